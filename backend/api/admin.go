@@ -0,0 +1,266 @@
+package api
+
+import (
+	"crypto/subtle"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminAuth 校验管理接口的 Bearer 令牌,未配置 ADMIN_TOKEN 时拒绝所有请求
+func adminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := config.AppConfig.AdminToken
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.APIResponse{
+				Success: false,
+				Error:   "管理接口未启用",
+			})
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIResponse{
+				Success: false,
+				Error:   "管理令牌无效",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetMonitorOverrides 获取所有监控项展示覆盖
+func GetMonitorOverrides(c *gin.Context) {
+	overrides, err := database.GetMonitorOverrides()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取展示覆盖失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    overrides,
+	})
+}
+
+// PutMonitorOverride 设置监控项的展示名称、分组、顺序、图标和链接
+func PutMonitorOverride(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+
+	var override models.MonitorOverride
+	if err := c.ShouldBindJSON(&override); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	override.MonitorID = id
+	override.DisplayName = strings.TrimSpace(override.DisplayName)
+	override.GroupName = strings.TrimSpace(override.GroupName)
+	override.Icon = strings.TrimSpace(override.Icon)
+	override.Link = strings.TrimSpace(override.Link)
+
+	if msg := validateMonitorOverride(&override); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if _, err := database.GetMonitorByID(id); err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "监控项不存在",
+		})
+		return
+	}
+
+	if err := database.SaveMonitorOverride(&override); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存展示覆盖失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &override,
+	})
+}
+
+// DeleteMonitorOverride 删除监控项展示覆盖,恢复 Kuma 的原始值
+func DeleteMonitorOverride(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+
+	override, err := database.GetMonitorOverride(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "展示覆盖不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteMonitorOverride(id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除展示覆盖失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    override,
+	})
+}
+
+// GetGroupOverrides 获取所有分组顺序覆盖
+func GetGroupOverrides(c *gin.Context) {
+	overrides, err := database.GetGroupOverrides()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取分组覆盖失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    overrides,
+	})
+}
+
+// PutGroupOverride 设置分组的展示顺序
+// 分组名可以是 Kuma 中的分组,也可以是展示覆盖中新建的分组
+func PutGroupOverride(c *gin.Context) {
+	var override models.GroupOverride
+	if err := c.ShouldBindJSON(&override); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	override.GroupName = strings.TrimSpace(override.GroupName)
+	if override.GroupName == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "分组名不能为空",
+		})
+		return
+	}
+	if len(override.GroupName) > 100 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "分组名过长",
+		})
+		return
+	}
+
+	if err := database.SaveGroupOverride(&override); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存分组覆盖失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &override,
+	})
+}
+
+// DeleteGroupOverride 删除分组顺序覆盖,恢复 Kuma 中的分组顺序
+func DeleteGroupOverride(c *gin.Context) {
+	group := strings.TrimSpace(c.Query("group"))
+	if group == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "缺少 group 参数",
+		})
+		return
+	}
+
+	override, err := database.GetGroupOverride(group)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "分组覆盖不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteGroupOverride(group); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除分组覆盖失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    override,
+	})
+}
+
+// validateMonitorOverride 校验展示覆盖的字段长度和链接格式,返回错误信息
+func validateMonitorOverride(override *models.MonitorOverride) string {
+	if len(override.DisplayName) > 255 {
+		return "展示名称过长"
+	}
+	if len(override.GroupName) > 100 {
+		return "分组名过长"
+	}
+	if len(override.Icon) > 500 || len(override.Link) > 500 {
+		return "图标或链接过长"
+	}
+	// 链接会直接渲染到页面上,只允许 http(s)
+	for _, value := range []string{override.Icon, override.Link} {
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "图标和链接必须是 http(s) 地址"
+		}
+	}
+	return ""
+}
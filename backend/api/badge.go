@@ -0,0 +1,359 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 徽章颜色(与 shields.io 命名颜色一致)
+const (
+	badgeColorGreen  = "#4c1"
+	badgeColorYellow = "#dfb317"
+	badgeColorOrange = "#fe7d37"
+	badgeColorRed    = "#e05d44"
+	badgeColorBlue   = "#007ec6"
+	badgeColorGrey   = "#9f9f9f"
+)
+
+// badge 徽章内容
+type badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// badgeError 生成徽章失败时的错误,仍以灰色徽章的形式输出
+type badgeError struct {
+	status  int
+	message string
+}
+
+// shieldsEndpoint shields.io endpoint 徽章格式
+type shieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	CacheSeconds  int    `json:"cacheSeconds"`
+	IsError       bool   `json:"isError,omitempty"`
+}
+
+// GetBadge 输出监控项徽章
+// 路径形如 /badge/:id/status.svg,扩展名为 .json 时输出 shields.io endpoint 格式
+func GetBadge(c *gin.Context) {
+	file := c.Param("file")
+	kind, ext, ok := strings.Cut(file, ".")
+	if !ok || (ext != "svg" && ext != "json") {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "不支持的徽章类型",
+		})
+		return
+	}
+
+	maxAge := int(config.AppConfig.CacheDuration.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+
+	status := http.StatusOK
+	b, err := buildBadge(c, kind)
+	if err != nil {
+		status = err.status
+		b = &badge{Label: kind, Message: err.message, Color: badgeColorGrey}
+	}
+	if label, ok := c.GetQuery("label"); ok {
+		b.Label = label
+	}
+
+	if ext == "json" {
+		c.JSON(status, shieldsEndpoint{
+			SchemaVersion: 1,
+			Label:         b.Label,
+			Message:       b.Message,
+			Color:         b.Color,
+			CacheSeconds:  maxAge,
+			IsError:       status != http.StatusOK,
+		})
+		return
+	}
+
+	svg, renderErr := renderBadgeSVG(b, c.DefaultQuery("style", "flat"))
+	if renderErr != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "生成徽章失败",
+		})
+		return
+	}
+	c.Data(status, "image/svg+xml; charset=utf-8", svg)
+}
+
+// buildBadge 根据徽章类型计算内容
+func buildBadge(c *gin.Context, kind string) (*badge, *badgeError) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, &badgeError{http.StatusBadRequest, "invalid id"}
+	}
+	monitor, err := database.GetMonitorByID(id)
+	if err != nil {
+		return nil, &badgeError{http.StatusNotFound, "not found"}
+	}
+
+	switch kind {
+	case "status":
+		return statusBadge(monitor), nil
+	case "uptime":
+		window, label, err := badgeWindow(c, "24h")
+		if err != nil {
+			return nil, err
+		}
+		summary, summaryErr := badgeUptimeSummary(monitor.ID, window, label)
+		if summaryErr != nil {
+			return nil, &badgeError{http.StatusInternalServerError, "unavailable"}
+		}
+		b := &badge{Label: "uptime " + label, Message: "no data", Color: badgeColorGrey}
+		if summary.Uptime >= 0 {
+			percent := summary.Uptime * 100
+			b.Message = formatPercent(percent)
+			b.Color = thresholdColor(percent, queryFloat(c, "warn", 99), queryFloat(c, "crit", 95), false)
+		}
+		return b, nil
+	case "response":
+		b := &badge{Label: "response", Message: "no data", Color: badgeColorGrey}
+		// 未指定窗口时使用最近一次心跳的响应时间,已归档的监控项没有当前响应时间
+		if c.Query("window") == "" {
+			if monitor.Status == models.StatusUp && !monitor.RemovedAt.Valid {
+				b.Message = fmt.Sprintf("%dms", monitor.ResponseTime)
+				b.Color = thresholdColor(float64(monitor.ResponseTime), queryFloat(c, "warn", 500), queryFloat(c, "crit", 1000), true)
+			}
+			return b, nil
+		}
+		window, label, err := badgeWindow(c, "24h")
+		if err != nil {
+			return nil, err
+		}
+		summary, summaryErr := badgeUptimeSummary(monitor.ID, window, label)
+		if summaryErr != nil {
+			return nil, &badgeError{http.StatusInternalServerError, "unavailable"}
+		}
+		b.Label = "response " + label
+		if summary.Up > 0 {
+			b.Message = fmt.Sprintf("%.0fms", summary.AvgResponseTime)
+			b.Color = thresholdColor(summary.AvgResponseTime, queryFloat(c, "warn", 500), queryFloat(c, "crit", 1000), true)
+		}
+		return b, nil
+	default:
+		return nil, &badgeError{http.StatusNotFound, "unknown badge"}
+	}
+}
+
+// statusBadge 当前状态徽章,已归档的监控项不再有当前状态
+func statusBadge(monitor *models.Monitor) *badge {
+	b := &badge{Label: "status"}
+	if monitor.RemovedAt.Valid {
+		b.Message, b.Color = "archived", badgeColorGrey
+		return b
+	}
+	switch monitor.EffectiveStatus {
+	case models.StatusUp:
+		b.Message, b.Color = "up", badgeColorGreen
+	case models.StatusDegraded:
+		b.Message, b.Color = "degraded", badgeColorYellow
+	case models.StatusPending:
+		b.Message, b.Color = "pending", badgeColorOrange
+	case models.StatusMaintenance:
+		b.Message, b.Color = "maintenance", badgeColorBlue
+	default:
+		b.Message, b.Color = "down", badgeColorRed
+	}
+	return b
+}
+
+// badgeSummaryCache 徽章使用的可用率汇总缓存
+var badgeSummaryCache = cache.NewTyped[*models.UptimeSummary]("badge_summary")
+
+// badgeUptimeSummary 统计窗口内的可用率,结果缓存60秒,新的获取周期开始后失效
+func badgeUptimeSummary(monitorID int, window time.Duration, label string) (*models.UptimeSummary, error) {
+	cacheKey := strconv.Itoa(monitorID) + "_" + label
+	return badgeSummaryCache.GetOrLoad(cacheKey, 60*time.Second, []string{cache.TagFetch},
+		func() (*models.UptimeSummary, error) {
+			return database.GetUptimeSummary(monitorID, time.Now().Add(-window))
+		})
+}
+
+// badgeWindow 解析 window 参数,返回时长和规范化后的标签
+func badgeWindow(c *gin.Context, defaultValue string) (time.Duration, string, *badgeError) {
+	value := c.DefaultQuery("window", defaultValue)
+	window, err := parseWindow(value)
+	if err != nil {
+		return 0, "", &badgeError{http.StatusBadRequest, "invalid window"}
+	}
+	// 超出数据保留期的窗口无法得到准确结果
+	maxWindow := time.Duration(config.AppConfig.DataRetentionDays) * 24 * time.Hour
+	if window > maxWindow {
+		return 0, "", &badgeError{http.StatusBadRequest, "window too large"}
+	}
+	return window, value, nil
+}
+
+// parseWindow 解析时间窗口,支持 h(小时)、d(天)、w(周) 后缀,如 24h、30d
+func parseWindow(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("无效的时间窗口: %q", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("无效的时间窗口: %q", value)
+	}
+	switch value[len(value)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("无效的时间窗口: %q", value)
+	}
+}
+
+// thresholdColor 根据阈值选择颜色,lowerIsBetter 表示数值越小越好(如响应时间)
+func thresholdColor(value, warn, crit float64, lowerIsBetter bool) string {
+	if lowerIsBetter {
+		switch {
+		case value >= crit:
+			return badgeColorRed
+		case value >= warn:
+			return badgeColorYellow
+		default:
+			return badgeColorGreen
+		}
+	}
+	switch {
+	case value < crit:
+		return badgeColorRed
+	case value < warn:
+		return badgeColorYellow
+	default:
+		return badgeColorGreen
+	}
+}
+
+// formatPercent 格式化百分比,去掉多余的小数位
+func formatPercent(percent float64) string {
+	s := strconv.FormatFloat(percent, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
+
+// queryFloat 解析浮点型查询参数
+func queryFloat(c *gin.Context, key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(c.Query(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// badgeLayout 徽章 SVG 模板参数
+type badgeLayout struct {
+	Label, Message, Color    string
+	Width, Height            int
+	LabelWidth, MessageWidth int
+	LabelX, MessageX         float64
+	TextY                    int
+}
+
+var badgeTemplates = map[string]*template.Template{
+	"flat": template.Must(template.New("flat").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" role="img" aria-label="{{.Label}}: {{.Message}}"><title>{{.Label}}: {{.Message}}</title>` +
+		`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="{{.Height}}" rx="3" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="{{.Height}}" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="{{.Height}}" fill="{{.Color}}"/><rect width="{{.Width}}" height="{{.Height}}" fill="url(#s)"/></g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="{{.LabelX}}" y="{{.TextY}}" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="{{.TextY}}" dy="-1">{{.Label}}</text>` +
+		`<text x="{{.MessageX}}" y="{{.TextY}}" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageX}}" y="{{.TextY}}" dy="-1">{{.Message}}</text></g></svg>`)),
+	"plastic": template.Must(template.New("plastic").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" role="img" aria-label="{{.Label}}: {{.Message}}"><title>{{.Label}}: {{.Message}}</title>` +
+		`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#fff" stop-opacity=".7"/><stop offset=".1" stop-color="#aaa" stop-opacity=".1"/><stop offset=".9" stop-color="#000" stop-opacity=".3"/><stop offset="1" stop-color="#000" stop-opacity=".5"/></linearGradient>` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="{{.Height}}" rx="4" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="{{.Height}}" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="{{.Height}}" fill="{{.Color}}"/><rect width="{{.Width}}" height="{{.Height}}" fill="url(#s)"/></g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="{{.LabelX}}" y="{{.TextY}}" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="{{.TextY}}" dy="-1">{{.Label}}</text>` +
+		`<text x="{{.MessageX}}" y="{{.TextY}}" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageX}}" y="{{.TextY}}" dy="-1">{{.Message}}</text></g></svg>`)),
+	"for-the-badge": template.Must(template.New("for-the-badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" role="img" aria-label="{{.Label}}: {{.Message}}"><title>{{.Label}}: {{.Message}}</title>` +
+		`<g shape-rendering="crispEdges"><rect width="{{.LabelWidth}}" height="{{.Height}}" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="{{.Height}}" fill="{{.Color}}"/></g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="10" letter-spacing="1">` +
+		`<text x="{{.LabelX}}" y="{{.TextY}}">{{.Label}}</text><text x="{{.MessageX}}" y="{{.TextY}}" font-weight="bold">{{.Message}}</text></g></svg>`)),
+}
+
+// renderBadgeSVG 按样式渲染 SVG 徽章
+func renderBadgeSVG(b *badge, style string) ([]byte, error) {
+	tmpl, ok := badgeTemplates[style]
+	if !ok {
+		style = "flat"
+		tmpl = badgeTemplates[style]
+	}
+
+	label, message := b.Label, b.Message
+	layout := badgeLayout{Color: b.Color, Height: 20, TextY: 15}
+	padding := 10
+	switch style {
+	case "plastic":
+		layout.Height, layout.TextY = 18, 14
+	case "for-the-badge":
+		label, message = strings.ToUpper(label), strings.ToUpper(message)
+		layout.Height, layout.TextY = 28, 18
+		padding = 24
+	}
+
+	layout.LabelWidth = textWidth(label, style) + padding
+	layout.MessageWidth = textWidth(message, style) + padding
+	layout.Width = layout.LabelWidth + layout.MessageWidth
+	layout.LabelX = float64(layout.LabelWidth) / 2
+	layout.MessageX = float64(layout.LabelWidth) + float64(layout.MessageWidth)/2
+	layout.Label = html.EscapeString(label)
+	layout.Message = html.EscapeString(message)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, layout); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// textWidth 估算 11px Verdana 下的文字宽度
+func textWidth(text string, style string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r > unicode.MaxLatin1:
+			width += 11
+		case strings.ContainsRune("ijl.,:;!'|", r):
+			width += 3.5
+		case strings.ContainsRune("frtI ", r):
+			width += 4.5
+		case strings.ContainsRune("mwMW%", r):
+			width += 10
+		case unicode.IsUpper(r):
+			width += 7.5
+		default:
+			width += 7
+		}
+	}
+	if style == "for-the-badge" {
+		// 10px 字号加 1px 字间距
+		width = width*10/11 + float64(len([]rune(text)))
+	}
+	return int(width + 0.5)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1h", time.Hour, false},
+		{"", 0, true},
+		{"h", 0, true},
+		{"0d", 0, true},
+		{"-1d", 0, true},
+		{"30m", 0, true},
+		{"1.5d", 0, true},
+		{"d7", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWindow(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseWindow(%q) = %v, %v,应为 %v,出错 %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestThresholdColor(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         float64
+		warn, crit    float64
+		lowerIsBetter bool
+		want          string
+	}{
+		{"可用率高于警告", 99.95, 99.9, 99, false, badgeColorGreen},
+		{"可用率等于警告", 99.9, 99.9, 99, false, badgeColorGreen},
+		{"可用率低于警告", 99.5, 99.9, 99, false, badgeColorYellow},
+		{"可用率等于严重", 99, 99.9, 99, false, badgeColorYellow},
+		{"可用率低于严重", 98, 99.9, 99, false, badgeColorRed},
+		{"响应时间低于警告", 100, 500, 1000, true, badgeColorGreen},
+		{"响应时间等于警告", 500, 500, 1000, true, badgeColorYellow},
+		{"响应时间等于严重", 1000, 500, 1000, true, badgeColorRed},
+	}
+	for _, tt := range tests {
+		if got := thresholdColor(tt.value, tt.warn, tt.crit, tt.lowerIsBetter); got != tt.want {
+			t.Errorf("%s: thresholdColor(%v) = %s,应为 %s", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		style string
+		want  int
+	}{
+		{"", "flat", 0},
+		{"up", "flat", 14},
+		{"il", "flat", 7},
+		{"MW", "flat", 20},
+		{"Up", "flat", 15},
+		{"状态", "flat", 22},
+		{"up", "for-the-badge", 15}, // 14*10/11 + 2 个字符的字间距
+	}
+	for _, tt := range tests {
+		if got := textWidth(tt.text, tt.style); got != tt.want {
+			t.Errorf("textWidth(%q, %q) = %d,应为 %d", tt.text, tt.style, got, tt.want)
+		}
+	}
+}
+
+// TestArchivedMonitorBadge 已归档的监控项 status 徽章为灰色 archived,不再显示归档前的状态
+func TestArchivedMonitorBadge(t *testing.T) {
+	router := setupTestAPI(t)
+	seedSchemaData(t)
+	if err := database.DB.Delete(&models.Monitor{}, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target  string
+		message string
+		color   string
+	}{
+		{"/badge/1/status.json", "up", badgeColorGreen},
+		{"/badge/2/status.json", "archived", badgeColorGrey},
+		{"/badge/1/response.json", "120ms", badgeColorGreen},
+		{"/badge/2/response.json", "no data", badgeColorGrey},
+	}
+	for _, tt := range tests {
+		w := doRequest(router, http.MethodGet, tt.target, "", false)
+		var got shieldsEndpoint
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("%s: 返回 %d: %s", tt.target, w.Code, w.Body.String())
+		}
+		if got.Message != tt.message || got.Color != tt.color {
+			t.Errorf("%s: 徽章为 %s %s,应为 %s %s", tt.target, got.Message, got.Color, tt.message, tt.color)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// certificateMaxDays days 参数上限
+const certificateMaxDays = 3650
+
+// GetCertificates 获取 HTTPS 监控项的证书到期情况,按剩余天数升序
+// 剩余天数不超过 days 或证书无效时标记为 expiring
+func GetCertificates(c *gin.Context) {
+	days := parseBoundedInt(c.Query("days"), 30, certificateMaxDays)
+
+	cacheKey := "certificates_" + strconv.Itoa(days)
+	respondCached(c, cacheKey, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取证书信息失败",
+		func() (interface{}, *models.Pagination, error) {
+			monitors, err := database.GetCertificates()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			certificates := make([]models.CertificateStatus, 0, len(monitors))
+			for _, monitor := range monitors {
+				valid := monitor.CertValid == nil || *monitor.CertValid
+				certificates = append(certificates, models.CertificateStatus{
+					MonitorID:     monitor.ID,
+					Name:          monitor.Name,
+					Group:         monitor.Group,
+					URL:           monitor.URL,
+					DaysRemaining: *monitor.CertExpiryDays,
+					Valid:         valid,
+					Expiring:      !valid || *monitor.CertExpiryDays <= days,
+				})
+			}
+			return certificates, nil, nil
+		})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"testing"
+)
+
+// TestCertificatesExpiringCutoff 剩余天数不超过 days 或证书无效时标记为 expiring,days 缺省为 30
+func TestCertificatesExpiringCutoff(t *testing.T) {
+	router := setupTestAPI(t)
+	valid, invalid := true, false
+	certificates := []struct {
+		days  int
+		valid *bool
+	}{
+		{5, &valid}, {30, &valid}, {31, &valid}, {200, &invalid}, {400, nil},
+	}
+	for i, cert := range certificates {
+		days := cert.days
+		monitor := &models.Monitor{ID: i + 1, Name: "证书", Type: "http", CertExpiryDays: &days, CertValid: cert.valid}
+		if err := database.SaveMonitor(monitor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.SaveMonitor(&models.Monitor{ID: 10, Name: "无证书", Type: "port"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  map[int]bool // 剩余天数 -> expiring
+	}{
+		{"", map[int]bool{5: true, 30: true, 31: false, 200: true, 400: false}},
+		{"?days=7", map[int]bool{5: true, 30: false, 31: false, 200: true, 400: false}},
+		{"?days=31", map[int]bool{5: true, 30: true, 31: true, 200: true, 400: false}},
+		{"?days=0", map[int]bool{5: true, 30: true, 31: false, 200: true, 400: false}},
+		{"?days=99999", map[int]bool{5: true, 30: true, 31: true, 200: true, 400: true}},
+	}
+	for _, tt := range tests {
+		w := doRequest(router, http.MethodGet, "/api/certificates"+tt.query, "", false)
+		var resp struct {
+			Data []models.CertificateStatus `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("%s: 返回 %d: %s", tt.query, w.Code, w.Body.String())
+		}
+		if len(resp.Data) != len(tt.want) {
+			t.Fatalf("%s: 应返回 %d 个证书,实际 %d", tt.query, len(tt.want), len(resp.Data))
+		}
+		for i, cert := range resp.Data {
+			if i > 0 && cert.DaysRemaining < resp.Data[i-1].DaysRemaining {
+				t.Errorf("%s: 证书应按剩余天数升序", tt.query)
+			}
+			if cert.Expiring != tt.want[cert.DaysRemaining] {
+				t.Errorf("%s: 剩余 %d 天的证书 expiring = %v", tt.query, cert.DaysRemaining, cert.Expiring)
+			}
+		}
+	}
+}
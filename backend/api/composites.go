@@ -0,0 +1,186 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compositeMaxMembers 单个组合监控项的最大成员数
+const compositeMaxMembers = 100
+
+// GetComposites 获取组合监控项定义
+func GetComposites(c *gin.Context) {
+	respondCached(c, "composites", config.AppConfig.CacheDuration, []string{cache.TagMonitors}, "获取组合监控项失败",
+		func() (interface{}, *models.Pagination, error) {
+			composites, err := database.GetComposites()
+			return composites, nil, err
+		})
+}
+
+// PostComposite 创建组合监控项
+func PostComposite(c *gin.Context) {
+	saveComposite(c, nil)
+}
+
+// PutComposite 更新组合监控项定义,心跳历史保留
+func PutComposite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+	existing, err := database.GetComposite(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "组合监控项不存在",
+		})
+		return
+	}
+	saveComposite(c, existing)
+}
+
+// DeleteComposite 删除组合监控项及其心跳历史
+func DeleteComposite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+
+	composite, err := database.GetComposite(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "组合监控项不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteComposite(id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除组合监控项失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagFetch, cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    composite,
+	})
+}
+
+// saveComposite 解析、校验并保存组合监控项,existing 为 nil 时新建
+func saveComposite(c *gin.Context, existing *models.CompositeMonitor) {
+	var composite models.CompositeMonitor
+	if err := c.ShouldBindJSON(&composite); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	composite.ID = 0
+	if existing != nil {
+		composite.ID = existing.ID
+		composite.CreatedAt = existing.CreatedAt
+	}
+	composite.Name = strings.TrimSpace(composite.Name)
+	composite.Group = strings.TrimSpace(composite.Group)
+
+	if msg := validateComposite(&composite); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := database.SaveComposite(&composite); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存组合监控项失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagFetch, cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &composite,
+	})
+}
+
+// validateComposite 校验规则、阈值和成员,返回错误信息
+// 成员必须是未归档的 Kuma 监控项,组合监控项不能嵌套
+func validateComposite(composite *models.CompositeMonitor) string {
+	if composite.Name == "" {
+		return "名称不能为空"
+	}
+	if len(composite.Name) > 255 || len(composite.Group) > 100 || len(composite.Description) > 1000 {
+		return "名称、分组或描述过长"
+	}
+	if len(composite.Members) == 0 {
+		return "至少需要一个成员"
+	}
+	if len(composite.Members) > compositeMaxMembers {
+		return "成员数量不能超过 " + strconv.Itoa(compositeMaxMembers)
+	}
+
+	switch composite.Rule {
+	case models.CompositeAll, models.CompositeAny:
+	case models.CompositeAtLeast:
+		if composite.Threshold != math.Trunc(composite.Threshold) ||
+			composite.Threshold < 1 || int(composite.Threshold) > len(composite.Members) {
+			return "at_least 规则的 threshold 需要是 1 到成员数量之间的整数"
+		}
+	case models.CompositeWeighted:
+		if composite.Threshold <= 0 || composite.Threshold > 1 {
+			return "weighted 规则的 threshold 需要在 0 到 1 之间"
+		}
+	default:
+		return "无效的规则,可用 all、any、at_least、weighted"
+	}
+
+	seen := make(map[int]bool, len(composite.Members))
+	ids := make([]int, 0, len(composite.Members))
+	for _, member := range composite.Members {
+		if seen[member.MonitorID] {
+			return "成员重复: " + strconv.Itoa(member.MonitorID)
+		}
+		seen[member.MonitorID] = true
+		ids = append(ids, member.MonitorID)
+	}
+
+	monitors, err := database.GetMonitorsByIDs(ids)
+	if err != nil {
+		return "查询成员失败"
+	}
+	for _, monitor := range monitors {
+		if monitor.Source == models.SourceKuma {
+			delete(seen, monitor.ID)
+		}
+	}
+	for _, member := range composite.Members {
+		if seen[member.MonitorID] {
+			return "成员不存在或不是 Kuma 监控项: " + strconv.Itoa(member.MonitorID)
+		}
+	}
+	return ""
+}
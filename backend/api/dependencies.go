@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDependencies 获取监控项依赖图,包括每个监控项考虑依赖后的状态
+func GetDependencies(c *gin.Context) {
+	respondCached(c, "dependencies", config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取依赖关系失败",
+		func() (interface{}, *models.Pagination, error) {
+			graph, err := database.GetDependencyGraph()
+			return graph, nil, err
+		})
+}
+
+// PostDependency 声明监控项依赖关系
+func PostDependency(c *gin.Context) {
+	var dependency models.MonitorDependency
+	if err := c.ShouldBindJSON(&dependency); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	dependency.ID = 0
+
+	for _, id := range []int{dependency.MonitorID, dependency.DependsOnID} {
+		if _, err := database.GetMonitorByID(id); err != nil {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "监控项不存在",
+			})
+			return
+		}
+	}
+
+	if err := database.AddDependency(&dependency); err != nil {
+		if errors.Is(err, database.ErrDependencySelf) || errors.Is(err, database.ErrDependencyCycle) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存依赖关系失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &dependency,
+	})
+}
+
+// DeleteDependency 删除依赖关系
+func DeleteDependency(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的依赖关系 ID",
+		})
+		return
+	}
+
+	dependency, err := database.GetDependency(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "依赖关系不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteDependency(id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除依赖关系失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    dependency,
+	})
+}
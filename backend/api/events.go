@@ -0,0 +1,129 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 事件和变更记录的查询范围
+const (
+	eventDefaultDays  = 7
+	eventMaxDays      = 90
+	eventDefaultLimit = 100
+	eventMaxLimit     = 500
+)
+
+// GetEvents 获取事件列表,包括监控项的新增、移除、恢复和配置变更
+func GetEvents(c *gin.Context) {
+	query := database.EventQuery{
+		Limit: parseBoundedInt(c.Query("limit"), eventDefaultLimit, eventMaxLimit),
+	}
+	days := parseBoundedInt(c.Query("days"), eventDefaultDays, eventMaxDays)
+	query.Since = time.Now().AddDate(0, 0, -days)
+
+	if kinds := c.Query("kind"); kinds != "" {
+		for _, kind := range strings.Split(kinds, ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				query.Kinds = append(query.Kinds, kind)
+			}
+		}
+	}
+	if monitorStr := c.Query("monitor"); monitorStr != "" {
+		monitorID, err := strconv.Atoi(monitorStr)
+		if err != nil || monitorID <= 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "无效的监控项 ID",
+			})
+			return
+		}
+		query.MonitorID = monitorID
+	}
+
+	// since 随时间变化,缓存键只包含规范化后的参数
+	params := url.Values{}
+	params.Set("days", strconv.Itoa(days))
+	params.Set("limit", strconv.Itoa(query.Limit))
+	params.Set("kind", strings.Join(query.Kinds, ","))
+	params.Set("monitor", strconv.Itoa(query.MonitorID))
+
+	respondCached(c, "events?"+params.Encode(), config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取事件失败",
+		func() (interface{}, *models.Pagination, error) {
+			events, err := database.GetEvents(query)
+			return events, nil, err
+		})
+}
+
+// GetMonitorChanges 获取监控项名称、分组、URL 和类型的变更记录
+func GetMonitorChanges(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+	limit := parseBoundedInt(c.Query("limit"), eventDefaultLimit, eventMaxLimit)
+
+	cacheKey := "changes_" + idStr + "_" + strconv.Itoa(limit)
+	respondCached(c, cacheKey, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取变更记录失败",
+		func() (interface{}, *models.Pagination, error) {
+			changes, err := database.GetMonitorChanges(id, limit)
+			return changes, nil, err
+		})
+}
+
+// GetMonitorAnomalies 获取监控项的响应时间异常
+func GetMonitorAnomalies(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+	days := parseBoundedInt(c.Query("days"), eventDefaultDays, eventMaxDays)
+	limit := parseBoundedInt(c.Query("limit"), eventDefaultLimit, eventMaxLimit)
+
+	cacheKey := "anomalies_" + idStr + "_" + strconv.Itoa(days) + "_" + strconv.Itoa(limit)
+	respondCached(c, cacheKey, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取响应时间异常失败",
+		func() (interface{}, *models.Pagination, error) {
+			anomalies, err := database.GetAnomalies(id, time.Now().AddDate(0, 0, -days), limit)
+			return anomalies, nil, err
+		})
+}
+
+// GetMonitorFlaps 获取监控项的状态抖动期间
+func GetMonitorFlaps(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的监控项 ID",
+		})
+		return
+	}
+	days := parseBoundedInt(c.Query("days"), eventDefaultDays, eventMaxDays)
+	limit := parseBoundedInt(c.Query("limit"), eventDefaultLimit, eventMaxLimit)
+
+	cacheKey := "flaps_" + idStr + "_" + strconv.Itoa(days) + "_" + strconv.Itoa(limit)
+	respondCached(c, cacheKey, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取抖动记录失败",
+		func() (interface{}, *models.Pagination, error) {
+			flaps, err := database.GetFlaps(id, time.Now().AddDate(0, 0, -days), limit)
+			return flaps, nil, err
+		})
+}
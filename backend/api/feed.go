@@ -0,0 +1,432 @@
+package api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	feedDefaultLimit = 50
+	feedMaxLimit     = 200
+	feedDefaultDays  = 7
+	feedMaxDays      = 90
+)
+
+// feedCache 渲染后的订阅源缓存
+var feedCache = cache.NewTyped[[]byte]("feed")
+
+// feedItem 与具体格式无关的订阅条目
+type feedItem struct {
+	ID        string
+	Title     string
+	Content   string
+	Link      string
+	Published time.Time
+	Updated   time.Time
+	Category  string
+}
+
+// feedData 订阅源的公共信息和条目
+type feedData struct {
+	ID      string
+	Title   string
+	Link    string
+	SelfURL string
+	Updated time.Time
+	Items   []feedItem
+}
+
+// GetAtomFeed Atom 订阅源
+func GetAtomFeed(c *gin.Context) {
+	serveFeed(c, "atom", "application/atom+xml; charset=utf-8", renderAtomFeed)
+}
+
+// GetRSSFeed RSS 2.0 订阅源
+func GetRSSFeed(c *gin.Context) {
+	serveFeed(c, "rss", "application/rss+xml; charset=utf-8", renderRSSFeed)
+}
+
+// GetJSONFeed JSON Feed 1.1 订阅源
+func GetJSONFeed(c *gin.Context) {
+	serveFeed(c, "json", "application/feed+json; charset=utf-8", renderJSONFeed)
+}
+
+// serveFeed 收集条目并按指定格式输出
+func serveFeed(c *gin.Context, format, contentType string, render func(*feedData) ([]byte, error)) {
+	group := c.Query("group")
+	monitorStr := c.Query("monitor")
+	limit := parseBoundedInt(c.Query("limit"), feedDefaultLimit, feedMaxLimit)
+	days := parseBoundedInt(c.Query("days"), feedDefaultDays, feedMaxDays)
+
+	var monitorID int
+	if monitorStr != "" {
+		id, err := strconv.Atoi(monitorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "无效的监控项 ID",
+			})
+			return
+		}
+		monitorID = id
+	}
+
+	// 订阅源缓存60秒,新的获取周期开始后失效
+	cacheKey := fmt.Sprintf("%s_%s_%s_%d_%d_%d", requestBaseURL(c), format, group, monitorID, limit, days)
+	body, err := feedCache.GetOrLoad(cacheKey, 60*time.Second, []string{cache.TagFetch, cache.TagMonitors},
+		func() ([]byte, error) {
+			feed, err := buildFeed(c, group, monitorID, limit, days)
+			if err != nil {
+				return nil, err
+			}
+			return render(feed)
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "生成订阅源失败",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// buildFeed 根据过滤条件汇总状态变化和公告
+func buildFeed(c *gin.Context, group string, monitorID, limit, days int) (*feedData, error) {
+	baseURL := requestBaseURL(c)
+	since := time.Now().AddDate(0, 0, -days)
+
+	monitors, err := database.GetAllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	monitorMap := make(map[int]models.Monitor, len(monitors))
+	var monitorIDs []int
+	for _, monitor := range monitors {
+		monitorMap[monitor.ID] = monitor
+		if monitorID != 0 && monitor.ID != monitorID {
+			continue
+		}
+		if group != "" && monitor.Group != group {
+			continue
+		}
+		monitorIDs = append(monitorIDs, monitor.ID)
+	}
+
+	filtered := group != "" || monitorID != 0
+	feed := &feedData{
+		ID:      "urn:kuma-lite:feed",
+		Title:   "Kuma-Lite 状态更新",
+		Link:    baseURL + "/",
+		SelfURL: baseURL + c.Request.URL.RequestURI(),
+	}
+	if group != "" {
+		feed.ID += ":group:" + group
+		feed.Title += " - " + group
+	}
+	if monitorID != 0 {
+		feed.ID += ":monitor:" + strconv.Itoa(monitorID)
+		if monitor, ok := monitorMap[monitorID]; ok {
+			feed.Title += " - " + monitor.Name
+		}
+	}
+
+	// 过滤后没有匹配的监控项时不查询状态变化,避免退化为不过滤
+	if !filtered || len(monitorIDs) > 0 {
+		transitions, err := database.GetStatusTransitions(monitorIDs, since, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, transition := range transitions {
+			feed.Items = append(feed.Items, transitionFeedItem(baseURL, transition, monitorMap[transition.MonitorID]))
+		}
+	}
+
+	// 公告针对整个状态页,仅在未过滤时输出
+	if !filtered {
+		announcements, err := database.GetAnnouncements(since, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, announcement := range announcements {
+			title := "公告: " + announcement.Title
+			if !announcement.Active {
+				title = "公告已结束: " + announcement.Title
+			}
+			feed.Items = append(feed.Items, feedItem{
+				ID:        "urn:kuma-lite:announcement:" + strconv.Itoa(announcement.ID),
+				Title:     title,
+				Content:   announcement.Content,
+				Link:      baseURL + "/",
+				Published: announcement.CreatedAt.UTC(),
+				Updated:   announcement.UpdatedAt.UTC(),
+				Category:  "announcement",
+			})
+		}
+	}
+
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].Updated.After(feed.Items[j].Updated)
+	})
+	if len(feed.Items) > limit {
+		feed.Items = feed.Items[:limit]
+	}
+
+	// 订阅源更新时间取最新条目,没有条目时取最新心跳
+	if len(feed.Items) > 0 {
+		feed.Updated = feed.Items[0].Updated
+	} else if latest, err := database.GetLatestHeartBeatTime(); err == nil {
+		feed.Updated = latest.UTC()
+	} else {
+		feed.Updated = time.Now().UTC()
+	}
+
+	return feed, nil
+}
+
+// transitionFeedItem 将状态变化转换为订阅条目
+func transitionFeedItem(baseURL string, transition models.StatusTransition, monitor models.Monitor) feedItem {
+	name := monitor.Name
+	if name == "" {
+		name = fmt.Sprintf("监控项 #%d", transition.MonitorID)
+	}
+
+	content := fmt.Sprintf("%s 状态从 %s 变为 %s。",
+		name, models.StatusText(transition.FromStatus), models.StatusText(transition.ToStatus))
+	if monitor.Group != "" {
+		content += fmt.Sprintf("\n分组: %s", monitor.Group)
+	}
+	if transition.ToStatus == models.StatusUp {
+		content += fmt.Sprintf("\n响应时间: %dms", transition.ResponseTime)
+	}
+	if transition.Message != "" {
+		content += "\n消息: " + transition.Message
+	}
+
+	updated := transition.CreatedAt.UTC()
+	return feedItem{
+		ID: fmt.Sprintf("urn:kuma-lite:monitor:%d:transition:%d",
+			transition.MonitorID, updated.UnixMilli()),
+		Title:     fmt.Sprintf("%s %s", name, models.StatusText(transition.ToStatus)),
+		Content:   content,
+		Link:      fmt.Sprintf("%s/detail.html?id=%d", baseURL, transition.MonitorID),
+		Published: updated,
+		Updated:   updated,
+		Category:  monitor.Group,
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Updated   string        `xml:"updated"`
+	Published string        `xml:"published"`
+	Link      atomLink      `xml:"link"`
+	Content   atomContent   `xml:"content"`
+	Category  *atomCategory `xml:"category,omitempty"`
+}
+
+// renderAtomFeed 输出 Atom 1.0
+func renderAtomFeed(feed *feedData) ([]byte, error) {
+	out := atomFeed{
+		XMLNS:   "http://www.w3.org/2005/Atom",
+		ID:      feed.ID,
+		Title:   feed.Title,
+		Updated: feed.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Author: atomAuthor{Name: "Kuma-Lite"},
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Updated:   item.Updated.Format(time.RFC3339),
+			Published: item.Published.Format(time.RFC3339),
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Content:   atomContent{Type: "text", Body: item.Content},
+		}
+		if item.Category != "" {
+			entry.Category = &atomCategory{Term: item.Category}
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+	return marshalXML(out)
+}
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XMLNSAtom string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category,omitempty"`
+}
+
+// renderRSSFeed 输出 RSS 2.0
+func renderRSSFeed(feed *feedData) ([]byte, error) {
+	out := rssFeed{
+		Version:   "2.0",
+		XMLNSAtom: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   "监控项状态变化与状态页公告",
+			LastBuildDate: feed.Updated.Format(time.RFC1123Z),
+			AtomLink:      atomLink{Href: feed.SelfURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	for _, item := range feed.Items {
+		out.Channel.Items = append(out.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Content,
+			GUID:        rssGUID{IsPermaLink: "false", Value: item.ID},
+			PubDate:     item.Updated.Format(time.RFC1123Z),
+			Category:    item.Category,
+		})
+	}
+	return marshalXML(out)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// renderJSONFeed 输出 JSON Feed 1.1
+func renderJSONFeed(feed *feedData) ([]byte, error) {
+	out := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.SelfURL,
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range feed.Items {
+		jsonItem := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   item.Content,
+			DatePublished: item.Published.Format(time.RFC3339),
+			DateModified:  item.Updated.Format(time.RFC3339),
+		}
+		if item.Category != "" {
+			jsonItem.Tags = []string{item.Category}
+		}
+		out.Items = append(out.Items, jsonItem)
+	}
+	return json.Marshal(out)
+}
+
+// marshalXML 序列化 XML 并加上声明头
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// requestBaseURL 根据请求推导站点根地址(兼容反向代理)
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// parseBoundedInt 解析正整数参数,非法时使用默认值,超过上限时截断
+func parseBoundedInt(value string, defaultValue, maxValue int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return defaultValue
+	}
+	if n > maxValue {
+		return maxValue
+	}
+	return n
+}
@@ -1,48 +1,56 @@
 package api
 
 import (
+	"encoding/base64"
+	"fmt"
 	"kuma-lite/backend/cache"
 	"kuma-lite/backend/config"
 	"kuma-lite/backend/database"
+	"kuma-lite/backend/fetcher"
 	"kuma-lite/backend/models"
+	"kuma-lite/backend/scheduler"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetMonitors 获取所有监控项
+// GetMonitors 获取监控项列表,支持过滤、排序和分页
 func GetMonitors(c *gin.Context) {
-	// 尝试从缓存获取
-	cacheKey := "monitors"
-	if cached, found := cache.Get(cacheKey); found {
-		c.JSON(http.StatusOK, models.APIResponse{
-			Success:   true,
-			Data:      cached,
-			Timestamp: time.Now(),
-		})
-		return
-	}
-
-	// 从数据库获取
-	monitors, err := database.GetAllMonitors()
+	query, err := parseMonitorQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Error:   "获取监控数据失败",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	// 存入缓存
-	cache.Set(cacheKey, monitors, config.AppConfig.CacheDuration)
+	// 不带参数时使用固定的缓存键,带参数时按规范化后的查询串区分
+	cacheKey := "monitors"
+	if params := c.Request.URL.Query(); len(params) > 0 {
+		cacheKey += "?" + params.Encode()
+	}
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Success:   true,
-		Data:      monitors,
-		Timestamp: time.Now(),
-	})
+	respondCached(c, cacheKey, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取监控数据失败",
+		func() (interface{}, *models.Pagination, error) {
+			monitors, total, err := database.QueryMonitors(query)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			meta := &models.Pagination{
+				Total:  total,
+				Limit:  query.Limit,
+				Offset: query.Offset,
+			}
+			if query.Limit > 0 && int64(query.Offset+len(monitors)) < total {
+				meta.NextCursor = encodeCursor(query.Offset + len(monitors))
+			}
+			return monitors, meta, nil
+		})
 }
 
 // GetMonitorByID 获取单个监控项
@@ -66,9 +74,20 @@ func GetMonitorByID(c *gin.Context) {
 		return
 	}
 
+	loc, err := requestLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	localizeTimes(monitor, loc)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    monitor,
+		Stale:   scheduler.IsStale(),
 	})
 }
 
@@ -88,8 +107,8 @@ func GetMonitorHistory(c *gin.Context) {
 	limitStr := c.Query("limit") // 限制条数(优先级高)
 	hoursStr := c.Query("hours") // 时间范围
 
-	var heartbeats []models.HeartBeat
 	var cacheKey string
+	var load func() ([]models.HeartBeat, error)
 
 	// 如果提供了 limit 参数,直接获取最近 N 条记录(不限制时间)
 	if limitStr != "" {
@@ -98,24 +117,9 @@ func GetMonitorHistory(c *gin.Context) {
 			limit = 100
 		}
 
-		cacheKey = "history_" + idStr + "_limit_" + limitStr
-
-		// 尝试从缓存获取
-		if cached, found := cache.Get(cacheKey); found {
-			c.JSON(http.StatusOK, models.APIResponse{
-				Success: true,
-				Data:    cached,
-			})
-			return
-		}
-
-		heartbeats, err = database.GetRecentHeartBeats(id, limit)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "获取历史数据失败",
-			})
-			return
+		cacheKey = "history_" + idStr + "_limit_" + strconv.Itoa(limit)
+		load = func() ([]models.HeartBeat, error) {
+			return database.GetRecentHeartBeats(id, limit)
 		}
 	} else {
 		// 使用 hours 参数(默认24小时)
@@ -128,69 +132,236 @@ func GetMonitorHistory(c *gin.Context) {
 		}
 
 		cacheKey = "history_" + idStr + "_" + strconv.Itoa(hours) + "h"
-
-		// 尝试从缓存获取
-		if cached, found := cache.Get(cacheKey); found {
-			c.JSON(http.StatusOK, models.APIResponse{
-				Success: true,
-				Data:    cached,
-			})
-			return
+		load = func() ([]models.HeartBeat, error) {
+			return database.GetHeartBeatHistory(id, hours)
 		}
+	}
 
-		heartbeats, err = database.GetHeartBeatHistory(id, hours)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "获取历史数据失败",
-			})
-			return
-		}
+	// 历史数据缓存30秒
+	respondCached(c, cacheKey, 30*time.Second, []string{cache.TagFetch}, "获取历史数据失败",
+		func() (interface{}, *models.Pagination, error) {
+			heartbeats, err := load()
+			return heartbeats, nil, err
+		})
+}
+
+// GetStats 获取统计信息
+func GetStats(c *gin.Context) {
+	respondCached(c, "stats", config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取统计信息失败",
+		func() (interface{}, *models.Pagination, error) {
+			stats, err := database.GetStats()
+			return stats, nil, err
+		})
+}
+
+// HealthCheck 健康检查,数据过期时返回 503 便于编排系统告警
+func HealthCheck(c *gin.Context) {
+	health := models.HealthStatus{
+		Status:  "healthy",
+		Message: "Service is healthy",
+	}
+	if last := scheduler.LastSuccessfulFetch(); !last.IsZero() {
+		health.LastSuccessAt = &last
 	}
 
-	// 存入缓存,历史数据缓存30秒
-	cache.Set(cacheKey, heartbeats, 30*time.Second)
+	if scheduler.IsStale() {
+		health.Status = "stale"
+		health.Message = "Monitor data is stale"
+		health.Stale = true
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Data:    health,
+			Error:   "数据已过期",
+			Stale:   true,
+		})
+		return
+	}
+	if health.LastSuccessAt == nil {
+		health.Status = "starting"
+		health.Message = "Waiting for first fetch"
+	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Data:    heartbeats,
+		Data:    health,
 	})
 }
 
-// GetStats 获取统计信息
-func GetStats(c *gin.Context) {
-	// 尝试从缓存获取
-	cacheKey := "stats"
-	if cached, found := cache.Get(cacheKey); found {
-		c.JSON(http.StatusOK, models.APIResponse{
-			Success: true,
-			Data:    cached,
+// recentFetchAttempts /api/health/source 返回的最近获取记录数量
+const recentFetchAttempts = 20
+
+// GetSourceHealth 获取数据源的健康状况
+func GetSourceHealth(c *gin.Context) {
+	attempts, err := database.GetRecentFetchAttempts(recentFetchAttempts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取数据源状态失败",
 		})
 		return
 	}
-
-	stats, err := database.GetStats()
+	failures, err := database.CountConsecutiveFetchFailures()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
-			Error:   "获取统计信息失败",
+			Error:   "获取数据源状态失败",
 		})
 		return
 	}
 
-	// 存入缓存
-	cache.Set(cacheKey, stats, config.AppConfig.CacheDuration)
+	health := models.SourceHealth{
+		Source:              models.SourceKuma,
+		ConsecutiveFailures: failures,
+		Stale:               scheduler.IsStale(),
+		DataAgeSeconds:      -1,
+		StaleThreshold:      config.AppConfig.StaleThreshold.Seconds(),
+		Timezone:            fetcher.SourceLocation().String(),
+		DroppedHeartbeats:   fetcher.DroppedHeartBeats(),
+		RecentAttempts:      attempts,
+	}
+	if len(attempts) > 0 {
+		health.LastAttempt = &attempts[0]
+	}
+	if breaker := fetcher.KumaBreakerState(); breaker.Open {
+		health.CircuitOpen = true
+		health.CircuitOpenUntil = &breaker.OpenUntil
+	}
+	if last := scheduler.LastSuccessfulFetch(); !last.IsZero() {
+		health.LastSuccessAt = &last
+		health.DataAgeSeconds = time.Since(last).Seconds()
+	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Data:    stats,
+		Data:    health,
+		Stale:   health.Stale,
 	})
 }
 
-// HealthCheck 健康检查
-func HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, models.APIResponse{
-		Success: true,
-		Data:    gin.H{"message": "Service is healthy"},
-	})
+// monitorListMaxLimit 单页最多返回的监控项数量
+const monitorListMaxLimit = 500
+
+// parseMonitorQuery 解析监控项列表的查询参数
+func parseMonitorQuery(c *gin.Context) (database.MonitorQuery, error) {
+	query := database.MonitorQuery{
+		Group:  c.Query("group"),
+		Type:   c.Query("type"),
+		Name:   c.Query("name"),
+		Source: c.Query("source"),
+		Sort:   c.Query("sort"),
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		for _, value := range strings.Split(statusStr, ",") {
+			status, ok := parseStatus(strings.TrimSpace(value))
+			if !ok {
+				return query, fmt.Errorf("无效的状态: %s", value)
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	switch include := c.Query("include"); include {
+	case "":
+	case "archived":
+		query.Archived = true
+	default:
+		return query, fmt.Errorf("无效的 include: %s", include)
+	}
+
+	for _, value := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				query.Tags = append(query.Tags, tag)
+			}
+		}
+	}
+
+	switch query.Sort {
+	case "", "id", "name", "uptime", "responseTime", "group":
+	default:
+		return query, fmt.Errorf("无效的排序字段: %s", query.Sort)
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("无效的排序方向: %s", c.Query("order"))
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return query, fmt.Errorf("无效的 limit: %s", limitStr)
+		}
+		if limit > monitorListMaxLimit {
+			limit = monitorListMaxLimit
+		}
+		query.Limit = limit
+	}
+
+	// cursor 优先于 offset
+	if cursor := c.Query("cursor"); cursor != "" {
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return query, fmt.Errorf("无效的 cursor")
+		}
+		query.Offset = offset
+	} else if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return query, fmt.Errorf("无效的 offset: %s", offsetStr)
+		}
+		query.Offset = offset
+	}
+	if query.Offset > 0 && query.Limit == 0 {
+		query.Limit = monitorListMaxLimit
+	}
+
+	return query, nil
+}
+
+// statusNames 状态名称,查询参数中可以使用名称或数字
+var statusNames = map[string]int{
+	"down":        models.StatusDown,
+	"up":          models.StatusUp,
+	"pending":     models.StatusPending,
+	"maintenance": models.StatusMaintenance,
+	"impacted":    models.StatusImpacted,
+	"degraded":    models.StatusDegraded,
+}
+
+// parseStatus 解析状态名称或数字
+func parseStatus(value string) (int, bool) {
+	if status, ok := statusNames[value]; ok {
+		return status, true
+	}
+	status, err := strconv.Atoi(value)
+	if err != nil || status < models.StatusDown || status > models.StatusDegraded {
+		return 0, false
+	}
+	return status, true
+}
+
+// encodeCursor 将偏移量编码为不透明的游标
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// decodeCursor 解析游标中的偏移量
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := strings.CutPrefix(string(raw), "offset:")
+	if !ok {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
 }
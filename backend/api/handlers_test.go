@@ -0,0 +1,33 @@
+package api
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int
+		wantOK bool
+	}{
+		{"down", models.StatusDown, true},
+		{"up", models.StatusUp, true},
+		{"pending", models.StatusPending, true},
+		{"maintenance", models.StatusMaintenance, true},
+		{"impacted", models.StatusImpacted, true},
+		{"degraded", models.StatusDegraded, true},
+		{"0", models.StatusDown, true},
+		{"4", models.StatusImpacted, true},
+		{"5", models.StatusDegraded, true},
+		{"6", 0, false},
+		{"-1", 0, false},
+		{"offline", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseStatus(tt.value)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseStatus(%q) = %d, %v,应为 %d, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
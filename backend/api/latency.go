@@ -0,0 +1,132 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLatencyThresholds 获取所有响应时间阈值
+func GetLatencyThresholds(c *gin.Context) {
+	thresholds, err := database.GetLatencyThresholds()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取响应时间阈值失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    thresholds,
+	})
+}
+
+// PutLatencyThreshold 设置监控项或分组的响应时间阈值
+func PutLatencyThreshold(c *gin.Context) {
+	var threshold models.LatencyThreshold
+	if err := c.ShouldBindJSON(&threshold); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	threshold.GroupName = strings.TrimSpace(threshold.GroupName)
+
+	if msg := validateLatencyThreshold(&threshold); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if threshold.MonitorID != 0 {
+		if _, err := database.GetMonitorByID(threshold.MonitorID); err != nil {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "监控项不存在",
+			})
+			return
+		}
+	}
+
+	if err := database.SaveLatencyThreshold(&threshold); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存响应时间阈值失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &threshold,
+	})
+}
+
+// DeleteLatencyThreshold 删除响应时间阈值
+func DeleteLatencyThreshold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的阈值 ID",
+		})
+		return
+	}
+
+	threshold, err := database.GetLatencyThreshold(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "响应时间阈值不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteLatencyThreshold(id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除响应时间阈值失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    threshold,
+	})
+}
+
+// validateLatencyThreshold 校验阈值目标和取值,返回错误信息
+func validateLatencyThreshold(threshold *models.LatencyThreshold) string {
+	if (threshold.MonitorID == 0) == (threshold.GroupName == "") {
+		return "monitorId 和 groupName 需要且只能设置一个"
+	}
+	if threshold.MonitorID < 0 {
+		return "无效的监控项 ID"
+	}
+	if len(threshold.GroupName) > 100 {
+		return "分组名过长"
+	}
+	if threshold.ThresholdMs < 0 {
+		return "thresholdMs 不能为负数"
+	}
+	if threshold.BaselineFactor != 0 && threshold.BaselineFactor <= 1 {
+		return "baselineFactor 需要大于 1"
+	}
+	if threshold.ThresholdMs == 0 && threshold.BaselineFactor == 0 {
+		return "thresholdMs 和 baselineFactor 至少设置一个"
+	}
+	return ""
+}
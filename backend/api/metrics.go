@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/fetcher"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/scheduler"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var metricsCache = cache.NewTyped[[]byte]("metrics")
+
+// metricLabelEscaper 转义 Prometheus 标签值中的反斜杠、双引号和换行
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// GetMetrics 以 Prometheus 文本格式输出监控项和 SLO 指标
+func GetMetrics(c *gin.Context) {
+	body, err := metricsCache.GetOrLoad("prometheus", config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors},
+		buildMetrics)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "生成指标失败",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", body)
+}
+
+// metricsWriter 按指标分组写入 HELP、TYPE 和样本
+type metricsWriter struct {
+	buf bytes.Buffer
+}
+
+// header 写入 gauge 指标的说明和类型
+func (w *metricsWriter) header(name, help string) {
+	w.typedHeader(name, help, "gauge")
+}
+
+// typedHeader 写入指定类型指标的说明和类型
+func (w *metricsWriter) typedHeader(name, help, metricType string) {
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sample 写入一个样本,labels 为键值交替的列表
+func (w *metricsWriter) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, `%s="%s"`, labels[i], metricLabelEscaper.Replace(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.buf.WriteByte('\n')
+}
+
+// buildMetrics 汇总监控项状态、SLO 和数据获取状态
+func buildMetrics() ([]byte, error) {
+	monitors, err := database.GetAllMonitors()
+	if err != nil {
+		return nil, err
+	}
+	reports, err := database.GetSLOReports(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &metricsWriter{}
+	monitorLabels := func(monitor *models.Monitor) []string {
+		return []string{"id", strconv.Itoa(monitor.ID), "name", monitor.Name, "group", monitor.Group}
+	}
+
+	w.header("kuma_lite_monitor_status", "Kuma 上报的状态: 0 离线, 1 正常, 2 重试中, 3 维护中")
+	for i := range monitors {
+		w.sample("kuma_lite_monitor_status", float64(monitors[i].Status), monitorLabels(&monitors[i])...)
+	}
+	w.header("kuma_lite_monitor_effective_status", "考虑依赖和响应时间阈值后的状态: 另有 4 受依赖影响, 5 响应缓慢")
+	for i := range monitors {
+		w.sample("kuma_lite_monitor_effective_status", float64(monitors[i].EffectiveStatus), monitorLabels(&monitors[i])...)
+	}
+	w.header("kuma_lite_monitor_flapping", "状态是否正在频繁切换")
+	for i := range monitors {
+		value := 0.0
+		if monitors[i].Flapping {
+			value = 1
+		}
+		w.sample("kuma_lite_monitor_flapping", value, monitorLabels(&monitors[i])...)
+	}
+	w.header("kuma_lite_monitor_response_time_ms", "最新响应时间(毫秒)")
+	for i := range monitors {
+		w.sample("kuma_lite_monitor_response_time_ms", float64(monitors[i].ResponseTime), monitorLabels(&monitors[i])...)
+	}
+	w.header("kuma_lite_monitor_uptime_ratio", "Kuma 统计的 24 小时可用率(0-1)")
+	for i := range monitors {
+		w.sample("kuma_lite_monitor_uptime_ratio", monitors[i].Uptime, monitorLabels(&monitors[i])...)
+	}
+
+	sloLabels := func(report *models.SLOReport) []string {
+		return []string{"id", strconv.Itoa(report.ID), "name", report.Name}
+	}
+	w.header("kuma_lite_slo_target_ratio", "SLO 目标(0-1)")
+	for i := range reports {
+		w.sample("kuma_lite_slo_target_ratio", reports[i].Target/100, sloLabels(&reports[i])...)
+	}
+	w.header("kuma_lite_slo_sli_ratio", "统计窗口内的 SLI(0-1),无数据时不输出")
+	for i := range reports {
+		if reports[i].SLI >= 0 {
+			w.sample("kuma_lite_slo_sli_ratio", reports[i].SLI/100, sloLabels(&reports[i])...)
+		}
+	}
+	w.header("kuma_lite_slo_error_budget_remaining_ratio", "剩余错误预算占比,超支时为负数")
+	for i := range reports {
+		w.sample("kuma_lite_slo_error_budget_remaining_ratio", reports[i].ErrorBudgetRemaining, sloLabels(&reports[i])...)
+	}
+	w.header("kuma_lite_slo_burn_rate", "错误预算燃烧率,1 表示恰好在统计窗口结束时用完")
+	for i := range reports {
+		for _, rate := range reports[i].BurnRates {
+			w.sample("kuma_lite_slo_burn_rate", rate.Rate, append(sloLabels(&reports[i]), "window", rate.Window)...)
+		}
+	}
+	w.header("kuma_lite_slo_alert_firing", "燃烧率告警是否正在触发")
+	for i := range reports {
+		firing := make(map[string]bool, len(reports[i].Alerts))
+		for _, alert := range reports[i].Alerts {
+			firing[alert.Rule] = true
+		}
+		for _, rule := range models.SLOBurnRateRules {
+			value := 0.0
+			if firing[rule.Name] {
+				value = 1
+			}
+			w.sample("kuma_lite_slo_alert_firing", value, append(sloLabels(&reports[i]), "rule", rule.Name)...)
+		}
+	}
+
+	w.header("kuma_lite_last_successful_fetch_timestamp_seconds", "最近一次成功从 Kuma 获取数据的时间,尚未成功时为 0")
+	lastFetch := 0.0
+	if last := scheduler.LastSuccessfulFetch(); !last.IsZero() {
+		lastFetch = float64(last.Unix())
+	}
+	w.sample("kuma_lite_last_successful_fetch_timestamp_seconds", lastFetch)
+
+	w.typedHeader("kuma_lite_dropped_heartbeats_total", "启动以来因时间无法解析而丢弃的心跳数", "counter")
+	w.sample("kuma_lite_dropped_heartbeats_total", float64(fetcher.DroppedHeartBeats()))
+
+	return w.buf.Bytes(), nil
+}
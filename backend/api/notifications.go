@@ -0,0 +1,454 @@
+package api
+
+import (
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/notify"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotificationChannels 获取所有通知渠道
+func GetNotificationChannels(c *gin.Context) {
+	channels, err := database.GetNotificationChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取通知渠道失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    channels,
+	})
+}
+
+// PostNotificationChannel 创建通知渠道
+func PostNotificationChannel(c *gin.Context) {
+	saveNotificationChannel(c, nil)
+}
+
+// PutNotificationChannel 更新通知渠道
+func PutNotificationChannel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的通知渠道 ID",
+		})
+		return
+	}
+	channel, err := database.GetNotificationChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "通知渠道不存在",
+		})
+		return
+	}
+	saveNotificationChannel(c, channel)
+}
+
+// DeleteNotificationChannel 删除通知渠道
+func DeleteNotificationChannel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的通知渠道 ID",
+		})
+		return
+	}
+	channel, err := database.GetNotificationChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "通知渠道不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteNotificationChannel(channel.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除通知渠道失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    channel,
+	})
+}
+
+// TestNotificationChannel 向通知渠道发送一条测试通知,停用的渠道也可以测试
+func TestNotificationChannel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的通知渠道 ID",
+		})
+		return
+	}
+	channel, err := database.GetNotificationChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "通知渠道不存在",
+		})
+		return
+	}
+
+	if err := notify.SendTest(channel); err != nil {
+		c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   "发送测试通知失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    channel,
+	})
+}
+
+// saveNotificationChannel 解析、校验并保存通知渠道,existing 为 nil 时新建
+func saveNotificationChannel(c *gin.Context, existing *models.NotificationChannel) {
+	var channel models.NotificationChannel
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	channel.ID = 0
+	if existing != nil {
+		channel.ID = existing.ID
+		channel.CreatedAt = existing.CreatedAt
+	}
+	channel.Name = strings.TrimSpace(channel.Name)
+	channel.WebhookURL = strings.TrimSpace(channel.WebhookURL)
+	for i := range channel.EmailTo {
+		channel.EmailTo[i] = strings.TrimSpace(channel.EmailTo[i])
+	}
+	if channel.Groups == nil {
+		channel.Groups = []string{}
+	}
+	if channel.MonitorIDs == nil {
+		channel.MonitorIDs = []int{}
+	}
+
+	if msg := validateNotificationChannel(&channel); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := database.SaveNotificationChannel(&channel); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存通知渠道失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &channel,
+	})
+}
+
+// validateNotificationChannel 校验通知渠道的类型、目标、过滤条件和模板,返回错误信息
+func validateNotificationChannel(channel *models.NotificationChannel) string {
+	if channel.Name == "" {
+		return "名称不能为空"
+	}
+	if len(channel.Name) > 255 || len(channel.Template) > 2000 {
+		return "名称或模板过长"
+	}
+	if !notify.SupportedChannel(channel.Type) {
+		return "不支持的渠道类型: " + channel.Type
+	}
+
+	switch channel.Type {
+	case models.ChannelSlack, models.ChannelDiscord, models.ChannelTeams:
+		u, err := url.Parse(channel.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(channel.WebhookURL) > 500 {
+			return "webhookUrl 需要是有效的 http(s) 地址"
+		}
+	case models.ChannelTelegram:
+		if channel.BotToken == "" || channel.ChatID == "" {
+			return "telegram 渠道需要设置 botToken 和 chatId"
+		}
+		if strings.ContainsAny(channel.BotToken, "/?#") {
+			return "无效的 botToken"
+		}
+	case models.ChannelEmail:
+		if !notify.SMTPConfigured() {
+			return "未配置 SMTP 服务器,不能使用邮件渠道"
+		}
+		if len(channel.EmailTo) == 0 {
+			return "email 渠道需要设置 emailTo"
+		}
+		for _, to := range channel.EmailTo {
+			if addr, err := mail.ParseAddress(to); err != nil || addr.Address != to {
+				return "无效的收件人地址: " + to
+			}
+		}
+	}
+
+	if channel.MinSeverity != "" && models.SeverityRank(channel.MinSeverity) == 0 {
+		return "minSeverity 需要是 minor、major 或 critical"
+	}
+	for _, id := range channel.MonitorIDs {
+		if id <= 0 {
+			return "无效的监控项 ID"
+		}
+	}
+	if channel.Template != "" {
+		if _, err := notify.ParseTemplate(channel.Template); err != nil {
+			return "模板格式错误: " + err.Error()
+		}
+	}
+	return validateQuietHours(channel)
+}
+
+// validateQuietHours 校验渠道的安静时段,未设置处理方式时默认延后发送
+func validateQuietHours(channel *models.NotificationChannel) string {
+	if (channel.QuietStart == "") != (channel.QuietEnd == "") {
+		return "quietStart 和 quietEnd 需要同时设置"
+	}
+	if channel.QuietStart != "" {
+		start, err := notify.ParseClock(channel.QuietStart)
+		if err != nil {
+			return "quietStart " + err.Error()
+		}
+		end, err := notify.ParseClock(channel.QuietEnd)
+		if err != nil {
+			return "quietEnd " + err.Error()
+		}
+		if start == end {
+			return "quietStart 和 quietEnd 不能相同"
+		}
+	}
+	if channel.QuietTimezone != "" {
+		if _, err := time.LoadLocation(channel.QuietTimezone); err != nil || len(channel.QuietTimezone) > 64 {
+			return "无效的时区: " + channel.QuietTimezone
+		}
+	}
+	switch channel.QuietMode {
+	case "":
+		channel.QuietMode = models.QuietDefer
+	case models.QuietDefer, models.QuietSuppress:
+	default:
+		return "quietMode 需要是 defer 或 suppress"
+	}
+	return ""
+}
+
+// GetNotificationRules 获取所有通知路由规则
+func GetNotificationRules(c *gin.Context) {
+	rules, err := database.GetNotificationRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取通知路由规则失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rules,
+	})
+}
+
+// PostNotificationRule 创建通知路由规则
+func PostNotificationRule(c *gin.Context) {
+	saveNotificationRule(c, nil)
+}
+
+// PutNotificationRule 更新通知路由规则
+func PutNotificationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的路由规则 ID",
+		})
+		return
+	}
+	rule, err := database.GetNotificationRule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "路由规则不存在",
+		})
+		return
+	}
+	saveNotificationRule(c, rule)
+}
+
+// DeleteNotificationRule 删除通知路由规则
+func DeleteNotificationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的路由规则 ID",
+		})
+		return
+	}
+	rule, err := database.GetNotificationRule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "路由规则不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteNotificationRule(rule.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除路由规则失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rule,
+	})
+}
+
+// saveNotificationRule 解析、校验并保存通知路由规则,existing 为 nil 时新建
+func saveNotificationRule(c *gin.Context, existing *models.NotificationRule) {
+	var rule models.NotificationRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	rule.ID = 0
+	if existing != nil {
+		rule.ID = existing.ID
+		rule.CreatedAt = existing.CreatedAt
+	}
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.MonitorPattern = strings.TrimSpace(rule.MonitorPattern)
+	rule.Group = strings.TrimSpace(rule.Group)
+	rule.Tag = strings.TrimSpace(rule.Tag)
+	if rule.EscalationChannelIDs == nil {
+		rule.EscalationChannelIDs = []int{}
+	}
+
+	if msg := validateNotificationRule(&rule); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := database.SaveNotificationRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存路由规则失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &rule,
+	})
+}
+
+// validateNotificationRule 校验路由规则的匹配条件、渠道和时长,返回错误信息
+func validateNotificationRule(rule *models.NotificationRule) string {
+	if rule.Name == "" {
+		return "名称不能为空"
+	}
+	if len(rule.Name) > 255 || len(rule.MonitorPattern) > 255 || len(rule.Group) > 100 || len(rule.Tag) > 100 {
+		return "名称或匹配条件过长"
+	}
+	if _, err := path.Match(rule.MonitorPattern, ""); err != nil {
+		return "monitorPattern 格式错误: " + err.Error()
+	}
+	if rule.MinSeverity != "" && models.SeverityRank(rule.MinSeverity) == 0 {
+		return "minSeverity 需要是 minor、major 或 critical"
+	}
+	if len(rule.ChannelIDs) == 0 {
+		return "channelIds 不能为空"
+	}
+	if rule.RepeatMinutes < 0 || rule.EscalateMinutes < 0 {
+		return "repeatMinutes 和 escalateMinutes 不能小于 0"
+	}
+	if rule.EscalateMinutes > 0 && len(rule.EscalationChannelIDs) == 0 {
+		return "设置 escalateMinutes 时需要设置 escalationChannelIds"
+	}
+	for _, id := range append(append([]int(nil), rule.ChannelIDs...), rule.EscalationChannelIDs...) {
+		if _, err := database.GetNotificationChannel(id); err != nil {
+			return "通知渠道不存在: " + strconv.Itoa(id)
+		}
+	}
+	return ""
+}
+
+// GetNotificationDeliveries 获取通知发送历史,可按渠道、故障事件和发送状态过滤
+func GetNotificationDeliveries(c *gin.Context) {
+	query := database.NotificationDeliveryQuery{
+		Status: c.Query("status"),
+		Limit:  parseBoundedInt(c.Query("limit"), eventDefaultLimit, eventMaxLimit),
+	}
+	days := parseBoundedInt(c.Query("days"), eventDefaultDays, eventMaxDays)
+	query.Since = time.Now().AddDate(0, 0, -days)
+
+	for name, field := range map[string]*int{"channel": &query.ChannelID, "incident": &query.IncidentID} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "无效的 " + name + " 参数",
+			})
+			return
+		}
+		*field = id
+	}
+
+	deliveries, err := database.GetNotificationDeliveries(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取通知发送历史失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    deliveries,
+	})
+}
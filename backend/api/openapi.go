@@ -0,0 +1,255 @@
+package api
+
+import (
+	"kuma-lite/backend/models"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// apiRoute /api 下的 JSON 接口定义,路由注册和 OpenAPI 文档共用同一份定义
+type apiRoute struct {
+	Method      string
+	Path        string // 相对 /api 的 gin 路径,如 /monitors/:id
+	Handler     gin.HandlerFunc
+	OperationID string
+	Summary     string
+	Params      []apiParam
+	Response    interface{} // data 字段的示例值,仅用于推导类型
+	Request     interface{} // JSON 请求体的示例值,仅用于推导类型,nil 表示没有请求体
+	Paginated   bool        // 响应是否带 meta 分页信息
+	Admin       bool        // 是否为需要 ADMIN_TOKEN 的管理接口
+	Errors      []int       // 可能返回的错误状态码
+}
+
+// apiParam 接口参数
+type apiParam struct {
+	Name        string
+	In          string // path 或 query
+	Type        string // integer, number, string, boolean
+	Description string
+	Required    bool
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPISpec map[string]interface{}
+)
+
+// GetOpenAPISpec 输出 OpenAPI 3 文档
+func GetOpenAPISpec(c *gin.Context) {
+	c.JSON(http.StatusOK, OpenAPISpec())
+}
+
+// OpenAPISpec 根据路由定义生成 OpenAPI 3 文档
+// 每个响应的 data 字段带有 x-go-type 扩展,供 client 包的代码生成使用
+func OpenAPISpec() map[string]interface{} {
+	openAPIOnce.Do(func() {
+		schemas := map[string]interface{}{}
+		envelope := schemaFor(reflect.TypeOf(models.APIResponse{}), schemas)
+		paths := map[string]interface{}{}
+
+		for _, route := range apiRoutes {
+			path := "/api" + openAPIPath(route.Path)
+			item, ok := paths[path].(map[string]interface{})
+			if !ok {
+				item = map[string]interface{}{}
+				paths[path] = item
+			}
+
+			dataType := reflect.TypeOf(route.Response)
+			dataSchema := schemaFor(dataType, schemas)
+			dataSchema["x-go-type"] = dataType.String()
+			properties := map[string]interface{}{"data": dataSchema}
+			if route.Paginated {
+				metaType := reflect.TypeOf(&models.Pagination{})
+				metaSchema := schemaFor(metaType, schemas)
+				metaSchema["x-go-type"] = metaType.String()
+				properties["meta"] = metaSchema
+			}
+
+			responses := map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "成功",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"allOf": []interface{}{
+									envelope,
+									map[string]interface{}{
+										"type":       "object",
+										"properties": properties,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			for _, code := range route.Errors {
+				responses[strconv.Itoa(code)] = map[string]interface{}{
+					"description": http.StatusText(code),
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": envelope},
+					},
+				}
+			}
+
+			params := make([]interface{}, 0, len(route.Params))
+			for _, param := range route.Params {
+				params = append(params, map[string]interface{}{
+					"name":        param.Name,
+					"in":          param.In,
+					"required":    param.Required || param.In == "path",
+					"description": param.Description,
+					"schema":      map[string]interface{}{"type": param.Type},
+				})
+			}
+
+			op := map[string]interface{}{
+				"operationId": route.OperationID,
+				"summary":     route.Summary,
+				"parameters":  params,
+				"responses":   responses,
+			}
+			if route.Request != nil {
+				requestType := reflect.TypeOf(route.Request)
+				requestSchema := schemaFor(requestType, schemas)
+				requestSchema["x-go-type"] = requestType.String()
+				op["requestBody"] = map[string]interface{}{
+					"required": true,
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": requestSchema},
+					},
+				}
+			}
+			if route.Admin {
+				op["security"] = []interface{}{map[string]interface{}{"adminToken": []string{}}}
+			}
+			item[strings.ToLower(route.Method)] = op
+		}
+
+		openAPISpec = map[string]interface{}{
+			"openapi": "3.0.3",
+			"info": map[string]interface{}{
+				"title":   "Kuma-Lite API",
+				"version": "1.0",
+			},
+			"paths": paths,
+			"components": map[string]interface{}{
+				"schemas": schemas,
+				"securitySchemes": map[string]interface{}{
+					"adminToken": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				},
+			},
+		}
+	})
+	return openAPISpec
+}
+
+// openAPIPath 将 gin 路径参数 :id 转换为 {id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// schemaFor 通过反射推导 JSON Schema,具名结构体放入 components/schemas 并返回引用
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaFor(t.Elem(), schemas)
+		if _, isRef := schema["$ref"]; !isRef {
+			schema["nullable"] = true
+		}
+		return schema
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		if t == deletedAtType {
+			// 序列化为时间或 null
+			return map[string]interface{}{"type": "string", "format": "date-time", "nullable": true}
+		}
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		if _, exists := schemas[t.Name()]; !exists {
+			// 先占位,防止自引用类型无限递归
+			schemas[t.Name()] = map[string]interface{}{}
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Uint:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema 按 json 标签生成结构体的 object schema
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type, schemas)
+			for key, value := range embedded["properties"].(map[string]interface{}) {
+				properties[key] = value
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, schemas)
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
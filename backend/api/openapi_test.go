@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/json"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminToken = "test-admin-token"
+
+// setupTestAPI 使用默认配置、临时数据库和内存缓存初始化接口,返回路由
+func setupTestAPI(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("KUMA_API_URL", "http://kuma.invalid")
+	t.Setenv("KUMA_STATUS_PAGE_SLUG", "test")
+	t.Setenv("ADMIN_TOKEN", testAdminToken)
+	config.LoadConfig()
+
+	if err := database.InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	t.Cleanup(func() { database.CloseDB() })
+	cache.InitCache(cache.NewMemoryStore(time.Minute, 2*time.Minute))
+
+	return SetupRouter()
+}
+
+// doRequest 发送请求,admin 为 true 时带上管理令牌
+func doRequest(router *gin.Engine, method, target, body string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if admin {
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestRoutesDocumented 每个 /api 路由都要出现在 OpenAPI 文档中,文档中也不能有已删除的路径
+func TestRoutesDocumented(t *testing.T) {
+	router := setupTestAPI(t)
+	paths := OpenAPISpec()["paths"].(map[string]interface{})
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		// Statuspage 兼容接口、文档本身和退订确认页不在 OpenAPI 文档中
+		if !strings.HasPrefix(route.Path, "/api/") || strings.HasPrefix(route.Path, "/api/v2/") || route.Path == "/api/openapi.json" ||
+			route.Method == http.MethodGet && route.Path == "/api/subscriptions/unsubscribe" {
+			continue
+		}
+		path := openAPIPath(route.Path)
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+
+		item, ok := paths[path].(map[string]interface{})
+		if !ok || item[method] == nil {
+			t.Errorf("路由 %s %s 没有出现在 OpenAPI 文档中", route.Method, route.Path)
+		}
+	}
+
+	for path, item := range paths {
+		for method := range item.(map[string]interface{}) {
+			if !registered[method+" "+path] {
+				t.Errorf("OpenAPI 文档中的 %s %s 没有注册路由", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestResponsesMatchSchema 实际接口响应的 data 和 meta 要符合 OpenAPI 文档中的 schema
+func TestResponsesMatchSchema(t *testing.T) {
+	router := setupTestAPI(t)
+	seedSchemaData(t)
+
+	spec := OpenAPISpec()
+	paths := spec["paths"].(map[string]interface{})
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	for _, route := range apiRoutes {
+		if route.Method != http.MethodGet {
+			continue
+		}
+		target := "/api" + strings.ReplaceAll(route.Path, ":id", "1")
+		if route.Paginated {
+			target += "?limit=1"
+		}
+
+		t.Run(route.OperationID, func(t *testing.T) {
+			w := doRequest(router, http.MethodGet, target, "", route.Admin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET %s 返回 %d: %s", target, w.Code, w.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+
+			op := paths["/api"+openAPIPath(route.Path)].(map[string]interface{})["get"].(map[string]interface{})
+			schema := op["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+			allOf := schema["allOf"].([]interface{})
+			checkSchema(t, "$", body, allOf[0].(map[string]interface{}), schemas)
+			properties := allOf[1].(map[string]interface{})["properties"].(map[string]interface{})
+			for name, property := range properties {
+				value, ok := body[name]
+				if !ok {
+					t.Errorf("响应缺少 %s 字段", name)
+					continue
+				}
+				checkSchema(t, name, value, property.(map[string]interface{}), schemas)
+			}
+		})
+	}
+}
+
+// seedSchemaData 写入监控项、心跳、故障事件和 SLO,让各接口返回非空数据
+func seedSchemaData(t *testing.T) {
+	t.Helper()
+	certDays := 20
+	certValid := true
+	monitors := []models.Monitor{
+		{
+			ID: 1, Name: "Web", Type: "http", URL: "https://example.com", Group: "应用", Status: models.StatusUp,
+			Uptime: 99.5, ResponseTime: 120, CertExpiryDays: &certDays, CertValid: &certValid,
+			Tags: []models.MonitorTag{{Name: "env", Value: "prod", Color: "#fff"}},
+		},
+		{ID: 2, Name: "DB", Type: "port", Group: "应用", Status: models.StatusDown},
+	}
+	for i := range monitors {
+		if err := database.SaveMonitor(&monitors[i]); err != nil {
+			t.Fatalf("保存监控项失败: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		for _, monitor := range monitors {
+			heartbeat := &models.HeartBeat{MonitorID: monitor.ID, Status: monitor.Status, ResponseTime: 100 + i, Message: "ok"}
+			if err := database.SaveHeartBeat(heartbeat); err != nil {
+				t.Fatalf("保存心跳失败: %v", err)
+			}
+		}
+	}
+	if _, err := database.SyncIncidents(monitors); err != nil {
+		t.Fatalf("同步故障事件失败: %v", err)
+	}
+	if err := database.SaveSLO(&models.SLO{Name: "Web 可用性", MonitorID: 1, Target: 99.9, WindowDays: 30}); err != nil {
+		t.Fatalf("保存 SLO 失败: %v", err)
+	}
+}
+
+// checkSchema 校验 JSON 值的类型和对象字段是否符合 schema,$ref 引用到 components/schemas 中解析
+// Go 的 nil 切片和 map 序列化为 null,数组和对象也允许为 null
+func checkSchema(t *testing.T, path string, value interface{}, schema map[string]interface{}, schemas map[string]interface{}) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		if value == nil {
+			return
+		}
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		resolved, ok := schemas[name].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: 未定义的 schema %s", path, ref)
+			return
+		}
+		checkSchema(t, path, value, resolved, schemas)
+		return
+	}
+
+	typ, _ := schema["type"].(string)
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); !nullable && typ != "array" && typ != "object" && typ != "" {
+			t.Errorf("%s: 为 null,但 schema 不允许", path)
+		}
+		return
+	}
+
+	switch typ {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: 应为 object,实际为 %T", path, value)
+			return
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for key, field := range object {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				checkSchema(t, path+"."+key, field, property, schemas)
+			} else if additional != nil {
+				checkSchema(t, path+"."+key, field, additional, schemas)
+			} else {
+				t.Errorf("%s: 字段 %s 没有出现在 schema 中", path, key)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			t.Errorf("%s: 应为 array,实际为 %T", path, value)
+			return
+		}
+		for i, item := range items {
+			checkSchema(t, path+"["+strconv.Itoa(i)+"]", item, schema["items"].(map[string]interface{}), schemas)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			t.Errorf("%s: 应为 string,实际为 %T", path, value)
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			t.Errorf("%s: 应为 integer,实际为 %v", path, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			t.Errorf("%s: 应为 number,实际为 %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Errorf("%s: 应为 boolean,实际为 %T", path, value)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 可靠性报告的默认和最大范围
+const (
+	reliabilityDefaultDays = 30
+	reliabilityMaxDays     = 366
+)
+
+var reliabilityCSVCache = cache.NewTyped[[]byte]("reliability_csv")
+
+// GetReliability 获取监控项和分组的 MTTR、MTBF、故障次数等可靠性指标
+// format=csv 时导出 CSV,监控项和分组各占一行
+func GetReliability(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	from, to, rangeKey, msg := reliabilityRange(c, loc)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	group := c.Query("group")
+	var monitorID int
+	if monitorStr := c.Query("monitor"); monitorStr != "" {
+		monitorID, err = strconv.Atoi(monitorStr)
+		if err != nil || monitorID <= 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "无效的监控项 ID",
+			})
+			return
+		}
+	}
+
+	params := url.Values{}
+	params.Set("range", rangeKey)
+	params.Set("group", group)
+	params.Set("monitor", strconv.Itoa(monitorID))
+	load := func() (*models.ReliabilityReport, error) {
+		return buildReliabilityReport(group, monitorID, from, to)
+	}
+
+	if c.Query("format") == "csv" {
+		params.Set("tz", loc.String())
+		body, err := reliabilityCSVCache.GetOrLoad(params.Encode(), config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors},
+			func() ([]byte, error) {
+				report, err := load()
+				if err != nil {
+					return nil, err
+				}
+				return renderReliabilityCSV(report, loc)
+			})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "生成可靠性报告失败",
+			})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="reliability.csv"`)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", body)
+		return
+	}
+
+	respondCached(c, "reliability?"+params.Encode(), config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "生成可靠性报告失败",
+		func() (interface{}, *models.Pagination, error) {
+			report, err := load()
+			return report, nil, err
+		})
+}
+
+// reliabilityRange 解析报告范围,返回 [from, to) 和用于缓存键的规范化表示
+// from、to 可以是 RFC 3339 时间或 YYYY-MM-DD 日期(按 loc 解析,to 包含当天);
+// 未指定 from 时取 to 之前 days 天,未指定 to 时取当前时间
+func reliabilityRange(c *gin.Context, loc *time.Location) (time.Time, time.Time, string, string) {
+	parse := func(value string, endOfDay bool) (time.Time, bool) {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+		t, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return time.Time{}, false
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true
+	}
+
+	to := time.Now()
+	toStr := c.Query("to")
+	if toStr != "" {
+		t, ok := parse(toStr, true)
+		if !ok {
+			return time.Time{}, time.Time{}, "", "无效的结束时间: " + toStr
+		}
+		to = t
+	}
+
+	days := parseBoundedInt(c.Query("days"), reliabilityDefaultDays, reliabilityMaxDays)
+	from := to.AddDate(0, 0, -days)
+	fromStr := c.Query("from")
+	if fromStr != "" {
+		t, ok := parse(fromStr, false)
+		if !ok {
+			return time.Time{}, time.Time{}, "", "无效的开始时间: " + fromStr
+		}
+		from = t
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, "", "结束时间需要晚于开始时间"
+	}
+	if to.Sub(from) > reliabilityMaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, "", "范围不能超过 " + strconv.Itoa(reliabilityMaxDays) + " 天"
+	}
+
+	// 未指定 to 时范围随时间变化,缓存键只包含参数
+	rangeKey := fromStr + "~" + toStr + "~" + strconv.Itoa(days)
+	if fromStr != "" || toStr != "" {
+		rangeKey += "~" + loc.String()
+	}
+	return from, to, rangeKey, ""
+}
+
+// buildReliabilityReport 按分组和监控项过滤后计算可靠性报告
+func buildReliabilityReport(group string, monitorID int, from, to time.Time) (*models.ReliabilityReport, error) {
+	monitors, err := database.GetAllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := monitors[:0]
+	for _, monitor := range monitors {
+		if group != "" && monitor.Group != group {
+			continue
+		}
+		if monitorID != 0 && monitor.ID != monitorID {
+			continue
+		}
+		filtered = append(filtered, monitor)
+	}
+	return database.GetReliabilityReport(filtered, from, to)
+}
+
+// renderReliabilityCSV 将报告输出为 CSV,带 UTF-8 BOM 以便表格软件识别中文
+func renderReliabilityCSV(report *models.ReliabilityReport, loc *time.Location) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	w := csv.NewWriter(&buf)
+
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	row := func(kind, id, name, group string, stats *models.ReliabilityStats) []string {
+		longestAt := ""
+		if stats.LongestAt != nil {
+			longestAt = stats.LongestAt.In(loc).Format(time.RFC3339)
+		}
+		return []string{
+			kind, id, name, group,
+			report.From.In(loc).Format(time.RFC3339), report.To.In(loc).Format(time.RFC3339),
+			formatFloat(stats.Availability),
+			strconv.FormatInt(stats.Uptime, 10), strconv.FormatInt(stats.Downtime, 10),
+			strconv.Itoa(stats.Failures), formatFloat(stats.MTTR), formatFloat(stats.MTBF),
+			strconv.FormatInt(stats.LongestOutage, 10), longestAt,
+			strconv.Itoa(stats.Flaps), formatFloat(stats.FlapsPerDay),
+		}
+	}
+
+	records := [][]string{{
+		"type", "id", "name", "group", "from", "to", "availability", "uptime", "downtime",
+		"failures", "mttr", "mtbf", "longestOutage", "longestOutageStartedAt", "flaps", "flapsPerDay",
+	}}
+	for i := range report.Groups {
+		group := &report.Groups[i]
+		records = append(records, row("group", "", group.Group, group.Group, &group.ReliabilityStats))
+	}
+	for i := range report.Monitors {
+		monitor := &report.Monitors[i]
+		records = append(records, row("monitor", strconv.Itoa(monitor.MonitorID), monitor.Name, monitor.Group, &monitor.ReliabilityStats))
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/scheduler"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 响应编码
+const (
+	encodingIdentity = "identity"
+	encodingGzip     = "gzip"
+	encodingBrotli   = "br"
+)
+
+// payloadCache 序列化并压缩后的 API 响应缓存
+var payloadCache = cache.NewTyped[*cache.Payload]("payload")
+
+// respondCached 输出带 ETag/Last-Modified 的 JSON 响应
+// 支持 tz 查询参数指定输出时区,缓存中保存序列化并压缩后的 cache.Payload,未命中时调用 load 获取数据
+func respondCached(c *gin.Context, cacheKey string, ttl time.Duration, tags []string, errMsg string,
+	load func() (interface{}, *models.Pagination, error)) {
+	// 指定 tz 时按该时区输出时间,不同时区分别缓存
+	loc, err := requestLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if loc != nil {
+		cacheKey += "|tz=" + loc.String()
+	}
+
+	payload, err := payloadCache.GetOrLoad(cacheKey, ttl, tags, func() (*cache.Payload, error) {
+		data, meta, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp := time.Now()
+		if loc != nil {
+			localizeTimes(data, loc)
+			timestamp = timestamp.In(loc)
+		}
+
+		// ETag 只覆盖数据本身,不含每次加载都不同的时间戳,缓存过期重新加载后数据未变时条件请求仍能命中
+		response := models.APIResponse{
+			Success: true,
+			Data:    data,
+			Meta:    meta,
+			Stale:   scheduler.IsStale(),
+		}
+		content, err := json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+		response.Timestamp = timestamp
+		body, err := json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+
+		// Last-Modified 取最近一次成功获取数据的时间,尚未获取过时取当前时间
+		lastModified := scheduler.LastSuccessfulFetch()
+		if lastModified.IsZero() {
+			lastModified = time.Now()
+		}
+		return cache.NewPayload("application/json; charset=utf-8", body, content, lastModified)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   errMsg,
+		})
+		return
+	}
+
+	writePayload(c, payload)
+}
+
+// writePayload 按 Accept-Encoding 选择编码输出,并处理条件请求
+func writePayload(c *gin.Context, payload *cache.Payload) {
+	encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), payload)
+	etag := `"` + payload.ETag + `"`
+	if encoding != encodingIdentity {
+		// 不同编码的字节不同,强 ETag 需要区分
+		etag = `"` + payload.ETag + "-" + encoding + `"`
+	}
+
+	header := c.Writer.Header()
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", payload.LastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", "no-cache")
+	header.Add("Vary", "Accept-Encoding")
+
+	if notModified(c.Request, payload) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	body := payload.Identity
+	switch encoding {
+	case encodingGzip:
+		body = payload.Gzip
+	case encodingBrotli:
+		body = payload.Brotli
+	}
+	if encoding != encodingIdentity {
+		header.Set("Content-Encoding", encoding)
+	}
+	c.Data(http.StatusOK, payload.ContentType, body)
+}
+
+// notModified 判断条件请求是否命中,If-None-Match 存在时忽略 If-Modified-Since
+// 数据过期期间 Last-Modified 停在最近一次成功获取的时间,不能反映 stale 标记的变化,此时忽略 If-Modified-Since
+func notModified(req *http.Request, payload *cache.Payload) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" {
+				return true
+			}
+			tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+			// 任一编码版本的 ETag 都代表同一份内容
+			base, _, _ := strings.Cut(tag, "-")
+			if base == payload.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" && !scheduler.IsStale() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !payload.LastModified.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择编码,优先 brotli,其次 gzip
+func negotiateEncoding(acceptEncoding string, payload *cache.Payload) string {
+	if acceptEncoding == "" || payload.Gzip == nil {
+		return encodingIdentity
+	}
+
+	accepted := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	acceptable := func(name string) bool {
+		if q, ok := accepted[name]; ok {
+			return q > 0
+		}
+		q, ok := accepted["*"]
+		return ok && q > 0
+	}
+
+	switch {
+	case payload.Brotli != nil && acceptable(encodingBrotli):
+		return encodingBrotli
+	case acceptable(encodingGzip):
+		return encodingGzip
+	default:
+		return encodingIdentity
+	}
+}
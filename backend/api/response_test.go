@@ -0,0 +1,71 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestETagIgnoresTimestamp 缓存失效后重新加载,数据不变时 ETag 不变,条件请求返回 304
+func TestETagIgnoresTimestamp(t *testing.T) {
+	router := setupTestAPI(t)
+	seedSchemaData(t)
+
+	first := doRequest(router, http.MethodGet, "/api/monitors", "", false)
+	if first.Code != http.StatusOK {
+		t.Fatalf("返回 %d: %s", first.Code, first.Body.String())
+	}
+	etag := first.Header().Get("ETag")
+
+	cache.NextGeneration()
+	cache.Invalidate(cache.TagMonitors)
+	second := doRequest(router, http.MethodGet, "/api/monitors", "", false)
+	if second.Body.String() == first.Body.String() {
+		t.Fatal("缓存失效后响应应重新生成,时间戳不同")
+	}
+	if got := second.Header().Get("ETag"); got != etag {
+		t.Fatalf("数据未变时 ETag 变化: %s -> %s", etag, got)
+	}
+
+	cache.NextGeneration()
+	req := httptest.NewRequest(http.MethodGet, "/api/monitors", nil)
+	req.Header.Set("If-None-Match", etag)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match 命中时应返回 304,实际 %d", w.Code)
+	}
+}
+
+// TestIfModifiedSinceIgnoredWhenStale 数据过期后 Last-Modified 不再前进,If-Modified-Since 不能再返回 304
+func TestIfModifiedSinceIgnoredWhenStale(t *testing.T) {
+	router := setupTestAPI(t)
+	seedSchemaData(t)
+
+	first := doRequest(router, http.MethodGet, "/api/monitors", "", false)
+	lastModified := first.Header().Get("Last-Modified")
+	etag := first.Header().Get("ETag")
+
+	conditional := func(header, value string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/monitors", nil)
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := conditional("If-Modified-Since", lastModified); code != http.StatusNotModified {
+		t.Fatalf("数据未过期时 If-Modified-Since 应返回 304,实际 %d", code)
+	}
+
+	config.AppConfig.StaleThreshold = time.Nanosecond
+	cache.NextGeneration()
+	if code := conditional("If-Modified-Since", lastModified); code != http.StatusOK {
+		t.Errorf("数据过期时 If-Modified-Since 应返回 200,实际 %d", code)
+	}
+	if code := conditional("If-None-Match", etag); code != http.StatusOK {
+		t.Errorf("stale 变化后旧 ETag 应返回 200,实际 %d", code)
+	}
+}
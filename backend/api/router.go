@@ -1,9 +1,450 @@
 package api
 
 import (
+	"kuma-lite/backend/models"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 )
 
+// apiRoutes /api 下的 JSON 接口
+// 新增接口时在此声明参数和响应类型,OpenAPI 文档和 client 包据此生成
+var apiRoutes = []apiRoute{
+	{
+		Method: http.MethodGet, Path: "/health", Handler: HealthCheck,
+		OperationID: "healthCheck", Summary: "健康检查",
+		Response: &models.HealthStatus{},
+		Errors:   []int{http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/health/source", Handler: GetSourceHealth,
+		OperationID: "getSourceHealth", Summary: "获取数据源健康状况",
+		Response: &models.SourceHealth{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors", Handler: GetMonitors,
+		OperationID: "getMonitors", Summary: "获取监控项列表",
+		Params: []apiParam{
+			{Name: "status", In: "query", Type: "string", Description: "按 effectiveStatus 过滤,逗号分隔,可用数字或 down/up/pending/maintenance/impacted/degraded"},
+			{Name: "group", In: "query", Type: "string", Description: "分组过滤"},
+			{Name: "type", In: "query", Type: "string", Description: "监控类型过滤"},
+			{Name: "name", In: "query", Type: "string", Description: "名称子串过滤,不区分大小写"},
+			{Name: "source", In: "query", Type: "string", Description: "数据来源过滤"},
+			{Name: "include", In: "query", Type: "string", Description: "传 archived 时包含已从 Kuma 移除的归档监控项"},
+			{Name: "tag", In: "query", Type: "string", Description: "标签过滤,逗号分隔,name 或 name:value,需同时满足"},
+			{Name: "sort", In: "query", Type: "string", Description: "排序字段: name, uptime, responseTime, group,默认按 ID"},
+			{Name: "order", In: "query", Type: "string", Description: "排序方向: asc(默认)或 desc"},
+			{Name: "limit", In: "query", Type: "integer", Description: "每页数量,最大 500,不传时不分页"},
+			{Name: "offset", In: "query", Type: "integer", Description: "起始位置"},
+			{Name: "cursor", In: "query", Type: "string", Description: "上一页返回的 meta.nextCursor,优先于 offset"},
+			tzParam,
+		},
+		Response:  []models.Monitor{},
+		Paginated: true,
+		Errors:    []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors/:id", Handler: GetMonitorByID,
+		OperationID: "getMonitorByID", Summary: "获取单个监控项",
+		Params:   []apiParam{monitorIDParam, tzParam},
+		Response: &models.Monitor{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors/:id/history", Handler: GetMonitorHistory,
+		OperationID: "getMonitorHistory", Summary: "获取监控历史",
+		Params: []apiParam{
+			monitorIDParam,
+			{Name: "limit", In: "query", Type: "integer", Description: "获取最近 N 条记录,优先于 hours"},
+			{Name: "hours", In: "query", Type: "integer", Description: "获取最近 N 小时的记录,默认 24"},
+			tzParam,
+		},
+		Response: []models.HeartBeat{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors/:id/changes", Handler: GetMonitorChanges,
+		OperationID: "getMonitorChanges", Summary: "获取监控项变更记录",
+		Params: []apiParam{
+			monitorIDParam,
+			{Name: "limit", In: "query", Type: "integer", Description: "返回条数,默认 100,最大 500"},
+			tzParam,
+		},
+		Response: []models.MonitorChange{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors/:id/anomalies", Handler: GetMonitorAnomalies,
+		OperationID: "getMonitorAnomalies", Summary: "获取监控项响应时间异常",
+		Params: []apiParam{
+			monitorIDParam,
+			{Name: "days", In: "query", Type: "integer", Description: "时间范围(天),默认 7,最大 90"},
+			{Name: "limit", In: "query", Type: "integer", Description: "返回条数,默认 100,最大 500"},
+			tzParam,
+		},
+		Response: []models.LatencyAnomaly{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/monitors/:id/flaps", Handler: GetMonitorFlaps,
+		OperationID: "getMonitorFlaps", Summary: "获取监控项状态抖动记录",
+		Params: []apiParam{
+			monitorIDParam,
+			{Name: "days", In: "query", Type: "integer", Description: "时间范围(天),默认 7,最大 90"},
+			{Name: "limit", In: "query", Type: "integer", Description: "返回条数,默认 100,最大 500"},
+			tzParam,
+		},
+		Response: []models.FlapPeriod{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/events", Handler: GetEvents,
+		OperationID: "getEvents", Summary: "获取事件列表",
+		Params: []apiParam{
+			{Name: "kind", In: "query", Type: "string", Description: "事件类型过滤,逗号分隔"},
+			{Name: "monitor", In: "query", Type: "integer", Description: "只返回指定监控项的事件"},
+			{Name: "days", In: "query", Type: "integer", Description: "时间范围(天),默认 7,最大 90"},
+			{Name: "limit", In: "query", Type: "integer", Description: "返回条数,默认 100,最大 500"},
+			tzParam,
+		},
+		Response: []models.Event{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/certificates", Handler: GetCertificates,
+		OperationID: "getCertificates", Summary: "获取证书到期情况",
+		Params: []apiParam{
+			{Name: "days", In: "query", Type: "integer", Description: "剩余天数不超过该值时标记为即将到期,默认 30"},
+		},
+		Response: []models.CertificateStatus{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/dependencies", Handler: GetDependencies,
+		OperationID: "getDependencies", Summary: "获取监控项依赖图",
+		Response: &models.DependencyGraph{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/composites", Handler: GetComposites,
+		OperationID: "getComposites", Summary: "获取组合监控项定义",
+		Response: []models.CompositeMonitor{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/reliability", Handler: GetReliability,
+		OperationID: "getReliability", Summary: "获取 MTTR、MTBF 等可靠性指标",
+		Params: []apiParam{
+			{Name: "from", In: "query", Type: "string", Description: "开始时间,RFC 3339 或 YYYY-MM-DD"},
+			{Name: "to", In: "query", Type: "string", Description: "结束时间,RFC 3339 或 YYYY-MM-DD(包含当天),默认当前时间"},
+			{Name: "days", In: "query", Type: "integer", Description: "未指定 from 时的天数,默认 30,最大 366"},
+			{Name: "group", In: "query", Type: "string", Description: "只统计指定分组"},
+			{Name: "monitor", In: "query", Type: "integer", Description: "只统计指定监控项"},
+			{Name: "format", In: "query", Type: "string", Description: "csv 时导出 CSV"},
+			tzParam,
+		},
+		Response: &models.ReliabilityReport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/slos", Handler: GetSLOs,
+		OperationID: "getSLOs", Summary: "获取 SLO 及错误预算、燃烧率",
+		Params:   []apiParam{tzParam},
+		Response: []models.SLOReport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/slos/:id", Handler: GetSLO,
+		OperationID: "getSLO", Summary: "获取单个 SLO",
+		Params:   []apiParam{sloIDParam, tzParam},
+		Response: &models.SLOReport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/stats", Handler: GetStats,
+		OperationID: "getStats", Summary: "获取统计信息",
+		Response: &models.Stats{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/subscriptions", Handler: PostSubscription,
+		OperationID: "postSubscription", Summary: "订阅状态更新邮件",
+		Request:  models.SubscriptionRequest{},
+		Response: &models.SubscriptionRequest{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway},
+	},
+	{
+		Method: http.MethodPost, Path: "/subscriptions/confirm", Handler: PostSubscriptionConfirm,
+		OperationID: "postSubscriptionConfirm", Summary: "确认订阅",
+		Request:  models.SubscriptionToken{},
+		Response: &models.SubscriptionRequest{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/subscriptions/unsubscribe", Handler: PostUnsubscribe,
+		OperationID: "postUnsubscribe", Summary: "退订",
+		Params: []apiParam{
+			{Name: "token", In: "query", Type: "string", Description: "邮件中的退订令牌"},
+		},
+		Response: &models.SubscriptionRequest{},
+		Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/overrides", Handler: GetMonitorOverrides,
+		OperationID: "getMonitorOverrides", Summary: "获取监控项展示覆盖",
+		Response: []models.MonitorOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/overrides/:id", Handler: PutMonitorOverride,
+		OperationID: "putMonitorOverride", Summary: "设置监控项展示覆盖",
+		Params:   []apiParam{monitorIDParam},
+		Request:  models.MonitorOverride{},
+		Response: &models.MonitorOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/overrides/:id", Handler: DeleteMonitorOverride,
+		OperationID: "deleteMonitorOverride", Summary: "删除监控项展示覆盖",
+		Params:   []apiParam{monitorIDParam},
+		Response: &models.MonitorOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/group-overrides", Handler: GetGroupOverrides,
+		OperationID: "getGroupOverrides", Summary: "获取分组顺序覆盖",
+		Response: []models.GroupOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/group-overrides", Handler: PutGroupOverride,
+		OperationID: "putGroupOverride", Summary: "设置分组顺序覆盖",
+		Request:  models.GroupOverride{},
+		Response: &models.GroupOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/group-overrides", Handler: DeleteGroupOverride,
+		OperationID: "deleteGroupOverride", Summary: "删除分组顺序覆盖",
+		Params: []apiParam{
+			{Name: "group", In: "query", Type: "string", Description: "分组名", Required: true},
+		},
+		Response: &models.GroupOverride{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/dependencies", Handler: PostDependency,
+		OperationID: "postDependency", Summary: "声明监控项依赖关系",
+		Request:  models.MonitorDependency{},
+		Response: &models.MonitorDependency{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/dependencies/:id", Handler: DeleteDependency,
+		OperationID: "deleteDependency", Summary: "删除监控项依赖关系",
+		Params: []apiParam{
+			{Name: "id", In: "path", Type: "integer", Description: "依赖关系 ID"},
+		},
+		Response: &models.MonitorDependency{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/composites", Handler: PostComposite,
+		OperationID: "postComposite", Summary: "创建组合监控项",
+		Request:  models.CompositeMonitor{},
+		Response: &models.CompositeMonitor{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/composites/:id", Handler: PutComposite,
+		OperationID: "putComposite", Summary: "更新组合监控项",
+		Params:   []apiParam{monitorIDParam},
+		Request:  models.CompositeMonitor{},
+		Response: &models.CompositeMonitor{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/composites/:id", Handler: DeleteComposite,
+		OperationID: "deleteComposite", Summary: "删除组合监控项",
+		Params:   []apiParam{monitorIDParam},
+		Response: &models.CompositeMonitor{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/latency-thresholds", Handler: GetLatencyThresholds,
+		OperationID: "getLatencyThresholds", Summary: "获取响应时间阈值",
+		Response: []models.LatencyThreshold{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/latency-thresholds", Handler: PutLatencyThreshold,
+		OperationID: "putLatencyThreshold", Summary: "设置响应时间阈值",
+		Request:  models.LatencyThreshold{},
+		Response: &models.LatencyThreshold{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/latency-thresholds/:id", Handler: DeleteLatencyThreshold,
+		OperationID: "deleteLatencyThreshold", Summary: "删除响应时间阈值",
+		Params: []apiParam{
+			{Name: "id", In: "path", Type: "integer", Description: "阈值 ID"},
+		},
+		Response: &models.LatencyThreshold{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/slos", Handler: PostSLO,
+		OperationID: "postSLO", Summary: "创建 SLO",
+		Request:  models.SLO{},
+		Response: &models.SLO{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/slos/:id", Handler: PutSLO,
+		OperationID: "putSLO", Summary: "更新 SLO",
+		Params:   []apiParam{sloIDParam},
+		Request:  models.SLO{},
+		Response: &models.SLO{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/slos/:id", Handler: DeleteSLO,
+		OperationID: "deleteSLO", Summary: "删除 SLO",
+		Params:   []apiParam{sloIDParam},
+		Response: &models.SLO{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/notification-channels", Handler: GetNotificationChannels,
+		OperationID: "getNotificationChannels", Summary: "获取通知渠道",
+		Response: []models.NotificationChannel{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/notification-channels", Handler: PostNotificationChannel,
+		OperationID: "postNotificationChannel", Summary: "创建通知渠道",
+		Request:  models.NotificationChannel{},
+		Response: &models.NotificationChannel{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/notification-channels/:id", Handler: PutNotificationChannel,
+		OperationID: "putNotificationChannel", Summary: "更新通知渠道",
+		Params:   []apiParam{channelIDParam},
+		Request:  models.NotificationChannel{},
+		Response: &models.NotificationChannel{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/notification-channels/:id", Handler: DeleteNotificationChannel,
+		OperationID: "deleteNotificationChannel", Summary: "删除通知渠道",
+		Params:   []apiParam{channelIDParam},
+		Response: &models.NotificationChannel{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/notification-channels/:id/test", Handler: TestNotificationChannel,
+		OperationID: "testNotificationChannel", Summary: "发送测试通知",
+		Params:   []apiParam{channelIDParam},
+		Response: &models.NotificationChannel{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/notification-rules", Handler: GetNotificationRules,
+		OperationID: "getNotificationRules", Summary: "获取通知路由规则",
+		Response: []models.NotificationRule{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/notification-rules", Handler: PostNotificationRule,
+		OperationID: "postNotificationRule", Summary: "创建通知路由规则",
+		Request:  models.NotificationRule{},
+		Response: &models.NotificationRule{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/notification-rules/:id", Handler: PutNotificationRule,
+		OperationID: "putNotificationRule", Summary: "更新通知路由规则",
+		Params:   []apiParam{ruleIDParam},
+		Request:  models.NotificationRule{},
+		Response: &models.NotificationRule{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/notification-rules/:id", Handler: DeleteNotificationRule,
+		OperationID: "deleteNotificationRule", Summary: "删除通知路由规则",
+		Params:   []apiParam{ruleIDParam},
+		Response: &models.NotificationRule{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/notification-deliveries", Handler: GetNotificationDeliveries,
+		OperationID: "getNotificationDeliveries", Summary: "获取通知发送历史",
+		Params: []apiParam{
+			{Name: "channel", In: "query", Type: "integer", Description: "通知渠道 ID 过滤"},
+			{Name: "incident", In: "query", Type: "integer", Description: "故障事件 ID 过滤"},
+			{Name: "status", In: "query", Type: "string", Description: "发送状态过滤: pending、deferred、sent、failed、suppressed"},
+			{Name: "days", In: "query", Type: "integer", Description: "查询最近多少天,默认 7,最大 90"},
+			{Name: "limit", In: "query", Type: "integer", Description: "返回数量,默认 100,最大 500"},
+		},
+		Response: []models.NotificationDelivery{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/subscribers", Handler: GetSubscribers,
+		OperationID: "getSubscribers", Summary: "获取邮件订阅者",
+		Response: []models.Subscriber{},
+		Admin:    true,
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/subscribers/:id", Handler: DeleteSubscriber,
+		OperationID: "deleteSubscriber", Summary: "删除邮件订阅者",
+		Params: []apiParam{
+			{Name: "id", In: "path", Type: "integer", Description: "订阅者 ID"},
+		},
+		Response: &models.Subscriber{},
+		Admin:    true,
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+var (
+	monitorIDParam = apiParam{Name: "id", In: "path", Type: "integer", Description: "监控项 ID"}
+	sloIDParam     = apiParam{Name: "id", In: "path", Type: "integer", Description: "SLO ID"}
+	channelIDParam = apiParam{Name: "id", In: "path", Type: "integer", Description: "通知渠道 ID"}
+	ruleIDParam    = apiParam{Name: "id", In: "path", Type: "integer", Description: "通知路由规则 ID"}
+	tzParam        = apiParam{Name: "tz", In: "query", Type: "string", Description: "输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC"}
+)
+
 // SetupRouter 设置路由
 func SetupRouter() *gin.Engine {
 	router := gin.Default()
@@ -14,13 +455,38 @@ func SetupRouter() *gin.Engine {
 	// API 路由
 	apiGroup := router.Group("/api")
 	{
-		apiGroup.GET("/health", HealthCheck)
-		apiGroup.GET("/monitors", GetMonitors)
-		apiGroup.GET("/monitors/:id", GetMonitorByID)
-		apiGroup.GET("/monitors/:id/history", GetMonitorHistory)
-		apiGroup.GET("/stats", GetStats)
+		for _, route := range apiRoutes {
+			if route.Admin {
+				apiGroup.Handle(route.Method, route.Path, adminAuth(), route.Handler)
+				continue
+			}
+			apiGroup.Handle(route.Method, route.Path, route.Handler)
+		}
+		apiGroup.GET("/openapi.json", GetOpenAPISpec)
+		apiGroup.GET("/subscriptions/unsubscribe", GetUnsubscribePage)
+	}
+
+	// Statuspage v2 兼容 API
+	statuspageGroup := router.Group("/api/v2")
+	{
+		statuspageGroup.GET("/summary.json", GetStatuspageSummary)
+		statuspageGroup.GET("/status.json", GetStatuspageStatus)
+		statuspageGroup.GET("/components.json", GetStatuspageComponents)
+		statuspageGroup.GET("/incidents.json", GetStatuspageIncidents)
+		statuspageGroup.GET("/incidents/unresolved.json", GetStatuspageUnresolvedIncidents)
 	}
 
+	// 订阅源
+	router.GET("/feed.atom", GetAtomFeed)
+	router.GET("/feed.rss", GetRSSFeed)
+	router.GET("/feed.json", GetJSONFeed)
+
+	// 徽章
+	router.GET("/badge/:id/:file", GetBadge)
+
+	// Prometheus 指标
+	router.GET("/metrics", GetMetrics)
+
 	// 静态文件服务
 	router.Static("/css", "./static/css")
 	router.Static("/js", "./static/js")
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSLOs 获取所有 SLO 及其 SLI、剩余错误预算和燃烧率
+func GetSLOs(c *gin.Context) {
+	respondCached(c, "slos", config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取 SLO 失败",
+		func() (interface{}, *models.Pagination, error) {
+			reports, err := database.GetSLOReports(time.Now().UTC())
+			return reports, nil, err
+		})
+}
+
+// GetSLO 获取单个 SLO 的计算结果
+func GetSLO(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的 SLO ID",
+		})
+		return
+	}
+	if _, err := database.GetSLO(id); err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "SLO 不存在",
+		})
+		return
+	}
+
+	respondCached(c, "slo_"+idStr, config.AppConfig.CacheDuration, []string{cache.TagFetch, cache.TagMonitors}, "获取 SLO 失败",
+		func() (interface{}, *models.Pagination, error) {
+			report, err := database.GetSLOReport(id, time.Now().UTC())
+			return report, nil, err
+		})
+}
+
+// PostSLO 创建 SLO
+func PostSLO(c *gin.Context) {
+	saveSLO(c, nil)
+}
+
+// PutSLO 更新 SLO 定义
+func PutSLO(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的 SLO ID",
+		})
+		return
+	}
+	existing, err := database.GetSLO(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "SLO 不存在",
+		})
+		return
+	}
+	saveSLO(c, existing)
+}
+
+// DeleteSLO 删除 SLO 及其告警记录
+func DeleteSLO(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的 SLO ID",
+		})
+		return
+	}
+
+	slo, err := database.GetSLO(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "SLO 不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteSLO(id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除 SLO 失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    slo,
+	})
+}
+
+// saveSLO 解析、校验并保存 SLO,existing 为 nil 时新建
+func saveSLO(c *gin.Context, existing *models.SLO) {
+	var slo models.SLO
+	if err := c.ShouldBindJSON(&slo); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	slo.ID = 0
+	if existing != nil {
+		slo.ID = existing.ID
+		slo.CreatedAt = existing.CreatedAt
+	}
+	slo.Name = strings.TrimSpace(slo.Name)
+	slo.GroupName = strings.TrimSpace(slo.GroupName)
+	if slo.WindowDays == 0 {
+		slo.WindowDays = 30
+	}
+
+	if msg := validateSLO(&slo); msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if slo.MonitorID != 0 {
+		if _, err := database.GetMonitorByID(slo.MonitorID); err != nil {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "监控项不存在",
+			})
+			return
+		}
+	}
+
+	if err := database.SaveSLO(&slo); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存 SLO 失败",
+		})
+		return
+	}
+	cache.Invalidate(cache.TagMonitors)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &slo,
+	})
+}
+
+// validateSLO 校验 SLO 定义,返回错误信息
+// 统计窗口不能超过心跳的保留天数,否则窗口内的数据不完整
+func validateSLO(slo *models.SLO) string {
+	if slo.Name == "" {
+		return "名称不能为空"
+	}
+	if len(slo.Name) > 255 || len(slo.Description) > 1000 || len(slo.GroupName) > 100 {
+		return "名称、描述或分组名过长"
+	}
+	if (slo.MonitorID == 0) == (slo.GroupName == "") {
+		return "monitorId 和 groupName 需要且只能设置一个"
+	}
+	if slo.MonitorID < 0 {
+		return "无效的监控项 ID"
+	}
+	if slo.Target <= 0 || slo.Target >= 100 {
+		return "target 需要在 0 到 100 之间(不含)"
+	}
+	if slo.WindowDays < 1 || slo.WindowDays > config.AppConfig.DataRetentionDays {
+		return "windowDays 需要在 1 到 " + strconv.Itoa(config.AppConfig.DataRetentionDays) + "(数据保留天数)之间"
+	}
+	if slo.LatencyMs < 0 {
+		return "latencyMs 不能为负数"
+	}
+	return ""
+}
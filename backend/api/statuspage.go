@@ -0,0 +1,447 @@
+package api
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Statuspage v2 组件状态
+const (
+	componentOperational         = "operational"
+	componentDegradedPerformance = "degraded_performance"
+	componentPartialOutage       = "partial_outage"
+	componentMajorOutage         = "major_outage"
+	componentUnderMaintenance    = "under_maintenance"
+)
+
+// statuspageIncidentLimit incidents.json 返回的最大事件数(与 Statuspage 一致)
+const statuspageIncidentLimit = 50
+
+type spPage struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	TimeZone  string    `json:"time_zone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type spStatus struct {
+	Indicator   string `json:"indicator"`
+	Description string `json:"description"`
+}
+
+type spComponent struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	Position           int       `json:"position"`
+	Description        *string   `json:"description"`
+	Showcase           bool      `json:"showcase"`
+	StartDate          *string   `json:"start_date"`
+	GroupID            *string   `json:"group_id"`
+	PageID             string    `json:"page_id"`
+	Group              bool      `json:"group"`
+	OnlyShowIfDegraded bool      `json:"only_show_if_degraded"`
+	Components         []string  `json:"components,omitempty"`
+}
+
+type spIncidentUpdate struct {
+	ID                 string    `json:"id"`
+	Status             string    `json:"status"`
+	Body               string    `json:"body"`
+	IncidentID         string    `json:"incident_id"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	DisplayAt          time.Time `json:"display_at"`
+	AffectedComponents []string  `json:"affected_components"`
+}
+
+type spIncident struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Status          string             `json:"status"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	MonitoringAt    *time.Time         `json:"monitoring_at"`
+	ResolvedAt      *time.Time         `json:"resolved_at"`
+	Impact          string             `json:"impact"`
+	Shortlink       string             `json:"shortlink"`
+	StartedAt       time.Time          `json:"started_at"`
+	PageID          string             `json:"page_id"`
+	IncidentUpdates []spIncidentUpdate `json:"incident_updates"`
+	Components      []spComponent      `json:"components"`
+}
+
+// statuspageData 构建 Statuspage 响应所需的数据
+type statuspageData struct {
+	page       spPage
+	components []spComponent
+	byMonitor  map[int]spComponent
+}
+
+// GetStatuspageSummary Statuspage v2 summary.json
+func GetStatuspageSummary(c *gin.Context) {
+	data, ok := loadStatuspageData(c)
+	if !ok {
+		return
+	}
+	incidents, ok := loadStatuspageIncidents(c, data, true)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":                   data.page,
+		"components":             data.components,
+		"incidents":              incidents,
+		"scheduled_maintenances": []interface{}{},
+		"status":                 statuspageStatus(data.components),
+	})
+}
+
+// GetStatuspageStatus Statuspage v2 status.json
+func GetStatuspageStatus(c *gin.Context) {
+	data, ok := loadStatuspageData(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":   data.page,
+		"status": statuspageStatus(data.components),
+	})
+}
+
+// GetStatuspageComponents Statuspage v2 components.json
+func GetStatuspageComponents(c *gin.Context) {
+	data, ok := loadStatuspageData(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":       data.page,
+		"components": data.components,
+	})
+}
+
+// GetStatuspageIncidents Statuspage v2 incidents.json
+func GetStatuspageIncidents(c *gin.Context) {
+	data, ok := loadStatuspageData(c)
+	if !ok {
+		return
+	}
+	incidents, ok := loadStatuspageIncidents(c, data, false)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":      data.page,
+		"incidents": incidents,
+	})
+}
+
+// GetStatuspageUnresolvedIncidents Statuspage v2 incidents/unresolved.json
+func GetStatuspageUnresolvedIncidents(c *gin.Context) {
+	data, ok := loadStatuspageData(c)
+	if !ok {
+		return
+	}
+	incidents, ok := loadStatuspageIncidents(c, data, true)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":      data.page,
+		"incidents": incidents,
+	})
+}
+
+// loadStatuspageData 将监控项映射为组件,分组映射为组件组
+func loadStatuspageData(c *gin.Context) (*statuspageData, bool) {
+	monitors, err := database.GetAllMonitors()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取监控数据失败",
+		})
+		return nil, false
+	}
+
+	cfg := config.AppConfig
+	data := &statuspageData{
+		page: spPage{
+			ID:       cfg.KumaStatusSlug,
+			Name:     cfg.StatusPageName,
+			URL:      requestBaseURL(c),
+			TimeZone: "Etc/UTC",
+		},
+		components: []spComponent{},
+		byMonitor:  make(map[int]spComponent, len(monitors)),
+	}
+
+	// 按分组顺序归集监控项,分组内保持展示顺序
+	type groupEntry struct {
+		name     string
+		order    int
+		children []spComponent
+	}
+	var groups []*groupEntry
+	groupIndex := make(map[string]*groupEntry)
+
+	for _, monitor := range monitors {
+		if monitor.UpdatedAt.After(data.page.UpdatedAt) {
+			data.page.UpdatedAt = monitor.UpdatedAt
+		}
+
+		component := spComponent{
+			ID:        strconv.Itoa(monitor.ID),
+			Name:      monitor.Name,
+			Status:    componentStatus(monitor.EffectiveStatus),
+			CreatedAt: monitor.CreatedAt,
+			UpdatedAt: monitor.UpdatedAt,
+			PageID:    data.page.ID,
+		}
+
+		if monitor.Group != "" {
+			entry, ok := groupIndex[monitor.Group]
+			if !ok {
+				entry = &groupEntry{name: monitor.Group, order: monitor.GroupOrder}
+				groupIndex[monitor.Group] = entry
+				groups = append(groups, entry)
+			}
+			groupID := groupComponentID(monitor.Group)
+			component.GroupID = &groupID
+			entry.children = append(entry.children, component)
+		} else {
+			component.Position = len(data.components) + 1
+			data.components = append(data.components, component)
+		}
+		data.byMonitor[monitor.ID] = component
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].order < groups[j].order })
+
+	for _, entry := range groups {
+		group := spComponent{
+			ID:         groupComponentID(entry.name),
+			Name:       entry.name,
+			Status:     groupStatus(entry.children),
+			Position:   len(data.components) + 1,
+			PageID:     data.page.ID,
+			Group:      true,
+			Components: []string{},
+		}
+		for i, child := range entry.children {
+			child.Position = i + 1
+			group.Components = append(group.Components, child.ID)
+			if group.CreatedAt.IsZero() || child.CreatedAt.Before(group.CreatedAt) {
+				group.CreatedAt = child.CreatedAt
+			}
+			if child.UpdatedAt.After(group.UpdatedAt) {
+				group.UpdatedAt = child.UpdatedAt
+			}
+			entry.children[i] = child
+		}
+		data.components = append(data.components, group)
+		data.components = append(data.components, entry.children...)
+	}
+
+	if data.page.UpdatedAt.IsZero() {
+		data.page.UpdatedAt = time.Now()
+	}
+	return data, true
+}
+
+// loadStatuspageIncidents 将故障事件映射为 Statuspage incident
+func loadStatuspageIncidents(c *gin.Context, data *statuspageData, unresolvedOnly bool) ([]spIncident, bool) {
+	var incidents []models.Incident
+	var err error
+	if unresolvedOnly {
+		incidents, err = database.GetUnresolvedIncidents()
+	} else {
+		incidents, err = database.GetIncidents(statuspageIncidentLimit)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取故障事件失败",
+		})
+		return nil, false
+	}
+
+	// 因依赖离线的监控项归入根因事件,作为受影响的组件
+	parentIDs := make([]int, 0, len(incidents))
+	for _, incident := range incidents {
+		parentIDs = append(parentIDs, incident.ID)
+	}
+	children, err := database.GetChildIncidents(parentIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取故障事件失败",
+		})
+		return nil, false
+	}
+	impacted := make(map[int][]int)
+	for _, child := range children {
+		impacted[child.ParentID] = append(impacted[child.ParentID], child.MonitorID)
+	}
+
+	result := make([]spIncident, 0, len(incidents))
+	for _, incident := range incidents {
+		result = append(result, toStatuspageIncident(data, incident, impacted[incident.ID]))
+	}
+	return result, true
+}
+
+// toStatuspageIncident 转换单个故障事件,事件开始和解决各生成一条更新
+// impacted 为受该事件影响的依赖方监控项
+func toStatuspageIncident(data *statuspageData, incident models.Incident, impacted []int) spIncident {
+	id := strconv.Itoa(incident.ID)
+	out := spIncident{
+		ID:              id,
+		Name:            incident.Title,
+		Status:          incident.Status,
+		CreatedAt:       incident.CreatedAt,
+		UpdatedAt:       incident.UpdatedAt,
+		ResolvedAt:      incident.ResolvedAt,
+		Impact:          incident.Impact,
+		Shortlink:       data.page.URL + "/detail.html?id=" + strconv.Itoa(incident.MonitorID),
+		StartedAt:       incident.StartedAt,
+		PageID:          data.page.ID,
+		IncidentUpdates: []spIncidentUpdate{},
+		Components:      []spComponent{},
+	}
+
+	var affected []string
+	seen := make(map[int]bool)
+	for _, monitorID := range append([]int{incident.MonitorID}, impacted...) {
+		if seen[monitorID] {
+			continue
+		}
+		seen[monitorID] = true
+		if component, ok := data.byMonitor[monitorID]; ok {
+			out.Components = append(out.Components, component)
+			affected = append(affected, component.ID)
+		}
+	}
+
+	body := "监控检测到服务离线,正在调查。"
+	if incident.Impact == "minor" {
+		body = "监控检测到服务响应缓慢,正在调查。"
+	}
+	if incident.Message != "" {
+		body += "\n" + incident.Message
+	}
+	updates := []spIncidentUpdate{{
+		ID:                 id + "-" + models.IncidentInvestigating,
+		Status:             models.IncidentInvestigating,
+		Body:               body,
+		IncidentID:         id,
+		CreatedAt:          incident.StartedAt,
+		UpdatedAt:          incident.StartedAt,
+		DisplayAt:          incident.StartedAt,
+		AffectedComponents: affected,
+	}}
+	if incident.ResolvedAt != nil {
+		resolved := spIncidentUpdate{
+			ID:                 id + "-" + models.IncidentResolved,
+			Status:             models.IncidentResolved,
+			Body:               "服务已恢复正常,持续 " + incident.ResolvedAt.Sub(incident.StartedAt).Round(time.Second).String() + "。",
+			IncidentID:         id,
+			CreatedAt:          *incident.ResolvedAt,
+			UpdatedAt:          *incident.ResolvedAt,
+			DisplayAt:          *incident.ResolvedAt,
+			AffectedComponents: affected,
+		}
+		// Statuspage 中最新的更新排在最前
+		updates = append([]spIncidentUpdate{resolved}, updates...)
+	}
+	out.IncidentUpdates = updates
+
+	return out
+}
+
+// componentStatus 考虑依赖和响应时间后的状态映射为组件状态
+func componentStatus(status int) string {
+	switch status {
+	case models.StatusUp:
+		return componentOperational
+	case models.StatusPending, models.StatusDegraded:
+		return componentDegradedPerformance
+	case models.StatusMaintenance:
+		return componentUnderMaintenance
+	default:
+		return componentMajorOutage
+	}
+}
+
+// groupStatus 组件组状态取成员中最严重的,全部离线时为 major_outage
+func groupStatus(children []spComponent) string {
+	down, degraded, maintenance := 0, 0, 0
+	for _, child := range children {
+		switch child.Status {
+		case componentMajorOutage:
+			down++
+		case componentDegradedPerformance:
+			degraded++
+		case componentUnderMaintenance:
+			maintenance++
+		}
+	}
+	switch {
+	case len(children) > 0 && down == len(children):
+		return componentMajorOutage
+	case down > 0:
+		return componentPartialOutage
+	case degraded > 0:
+		return componentDegradedPerformance
+	case len(children) > 0 && maintenance == len(children):
+		return componentUnderMaintenance
+	default:
+		return componentOperational
+	}
+}
+
+// statuspageStatus 根据所有组件计算页面整体状态
+func statuspageStatus(components []spComponent) spStatus {
+	total, down, degraded, maintenance := 0, 0, 0, 0
+	for _, component := range components {
+		if component.Group {
+			continue
+		}
+		total++
+		switch component.Status {
+		case componentMajorOutage:
+			down++
+		case componentDegradedPerformance:
+			degraded++
+		case componentUnderMaintenance:
+			maintenance++
+		}
+	}
+	switch {
+	case total > 0 && down == total:
+		return spStatus{Indicator: "critical", Description: "Major System Outage"}
+	case down > 0:
+		return spStatus{Indicator: "major", Description: "Partial System Outage"}
+	case degraded > 0:
+		return spStatus{Indicator: "minor", Description: "Minor Service Outage"}
+	case maintenance > 0:
+		return spStatus{Indicator: "maintenance", Description: "Service Under Maintenance"}
+	default:
+		return spStatus{Indicator: "none", Description: "All Systems Operational"}
+	}
+}
+
+// groupComponentID 组件组 ID
+func groupComponentID(group string) string {
+	return "group-" + group
+}
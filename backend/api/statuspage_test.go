@@ -0,0 +1,78 @@
+package api
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+)
+
+func TestComponentStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{models.StatusUp, componentOperational},
+		{models.StatusDown, componentMajorOutage},
+		{models.StatusPending, componentDegradedPerformance},
+		{models.StatusMaintenance, componentUnderMaintenance},
+		{models.StatusImpacted, componentMajorOutage},
+		{models.StatusDegraded, componentDegradedPerformance},
+	}
+	for _, tt := range tests {
+		if got := componentStatus(tt.status); got != tt.want {
+			t.Errorf("componentStatus(%d) = %s,应为 %s", tt.status, got, tt.want)
+		}
+	}
+}
+
+// components 按状态生成组件
+func components(statuses ...string) []spComponent {
+	result := make([]spComponent, len(statuses))
+	for i, status := range statuses {
+		result[i] = spComponent{Status: status}
+	}
+	return result
+}
+
+func TestGroupStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		children []spComponent
+		want     string
+	}{
+		{"没有成员", nil, componentOperational},
+		{"全部正常", components(componentOperational, componentOperational), componentOperational},
+		{"全部离线", components(componentMajorOutage, componentMajorOutage), componentMajorOutage},
+		{"部分离线", components(componentMajorOutage, componentOperational), componentPartialOutage},
+		{"离线优先于缓慢", components(componentMajorOutage, componentDegradedPerformance), componentPartialOutage},
+		{"部分缓慢", components(componentDegradedPerformance, componentOperational), componentDegradedPerformance},
+		{"全部维护", components(componentUnderMaintenance, componentUnderMaintenance), componentUnderMaintenance},
+		{"部分维护", components(componentUnderMaintenance, componentOperational), componentOperational},
+	}
+	for _, tt := range tests {
+		if got := groupStatus(tt.children); got != tt.want {
+			t.Errorf("%s: groupStatus = %s,应为 %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatuspageStatus(t *testing.T) {
+	group := spComponent{Status: componentMajorOutage, Group: true}
+	tests := []struct {
+		name       string
+		components []spComponent
+		want       string
+	}{
+		{"没有组件", nil, "none"},
+		{"全部正常", components(componentOperational, componentOperational), "none"},
+		{"全部离线", components(componentMajorOutage, componentMajorOutage), "critical"},
+		{"部分离线", components(componentMajorOutage, componentOperational), "major"},
+		{"缓慢", components(componentDegradedPerformance, componentOperational), "minor"},
+		{"维护", components(componentUnderMaintenance, componentOperational), "maintenance"},
+		{"不计组件组", append(components(componentOperational), group), "none"},
+	}
+	for _, tt := range tests {
+		if got := statuspageStatus(tt.components); got.Indicator != tt.want {
+			t.Errorf("%s: indicator = %s,应为 %s", tt.name, got.Indicator, tt.want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"html/template"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/notify"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 订阅请求的频率限制
+const (
+	subscribeLimit             = 5                // 每个客户端 IP 在一个窗口内最多提交的订阅请求数
+	subscribeLimitWindow       = time.Hour        // 频率限制的窗口
+	subscriptionResendCooldown = 10 * time.Minute // 同一邮箱两封确认邮件的最小间隔
+)
+
+// subscribeAttempts 各客户端 IP 在当前窗口内的订阅请求数,窗口结束后整体清空
+var subscribeAttempts = struct {
+	sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}{counts: make(map[string]int)}
+
+// allowSubscribe 判断客户端 IP 是否还能提交订阅请求,允许时计数
+func allowSubscribe(ip string, now time.Time) bool {
+	subscribeAttempts.Lock()
+	defer subscribeAttempts.Unlock()
+	if now.Sub(subscribeAttempts.windowStart) >= subscribeLimitWindow {
+		subscribeAttempts.windowStart = now
+		subscribeAttempts.counts = make(map[string]int)
+	}
+	if subscribeAttempts.counts[ip] >= subscribeLimit {
+		return false
+	}
+	subscribeAttempts.counts[ip]++
+	return true
+}
+
+// PostSubscription 订阅状态更新邮件,发送确认邮件,确认后才开始接收
+// 已订阅的邮箱再次订阅时同样需要确认,确认后新的分组生效
+// 为避免泄露邮箱是否已订阅,冷却期内的重复请求也返回成功但不再发送
+func PostSubscription(c *gin.Context) {
+	if !notify.SubscriptionsEnabled() {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "未启用邮件订阅",
+		})
+		return
+	}
+	if !allowSubscribe(c.ClientIP(), time.Now()) {
+		c.JSON(http.StatusTooManyRequests, models.APIResponse{
+			Success: false,
+			Error:   "订阅请求过于频繁,请稍后再试",
+		})
+		return
+	}
+
+	var req models.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "请求体格式错误",
+		})
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email || len(req.Email) > 255 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的邮箱地址",
+		})
+		return
+	}
+	groups, msg := normalizeSubscriptionGroups(req.Groups)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+	req.Groups = groups
+
+	subscriber, err := database.GetSubscriberByEmail(req.Email)
+	if err != nil {
+		subscriber = &models.Subscriber{Email: req.Email, Groups: []string{}}
+		if subscriber.UnsubscribeToken, err = notify.NewSubscriptionToken(); err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "生成令牌失败",
+			})
+			return
+		}
+	} else if time.Since(subscriber.ConfirmSentAt) < subscriptionResendCooldown {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Data:    &req,
+		})
+		return
+	}
+
+	if subscriber.ConfirmToken, err = notify.NewSubscriptionToken(); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "生成令牌失败",
+		})
+		return
+	}
+	subscriber.PendingGroups = req.Groups
+	subscriber.ConfirmSentAt = time.Now()
+
+	// 先发送确认邮件再保存,发送失败时不进入冷却期
+	if err := notify.SendConfirmation(subscriber); err != nil {
+		log.Printf("发送订阅确认邮件失败: %v", err)
+		c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   "发送确认邮件失败,请稍后再试",
+		})
+		return
+	}
+	if err := database.SaveSubscriber(subscriber); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "保存订阅失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &req,
+	})
+}
+
+// normalizeSubscriptionGroups 去除空白和重复的分组,并校验分组是否存在,返回错误信息
+func normalizeSubscriptionGroups(groups []string) ([]string, string) {
+	normalized := []string{}
+	if len(groups) == 0 {
+		return normalized, ""
+	}
+	monitors, err := database.GetAllMonitors()
+	if err != nil {
+		return nil, "获取分组失败"
+	}
+	known := make(map[string]bool)
+	for _, monitor := range monitors {
+		known[monitor.Group] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		group = strings.TrimSpace(group)
+		if group == "" || seen[group] {
+			continue
+		}
+		if !known[group] {
+			return nil, "分组不存在: " + group
+		}
+		seen[group] = true
+		normalized = append(normalized, group)
+	}
+	return normalized, ""
+}
+
+// PostSubscriptionConfirm 使用确认邮件中的令牌确认订阅
+func PostSubscriptionConfirm(c *gin.Context) {
+	var req models.SubscriptionToken
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "缺少确认令牌",
+		})
+		return
+	}
+	subscriber, err := database.GetSubscriberByConfirmToken(req.Token)
+	if err != nil || time.Since(subscriber.ConfirmSentAt) > notify.SubscriptionConfirmTTL {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "确认链接无效或已过期",
+		})
+		return
+	}
+
+	if err := database.ConfirmSubscriber(subscriber, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "确认订阅失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &models.SubscriptionRequest{Email: subscriber.Email, Groups: subscriber.Groups},
+	})
+}
+
+// unsubscribePage 退订确认页,确认按钮以 POST 提交同一地址
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>退订 - {{.Name}}</title>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Email}}<p id="message">确认不再向 {{.Email}} 发送状态更新邮件?</p>
+<form id="unsubscribe" method="post" action="{{.Action}}">
+<button type="submit">确认退订</button>
+</form>
+<script>
+document.getElementById("unsubscribe").addEventListener("submit", function (event) {
+  event.preventDefault();
+  fetch(this.action, {method: "POST"}).then(function (response) { return response.json(); }).then(function (result) {
+    document.getElementById("message").textContent = result.success ? "已退订,不会再收到状态更新邮件" : result.error;
+    event.target.remove();
+  });
+});
+</script>{{else}}<p>退订链接无效或已退订</p>{{end}}
+</body>
+</html>
+`))
+
+// GetUnsubscribePage 显示退订确认页
+// List-Unsubscribe 邮件头指向退订接口,在浏览器中打开时只显示确认页,
+// 避免邮件客户端或安全网关预取链接时直接退订
+func GetUnsubscribePage(c *gin.Context) {
+	data := struct {
+		Name   string
+		Email  string
+		Action string
+	}{Name: config.AppConfig.StatusPageName}
+
+	status := http.StatusOK
+	subscriber, err := database.GetSubscriberByUnsubscribeToken(c.Query("token"))
+	if err != nil {
+		status = http.StatusNotFound
+	} else {
+		data.Email = subscriber.Email
+		data.Action = c.Request.URL.RequestURI()
+	}
+
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := unsubscribePage.Execute(c.Writer, data); err != nil {
+		log.Printf("渲染退订确认页失败: %v", err)
+	}
+}
+
+// PostUnsubscribe 使用邮件中的退订令牌取消订阅
+// 令牌放在查询参数中,支持邮件客户端按 RFC 8058 发起的一键退订
+func PostUnsubscribe(c *gin.Context) {
+	subscriber, err := database.GetSubscriberByUnsubscribeToken(c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "退订链接无效或已退订",
+		})
+		return
+	}
+
+	if err := database.DeleteSubscriber(subscriber.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "退订失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    &models.SubscriptionRequest{Email: subscriber.Email, Groups: subscriber.Groups},
+	})
+}
+
+// GetSubscribers 获取所有邮件订阅者,包括未确认的
+func GetSubscribers(c *gin.Context) {
+	subscribers, err := database.GetSubscribers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "获取订阅者失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    subscribers,
+	})
+}
+
+// DeleteSubscriber 删除邮件订阅者
+func DeleteSubscriber(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "无效的订阅者 ID",
+		})
+		return
+	}
+	subscriber, err := database.GetSubscriber(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "订阅者不存在",
+		})
+		return
+	}
+
+	if err := database.DeleteSubscriber(subscriber.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "删除订阅者失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    subscriber,
+	})
+}
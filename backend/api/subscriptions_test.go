@@ -0,0 +1,276 @@
+package api
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSMTP 记录收到的邮件的本地 SMTP 服务器,不支持 STARTTLS 和认证
+type fakeSMTP struct {
+	mu    sync.Mutex
+	mails []string
+}
+
+// setupSubscriptionAPI 启动 fakeSMTP 并启用邮件订阅,清空订阅请求的频率限制
+func setupSubscriptionAPI(t *testing.T) (*gin.Engine, *fakeSMTP) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	fake := &fakeSMTP{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	t.Setenv("SMTP_HOST", host)
+	t.Setenv("SMTP_PORT", port)
+	t.Setenv("SMTP_FROM", "status@example.com")
+	t.Setenv("PUBLIC_URL", "https://status.example.com")
+
+	subscribeAttempts.Lock()
+	subscribeAttempts.windowStart = time.Time{}
+	subscribeAttempts.counts = make(map[string]int)
+	subscribeAttempts.Unlock()
+
+	router := setupTestAPI(t)
+	if err := database.SaveMonitor(&models.Monitor{ID: 1, Name: "Website", Group: "Core", Status: models.StatusUp}); err != nil {
+		t.Fatal(err)
+	}
+	return router, fake
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(strings.Fields(line + " ")[0]) {
+		case "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			f.mu.Lock()
+			f.mails = append(f.mails, data.String())
+			f.mu.Unlock()
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// bodies 返回已收到邮件的正文(已解码)
+func (f *fakeSMTP) bodies(t *testing.T) []string {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var bodies []string
+	for _, data := range f.mails {
+		m, err := mail.ReadMessage(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("邮件格式无效: %v", err)
+		}
+		body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, m.Body))
+		if err != nil {
+			t.Fatalf("正文不是 base64: %v", err)
+		}
+		bodies = append(bodies, string(body))
+	}
+	return bodies
+}
+
+var confirmLink = regexp.MustCompile(`https://status\.example\.com/\?confirm=([0-9a-f]+)`)
+
+// TestSubscriptionOptIn 订阅后收到确认邮件,确认后分组生效,退订后删除
+func TestSubscriptionOptIn(t *testing.T) {
+	router, fake := setupSubscriptionAPI(t)
+
+	w := doRequest(router, http.MethodPost, "/api/subscriptions", `{"email":" Ops@Example.com ","groups":["Core","Core"]}`, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("订阅应成功: %d %s", w.Code, w.Body)
+	}
+	bodies := fake.bodies(t)
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "分组 Core") {
+		t.Fatalf("应收到一封确认邮件: %q", bodies)
+	}
+	match := confirmLink.FindStringSubmatch(bodies[0])
+	if match == nil {
+		t.Fatalf("确认邮件中没有确认链接: %q", bodies[0])
+	}
+
+	subscriber, err := database.GetSubscriberByEmail("ops@example.com")
+	if err != nil || subscriber.Confirmed || strings.Join(subscriber.PendingGroups, ",") != "Core" {
+		t.Fatalf("确认前订阅者应未确认,分组等待确认: %+v, %v", subscriber, err)
+	}
+
+	if w := doRequest(router, http.MethodPost, "/api/subscriptions/confirm", `{"token":"invalid"}`, false); w.Code != http.StatusNotFound {
+		t.Errorf("无效的确认令牌应返回 404,实际 %d", w.Code)
+	}
+	w = doRequest(router, http.MethodPost, "/api/subscriptions/confirm", `{"token":"`+match[1]+`"}`, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("确认应成功: %d %s", w.Code, w.Body)
+	}
+	subscriber, err = database.GetSubscriberByEmail("ops@example.com")
+	if err != nil || !subscriber.Confirmed || strings.Join(subscriber.Groups, ",") != "Core" || subscriber.ConfirmToken != "" {
+		t.Fatalf("确认后分组应生效: %+v, %v", subscriber, err)
+	}
+	if w := doRequest(router, http.MethodPost, "/api/subscriptions/confirm", `{"token":"`+match[1]+`"}`, false); w.Code != http.StatusNotFound {
+		t.Errorf("确认令牌只能使用一次,实际 %d", w.Code)
+	}
+
+	// 打开退订链接只显示确认页,不直接退订
+	w = doRequest(router, http.MethodGet, "/api/subscriptions/unsubscribe?token="+subscriber.UnsubscribeToken, "", false)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") ||
+		!strings.Contains(w.Body.String(), "ops@example.com") || !strings.Contains(w.Body.String(), `method="post"`) {
+		t.Fatalf("退订链接应显示确认页: %d %s", w.Code, w.Body)
+	}
+	if _, err := database.GetSubscriberByEmail("ops@example.com"); err != nil {
+		t.Fatalf("打开确认页不应退订: %v", err)
+	}
+
+	w = doRequest(router, http.MethodPost, "/api/subscriptions/unsubscribe?token="+subscriber.UnsubscribeToken, "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("退订应成功: %d %s", w.Code, w.Body)
+	}
+	if _, err := database.GetSubscriberByEmail("ops@example.com"); err == nil {
+		t.Error("退订后订阅者应被删除")
+	}
+	if w := doRequest(router, http.MethodPost, "/api/subscriptions/unsubscribe?token="+subscriber.UnsubscribeToken, "", false); w.Code != http.StatusNotFound {
+		t.Errorf("重复退订应返回 404,实际 %d", w.Code)
+	}
+	if w := doRequest(router, http.MethodGet, "/api/subscriptions/unsubscribe?token="+subscriber.UnsubscribeToken, "", false); w.Code != http.StatusNotFound {
+		t.Errorf("已退订的令牌打开确认页应返回 404,实际 %d", w.Code)
+	}
+}
+
+// TestSubscriptionValidation 无效的邮箱和不存在的分组不发送确认邮件
+func TestSubscriptionValidation(t *testing.T) {
+	router, fake := setupSubscriptionAPI(t)
+
+	for _, body := range []string{`{"email":"not-an-email"}`, `{"email":"Name <a@example.com>"}`, `{"email":"a@example.com","groups":["不存在"]}`} {
+		if w := doRequest(router, http.MethodPost, "/api/subscriptions", body, false); w.Code != http.StatusBadRequest {
+			t.Errorf("%s 应返回 400,实际 %d", body, w.Code)
+		}
+	}
+	if bodies := fake.bodies(t); len(bodies) != 0 {
+		t.Errorf("请求无效时不应发送邮件: %q", bodies)
+	}
+}
+
+// TestSubscriptionResendCooldown 冷却期内重复订阅返回成功但不发送,冷却期后重新发送并更换确认令牌
+func TestSubscriptionResendCooldown(t *testing.T) {
+	router, fake := setupSubscriptionAPI(t)
+
+	subscribe := func() {
+		t.Helper()
+		if w := doRequest(router, http.MethodPost, "/api/subscriptions", `{"email":"ops@example.com"}`, false); w.Code != http.StatusOK {
+			t.Fatalf("订阅应返回成功: %d %s", w.Code, w.Body)
+		}
+	}
+	subscribe()
+	first, _ := database.GetSubscriberByEmail("ops@example.com")
+	subscribe()
+	if bodies := fake.bodies(t); len(bodies) != 1 {
+		t.Fatalf("冷却期内不应再次发送确认邮件,共 %d 封", len(bodies))
+	}
+
+	first.ConfirmSentAt = time.Now().Add(-subscriptionResendCooldown - time.Second)
+	if err := database.SaveSubscriber(first); err != nil {
+		t.Fatal(err)
+	}
+	subscribe()
+	bodies := fake.bodies(t)
+	if len(bodies) != 2 {
+		t.Fatalf("冷却期后应再次发送确认邮件,共 %d 封", len(bodies))
+	}
+	second, _ := database.GetSubscriberByEmail("ops@example.com")
+	if second.ConfirmToken == first.ConfirmToken || !strings.Contains(bodies[1], second.ConfirmToken) {
+		t.Error("重新发送的确认邮件应使用新的确认令牌")
+	}
+	if w := doRequest(router, http.MethodPost, "/api/subscriptions/confirm", `{"token":"`+first.ConfirmToken+`"}`, false); w.Code != http.StatusNotFound {
+		t.Errorf("旧的确认令牌应失效,实际 %d", w.Code)
+	}
+}
+
+// TestSubscriptionRateLimit 每个客户端 IP 在一个窗口内最多提交 subscribeLimit 次订阅请求
+func TestSubscriptionRateLimit(t *testing.T) {
+	router, _ := setupSubscriptionAPI(t)
+
+	post := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions", strings.NewReader(`{"email":"bad"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	for i := 0; i < subscribeLimit; i++ {
+		if code := post("192.0.2.1:1234"); code != http.StatusBadRequest {
+			t.Fatalf("第 %d 次请求不应被限制,实际 %d", i+1, code)
+		}
+	}
+	if code := post("192.0.2.1:5678"); code != http.StatusTooManyRequests {
+		t.Errorf("超过次数后应返回 429,实际 %d", code)
+	}
+	if code := post("192.0.2.2:1234"); code != http.StatusBadRequest {
+		t.Errorf("其他 IP 不应被限制,实际 %d", code)
+	}
+
+	// 窗口结束后计数清空
+	if !allowSubscribe("192.0.2.1", time.Now().Add(subscribeLimitWindow)) {
+		t.Error("新窗口内应允许请求")
+	}
+}
+
+// TestSubscriptionDisabled 未配置 PUBLIC_URL 时不能订阅
+func TestSubscriptionDisabled(t *testing.T) {
+	router := setupTestAPI(t)
+	w := doRequest(router, http.MethodPost, "/api/subscriptions", `{"email":"ops@example.com"}`, false)
+	var resp models.APIResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusForbidden || resp.Success {
+		t.Errorf("未启用邮件订阅时应返回 403,实际 %d %s", w.Code, w.Body)
+	}
+}
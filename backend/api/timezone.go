@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocation 解析 tz 查询参数,未指定时返回 nil 表示按 UTC 输出
+// 支持 IANA 时区名,如 Asia/Shanghai
+func requestLocation(c *gin.Context) (*time.Location, error) {
+	name := c.Query("tz")
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区: %s", name)
+	}
+	return loc, nil
+}
+
+// localizeTimes 将 v 中所有可写的 time.Time 转换到 loc 时区
+// v 需为指针或切片,值类型的结构体无法原地修改
+func localizeTimes(v interface{}, loc *time.Location) {
+	if v == nil || loc == nil {
+		return
+	}
+	localizeValue(reflect.ValueOf(v), loc)
+}
+
+func localizeValue(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			localizeValue(v.Elem(), loc)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			localizeValue(v.Index(i), loc)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if v.CanSet() {
+				t := v.Interface().(time.Time)
+				if !t.IsZero() {
+					v.Set(reflect.ValueOf(t.In(loc)))
+				}
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				localizeValue(v.Field(i), loc)
+			}
+		}
+	}
+}
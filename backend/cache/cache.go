@@ -1,34 +1,205 @@
 package cache
 
 import (
+	"bytes"
+	"encoding/gob"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
-	gocache "github.com/patrickmn/go-cache"
+	"golang.org/x/sync/singleflight"
 )
 
-var Cache *gocache.Cache
+// 常用的缓存标签
+const (
+	// TagFetch 所有由 Kuma 数据派生的缓存,每个获取周期结束后失效
+	TagFetch = "fetch"
+	// TagMonitors 监控项列表及其展示信息
+	TagMonitors = "monitors"
+)
+
+// Store 缓存后端,只存取字节,便于替换为 Redis 等外部存储
+type Store interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+	// Incr 原子递增计数器并返回新值,计数器不过期
+	Incr(key string) (int64, error)
+	Flush() error
+}
 
-// InitCache 初始化缓存
-func InitCache(defaultExpiration, cleanupInterval time.Duration) {
-	Cache = gocache.New(defaultExpiration, cleanupInterval)
+// ValueStore 可以直接保存 Go 值的后端,Typed 使用时跳过 gob 编解码
+// 进程内缓存实现该接口,命中时返回写入时的同一个值,不需要解码和复制
+type ValueStore interface {
+	GetValue(key string) (interface{}, bool)
+	SetValue(key string, value interface{}, ttl time.Duration)
 }
 
-// Set 设置缓存
-func Set(key string, value interface{}, duration time.Duration) {
-	Cache.Set(key, value, duration)
+var store Store
+
+// InitCache 初始化缓存后端
+func InitCache(s Store) {
+	store = s
 }
 
-// Get 获取缓存
-func Get(key string) (interface{}, bool) {
-	return Cache.Get(key)
+// Invalidate 使带有指定标签的缓存全部失效
+// 通过递增标签的代际计数器实现,旧条目不再被访问,由后端按 TTL 自然淘汰
+func Invalidate(tags ...string) {
+	for _, tag := range tags {
+		if _, err := store.Incr(generationKey(tag)); err != nil {
+			log.Printf("缓存失效失败 [%s]: %v", tag, err)
+		}
+	}
 }
 
-// Delete 删除缓存
-func Delete(key string) {
-	Cache.Delete(key)
+// NextGeneration 开始新的获取周期,使所有 TagFetch 缓存失效
+func NextGeneration() {
+	Invalidate(TagFetch)
 }
 
 // Clear 清空所有缓存
 func Clear() {
-	Cache.Flush()
+	if err := store.Flush(); err != nil {
+		log.Printf("清空缓存失败: %v", err)
+	}
+}
+
+// generationKey 标签代际计数器的键
+func generationKey(tag string) string {
+	return "gen:" + tag
+}
+
+// taggedKey 在键后附加各标签的当前代际,标签失效后生成的键随之变化
+func taggedKey(key string, tags []string) (string, error) {
+	if len(tags) == 0 {
+		return key, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(key)
+	b.WriteString("@")
+	for i, tag := range tags {
+		raw, found, err := store.Get(generationKey(tag))
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		if found {
+			b.Write(raw)
+		} else {
+			b.WriteString("0")
+		}
+	}
+	return b.String(), nil
+}
+
+// Typed 带类型的缓存,后端实现 ValueStore 时直接保存值,否则使用 gob 编码后存入后端
+// 直接保存时命中返回的是共享的同一个值,调用方不能修改
+// 同一个键的并发未命中通过 singleflight 合并为一次加载
+type Typed[T any] struct {
+	prefix string
+	group  singleflight.Group
+}
+
+// NewTyped 创建带类型的缓存,prefix 用于区分不同用途的键
+func NewTyped[T any](prefix string) *Typed[T] {
+	return &Typed[T]{prefix: prefix}
+}
+
+// Get 读取缓存
+func (t *Typed[T]) Get(key string, tags ...string) (T, bool) {
+	var zero T
+	fullKey, err := taggedKey(t.prefix+":"+key, tags)
+	if err != nil {
+		log.Printf("读取缓存失败 [%s]: %v", key, err)
+		return zero, false
+	}
+	return t.get(fullKey)
+}
+
+// Set 写入缓存
+func (t *Typed[T]) Set(key string, value T, ttl time.Duration, tags ...string) {
+	fullKey, err := taggedKey(t.prefix+":"+key, tags)
+	if err != nil {
+		log.Printf("写入缓存失败 [%s]: %v", key, err)
+		return
+	}
+	t.set(fullKey, value, ttl)
+}
+
+// GetOrLoad 读取缓存,未命中时调用 load 加载并写入
+// 后端不可用时直接调用 load,不影响请求
+func (t *Typed[T]) GetOrLoad(key string, ttl time.Duration, tags []string, load func() (T, error)) (T, error) {
+	fullKey, err := taggedKey(t.prefix+":"+key, tags)
+	if err != nil {
+		log.Printf("读取缓存失败 [%s]: %v", key, err)
+		return load()
+	}
+	if value, found := t.get(fullKey); found {
+		return value, nil
+	}
+
+	result, err, _ := t.group.Do(fullKey, func() (interface{}, error) {
+		// 等待期间其他请求可能已经写入
+		if value, found := t.get(fullKey); found {
+			return value, nil
+		}
+		value, err := load()
+		if err != nil {
+			return value, err
+		}
+		t.set(fullKey, value, ttl)
+		return value, nil
+	})
+	return result.(T), err
+}
+
+func (t *Typed[T]) get(fullKey string) (T, bool) {
+	var value T
+	if values, ok := store.(ValueStore); ok {
+		cached, found := values.GetValue(fullKey)
+		if !found {
+			return value, false
+		}
+		value, ok = cached.(T)
+		return value, ok
+	}
+
+	raw, found, err := store.Get(fullKey)
+	if err != nil {
+		log.Printf("读取缓存失败 [%s]: %v", fullKey, err)
+		return value, false
+	}
+	if !found {
+		return value, false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&value); err != nil {
+		log.Printf("解码缓存失败 [%s]: %v", fullKey, err)
+		return value, false
+	}
+	return value, true
+}
+
+func (t *Typed[T]) set(fullKey string, value T, ttl time.Duration) {
+	if values, ok := store.(ValueStore); ok {
+		values.SetValue(fullKey, value, ttl)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		log.Printf("编码缓存失败 [%s]: %v", fullKey, err)
+		return
+	}
+	if err := store.Set(fullKey, buf.Bytes(), ttl); err != nil {
+		log.Printf("写入缓存失败 [%s]: %v", fullKey, err)
+	}
+}
+
+// formatGeneration 计数器以十进制文本保存,与 Redis INCR 的格式一致
+func formatGeneration(n int64) []byte {
+	return []byte(strconv.FormatInt(n, 10))
 }
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// forEachBackend 分别在进程内缓存和 Redis 后端上运行测试
+func forEachBackend(t *testing.T, test func(t *testing.T)) {
+	t.Run("memory", func(t *testing.T) {
+		InitCache(NewMemoryStore(time.Minute, 2*time.Minute))
+		test(t)
+	})
+	t.Run("redis", func(t *testing.T) {
+		InitCache(NewRedisStoreWithClient(newFakeRedis(t)))
+		test(t)
+	})
+}
+
+func TestTypedTagInvalidation(t *testing.T) {
+	forEachBackend(t, func(t *testing.T) {
+		typed := NewTyped[[]byte]("test")
+		typed.Set("fetch", []byte("a"), time.Minute, TagFetch)
+		typed.Set("monitors", []byte("b"), time.Minute, TagMonitors)
+		typed.Set("both", []byte("c"), time.Minute, TagFetch, TagMonitors)
+		typed.Set("untagged", []byte("d"), time.Minute)
+
+		if value, found := typed.Get("fetch", TagFetch); !found || string(value) != "a" {
+			t.Fatalf("写入后应命中,实际 %q, %v", value, found)
+		}
+
+		NextGeneration()
+		if _, found := typed.Get("fetch", TagFetch); found {
+			t.Error("新的获取周期开始后 TagFetch 缓存应失效")
+		}
+		if _, found := typed.Get("both", TagFetch, TagMonitors); found {
+			t.Error("任一标签失效后缓存都应失效")
+		}
+		if value, found := typed.Get("monitors", TagMonitors); !found || string(value) != "b" {
+			t.Error("其他标签的缓存不应失效")
+		}
+		if _, found := typed.Get("untagged"); !found {
+			t.Error("不带标签的缓存不应失效")
+		}
+
+		// 失效后写入的新条目使用新的代际
+		typed.Set("fetch", []byte("e"), time.Minute, TagFetch)
+		if value, found := typed.Get("fetch", TagFetch); !found || string(value) != "e" {
+			t.Errorf("失效后重新写入应命中,实际 %q, %v", value, found)
+		}
+
+		Invalidate(TagMonitors)
+		if _, found := typed.Get("monitors", TagMonitors); found {
+			t.Error("Invalidate 后缓存应失效")
+		}
+	})
+}
+
+func TestTypedExpiration(t *testing.T) {
+	forEachBackend(t, func(t *testing.T) {
+		typed := NewTyped[[]byte]("test")
+		typed.Set("short", []byte("a"), 50*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		if _, found := typed.Get("short"); found {
+			t.Error("超过 TTL 后应未命中")
+		}
+	})
+}
+
+// TestGetOrLoadSingleflight 并发未命中只调用一次 load,其余请求共享结果
+func TestGetOrLoadSingleflight(t *testing.T) {
+	forEachBackend(t, func(t *testing.T) {
+		typed := NewTyped[*Payload]("test")
+		const concurrency = 20
+
+		var loads atomic.Int32
+		var started sync.WaitGroup
+		started.Add(concurrency)
+		release := make(chan struct{})
+		load := func() (*Payload, error) {
+			loads.Add(1)
+			<-release
+			return NewPayload("text/plain", []byte("body"), []byte("body"), time.Now())
+		}
+
+		var done sync.WaitGroup
+		results := make([]*Payload, concurrency)
+		for i := 0; i < concurrency; i++ {
+			done.Add(1)
+			go func(i int) {
+				defer done.Done()
+				started.Done()
+				payload, err := typed.GetOrLoad("key", time.Minute, []string{TagFetch}, load)
+				if err != nil {
+					t.Error(err)
+				}
+				results[i] = payload
+			}(i)
+		}
+		started.Wait()
+		// 等待所有请求进入 GetOrLoad 后再完成加载
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		done.Wait()
+
+		if n := loads.Load(); n != 1 {
+			t.Fatalf("load 被调用 %d 次,应为 1 次", n)
+		}
+		for _, payload := range results {
+			if payload == nil || string(payload.Identity) != "body" {
+				t.Fatalf("请求没有得到加载结果: %+v", payload)
+			}
+		}
+
+		// 之后的请求命中缓存,不再加载
+		if _, err := typed.GetOrLoad("key", time.Minute, []string{TagFetch}, load); err != nil {
+			t.Fatal(err)
+		}
+		if n := loads.Load(); n != 1 {
+			t.Fatalf("命中缓存时不应调用 load,共调用 %d 次", n)
+		}
+	})
+}
+
+// TestMemoryStoreKeepsValues 进程内缓存命中时返回写入的同一个值,不经过 gob 编解码
+// Redis 后端需要编码,命中时得到内容相同的新值
+func TestMemoryStoreKeepsValues(t *testing.T) {
+	payload, err := NewPayload("text/plain", []byte("body"), []byte("body"), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	InitCache(NewMemoryStore(time.Minute, 2*time.Minute))
+	typed := NewTyped[*Payload]("test")
+	typed.Set("key", payload, time.Minute)
+	if got, found := typed.Get("key"); !found || got != payload {
+		t.Fatalf("进程内缓存应返回同一个值,实际 %p, %v", got, found)
+	}
+
+	InitCache(NewRedisStoreWithClient(newFakeRedis(t)))
+	typed.Set("key", payload, time.Minute)
+	got, found := typed.Get("key")
+	if !found || got == payload || got.ETag != payload.ETag || string(got.Identity) != "body" {
+		t.Fatalf("Redis 后端应解码出内容相同的新值,实际 %+v, %v", got, found)
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// memoryStore 进程内缓存,基于 go-cache
+// 实现 ValueStore,Typed 缓存的值直接保存,Get/Set 只用于字节值(如代际计数器)
+type memoryStore struct {
+	mu    sync.Mutex // 保护计数器的读-改-写
+	cache *gocache.Cache
+}
+
+// NewMemoryStore 创建进程内缓存后端
+func NewMemoryStore(defaultExpiration, cleanupInterval time.Duration) Store {
+	return &memoryStore{cache: gocache.New(defaultExpiration, cleanupInterval)}
+}
+
+func (m *memoryStore) Get(key string) ([]byte, bool, error) {
+	value, found := m.cache.Get(key)
+	if !found {
+		return nil, false, nil
+	}
+	raw, ok := value.([]byte)
+	if !ok {
+		return nil, false, nil
+	}
+	return raw, true, nil
+}
+
+func (m *memoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	m.cache.Set(key, value, ttl)
+	return nil
+}
+
+func (m *memoryStore) Delete(key string) error {
+	m.cache.Delete(key)
+	return nil
+}
+
+func (m *memoryStore) Incr(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int64
+	if raw, found := m.cache.Get(key); found {
+		n, _ = strconv.ParseInt(string(raw.([]byte)), 10, 64)
+	}
+	n++
+	m.cache.Set(key, formatGeneration(n), gocache.NoExpiration)
+	return n, nil
+}
+
+func (m *memoryStore) Flush() error {
+	m.cache.Flush()
+	return nil
+}
+
+func (m *memoryStore) GetValue(key string) (interface{}, bool) {
+	return m.cache.Get(key)
+}
+
+func (m *memoryStore) SetValue(key string, value interface{}, ttl time.Duration) {
+	m.cache.Set(key, value, ttl)
+}
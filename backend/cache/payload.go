@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minCompressSize 小于该长度的响应不压缩
+const minCompressSize = 512
+
+// Payload 预先序列化并压缩的响应,缓存命中时无需重新编码
+type Payload struct {
+	ContentType  string
+	Identity     []byte
+	Gzip         []byte // 为 nil 表示未压缩
+	Brotli       []byte // 为 nil 表示未压缩
+	ETag         string // 不含引号和编码后缀的内容摘要,不随响应时间戳变化
+	LastModified time.Time
+}
+
+// NewPayload 根据序列化后的响应体生成 Payload,同时生成 gzip 和 brotli 压缩版本
+// ETag 取 content 的摘要,content 应排除时间戳等每次序列化都会变化的部分,数据不变时 ETag 保持不变
+func NewPayload(contentType string, body, content []byte, lastModified time.Time) (*Payload, error) {
+	sum := sha256.Sum256(content)
+	payload := &Payload{
+		ContentType:  contentType,
+		Identity:     body,
+		ETag:         hex.EncodeToString(sum[:16]),
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}
+	if len(body) < minCompressSize {
+		return payload, nil
+	}
+
+	var gz bytes.Buffer
+	gzipWriter, err := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gzipWriter.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	payload.Gzip = gz.Bytes()
+
+	var br bytes.Buffer
+	brotliWriter := brotli.NewWriterLevel(&br, brotli.DefaultCompression)
+	if _, err := brotliWriter.Write(body); err != nil {
+		return nil, err
+	}
+	if err := brotliWriter.Close(); err != nil {
+		return nil, err
+	}
+	payload.Brotli = br.Bytes()
+
+	return payload, nil
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout 单次 Redis 操作的超时时间,超时按未命中处理
+const redisTimeout = 500 * time.Millisecond
+
+// redisStore Redis 缓存后端,多个 kuma-lite 实例可共享缓存
+type redisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// NewRedisStore 根据 redis:// 地址创建 Redis 缓存后端
+func NewRedisStore(url string) (Store, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return NewRedisStoreWithClient(client), nil
+}
+
+// NewRedisStoreWithClient 使用已有的客户端创建缓存后端(可传入指向测试用 Redis 的客户端)
+func NewRedisStoreWithClient(client redis.UniversalClient) Store {
+	return &redisStore{client: client, keyPrefix: "kuma-lite:"}
+}
+
+func (r *redisStore) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, r.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *redisStore) Set(key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return r.client.Set(ctx, r.keyPrefix+key, value, ttl).Err()
+}
+
+func (r *redisStore) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return r.client.Del(ctx, r.keyPrefix+key).Err()
+}
+
+func (r *redisStore) Incr(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return r.client.Incr(ctx, r.keyPrefix+key).Result()
+}
+
+// Flush 只删除本应用前缀下的键,不影响 Redis 中的其他数据
+func (r *redisStore) Flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	iter := r.client.Scan(ctx, 0, r.keyPrefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		if err := r.client.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis 实现缓存用到的 RESP 命令子集的内存 Redis: GET、SET(EX/PX)、DEL、INCR、SCAN
+// 其他命令(如连接时的 HELLO)返回错误,客户端会退回 RESP2
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+// newFakeRedis 启动监听本地端口的 fakeRedis,返回连接到它的客户端
+func newFakeRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeRedis{values: make(map[string]string), expires: make(map[string]time.Time)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), DisableIndentity: true})
+	t.Cleanup(func() {
+		client.Close()
+		listener.Close()
+	})
+	return client
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand 读取一条 RESP 数组形式的命令
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("不支持的请求: %q", line)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := f.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulkString(value)
+	case "SET":
+		f.values[args[1]] = args[2]
+		delete(f.expires, args[1])
+		if len(args) == 5 {
+			n, _ := strconv.Atoi(args[4])
+			unit := time.Second
+			if strings.EqualFold(args[3], "px") {
+				unit = time.Millisecond
+			}
+			f.expires[args[1]] = time.Now().Add(time.Duration(n) * unit)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := f.lookup(key); ok {
+				deleted++
+			}
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+		return ":" + strconv.Itoa(deleted) + "\r\n"
+	case "INCR":
+		value, _ := f.lookup(args[1])
+		n, _ := strconv.ParseInt(value, 10, 64)
+		n++
+		f.values[args[1]] = strconv.FormatInt(n, 10)
+		return ":" + strconv.FormatInt(n, 10) + "\r\n"
+	case "SCAN":
+		// 一次返回所有匹配的键,游标固定为 0
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.EqualFold(args[i], "match") {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range f.values {
+			if matched, _ := path.Match(pattern, key); matched {
+				if _, ok := f.lookup(key); ok {
+					keys = append(keys, key)
+				}
+			}
+		}
+		reply := "*2\r\n" + bulkString("0") + "*" + strconv.Itoa(len(keys)) + "\r\n"
+		for _, key := range keys {
+			reply += bulkString(key)
+		}
+		return reply
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// lookup 读取键,已过期的键视为不存在并删除
+func (f *fakeRedis) lookup(key string) (string, bool) {
+	if expires, ok := f.expires[key]; ok && !time.Now().Before(expires) {
+		delete(f.values, key)
+		delete(f.expires, key)
+	}
+	value, ok := f.values[key]
+	return value, ok
+}
+
+func bulkString(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+// TestRedisStoreFlushKeepsOtherKeys Flush 只删除本应用前缀下的键
+func TestRedisStoreFlushKeepsOtherKeys(t *testing.T) {
+	client := newFakeRedis(t)
+	s := NewRedisStoreWithClient(client)
+	if err := s.Set("a", []byte("1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(context.Background(), "other:a", "1", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := s.Get("a"); found {
+		t.Error("Flush 后本应用的键仍然存在")
+	}
+	if value, _ := client.Get(context.Background(), "other:a").Result(); value != "1" {
+		t.Error("Flush 删除了其他应用的键")
+	}
+}
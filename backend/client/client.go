@@ -0,0 +1,106 @@
+// Package client 是 kuma-lite API 的 Go 客户端
+// 接口方法由 gen.go 根据 OpenAPI 文档生成,修改接口后执行 go generate 更新
+package client
+
+//go:generate go run gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client kuma-lite API 客户端
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	AdminToken string // 调用管理接口时以 Bearer 令牌发送
+}
+
+// New 创建客户端,baseURL 形如 http://localhost:8080
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// APIError 接口返回的错误
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kuma-lite API 错误 (HTTP %d): %s", e.StatusCode, e.Message)
+}
+
+// envelope 与 models.APIResponse 对应,data 延迟解析为具体类型
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Meta    json.RawMessage `json:"meta"`
+	Error   string          `json:"error"`
+}
+
+// do 发送请求并将 data 字段解析到 out,meta 不为 nil 时解析分页信息
+// payload 不为 nil 时序列化为 JSON 请求体
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, payload, out, meta interface{}) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.AdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AdminToken)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body envelope
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return &APIError{StatusCode: resp.StatusCode, Message: "解析响应失败: " + err.Error()}
+	}
+	if resp.StatusCode >= http.StatusBadRequest || !body.Success {
+		return &APIError{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+
+	if out != nil && len(body.Data) > 0 {
+		if err := json.Unmarshal(body.Data, out); err != nil {
+			return fmt.Errorf("解析 data 失败: %w", err)
+		}
+	}
+	if meta != nil && len(body.Meta) > 0 {
+		if err := json.Unmarshal(body.Meta, meta); err != nil {
+			return fmt.Errorf("解析 meta 失败: %w", err)
+		}
+	}
+	return nil
+}
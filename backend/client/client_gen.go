@@ -0,0 +1,810 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"kuma-lite/backend/models"
+	"net/url"
+	"strconv"
+)
+
+// PostComposite 创建组合监控项
+// POST /api/admin/composites
+func (c *Client) PostComposite(ctx context.Context, body models.CompositeMonitor) (*models.CompositeMonitor, error) {
+	path := "/api/admin/composites"
+	query := url.Values{}
+	var out *models.CompositeMonitor
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteComposite 删除组合监控项
+// DELETE /api/admin/composites/{id}
+func (c *Client) DeleteComposite(ctx context.Context, id int) (*models.CompositeMonitor, error) {
+	path := "/api/admin/composites/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.CompositeMonitor
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutComposite 更新组合监控项
+// PUT /api/admin/composites/{id}
+func (c *Client) PutComposite(ctx context.Context, id int, body models.CompositeMonitor) (*models.CompositeMonitor, error) {
+	path := "/api/admin/composites/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.CompositeMonitor
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// PostDependency 声明监控项依赖关系
+// POST /api/admin/dependencies
+func (c *Client) PostDependency(ctx context.Context, body models.MonitorDependency) (*models.MonitorDependency, error) {
+	path := "/api/admin/dependencies"
+	query := url.Values{}
+	var out *models.MonitorDependency
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteDependency 删除监控项依赖关系
+// DELETE /api/admin/dependencies/{id}
+func (c *Client) DeleteDependency(ctx context.Context, id int) (*models.MonitorDependency, error) {
+	path := "/api/admin/dependencies/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.MonitorDependency
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// DeleteGroupOverrideParams DeleteGroupOverride 的查询参数,零值字段不发送
+type DeleteGroupOverrideParams struct {
+	// 分组名
+	Group string
+}
+
+// DeleteGroupOverride 删除分组顺序覆盖
+// DELETE /api/admin/group-overrides
+func (c *Client) DeleteGroupOverride(ctx context.Context, params *DeleteGroupOverrideParams) (*models.GroupOverride, error) {
+	path := "/api/admin/group-overrides"
+	query := url.Values{}
+	if params != nil {
+		if params.Group != "" {
+			query.Set("group", params.Group)
+		}
+	}
+	var out *models.GroupOverride
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetGroupOverrides 获取分组顺序覆盖
+// GET /api/admin/group-overrides
+func (c *Client) GetGroupOverrides(ctx context.Context) ([]models.GroupOverride, error) {
+	path := "/api/admin/group-overrides"
+	query := url.Values{}
+	var out []models.GroupOverride
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutGroupOverride 设置分组顺序覆盖
+// PUT /api/admin/group-overrides
+func (c *Client) PutGroupOverride(ctx context.Context, body models.GroupOverride) (*models.GroupOverride, error) {
+	path := "/api/admin/group-overrides"
+	query := url.Values{}
+	var out *models.GroupOverride
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// GetLatencyThresholds 获取响应时间阈值
+// GET /api/admin/latency-thresholds
+func (c *Client) GetLatencyThresholds(ctx context.Context) ([]models.LatencyThreshold, error) {
+	path := "/api/admin/latency-thresholds"
+	query := url.Values{}
+	var out []models.LatencyThreshold
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutLatencyThreshold 设置响应时间阈值
+// PUT /api/admin/latency-thresholds
+func (c *Client) PutLatencyThreshold(ctx context.Context, body models.LatencyThreshold) (*models.LatencyThreshold, error) {
+	path := "/api/admin/latency-thresholds"
+	query := url.Values{}
+	var out *models.LatencyThreshold
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteLatencyThreshold 删除响应时间阈值
+// DELETE /api/admin/latency-thresholds/{id}
+func (c *Client) DeleteLatencyThreshold(ctx context.Context, id int) (*models.LatencyThreshold, error) {
+	path := "/api/admin/latency-thresholds/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.LatencyThreshold
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetNotificationChannels 获取通知渠道
+// GET /api/admin/notification-channels
+func (c *Client) GetNotificationChannels(ctx context.Context) ([]models.NotificationChannel, error) {
+	path := "/api/admin/notification-channels"
+	query := url.Values{}
+	var out []models.NotificationChannel
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PostNotificationChannel 创建通知渠道
+// POST /api/admin/notification-channels
+func (c *Client) PostNotificationChannel(ctx context.Context, body models.NotificationChannel) (*models.NotificationChannel, error) {
+	path := "/api/admin/notification-channels"
+	query := url.Values{}
+	var out *models.NotificationChannel
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteNotificationChannel 删除通知渠道
+// DELETE /api/admin/notification-channels/{id}
+func (c *Client) DeleteNotificationChannel(ctx context.Context, id int) (*models.NotificationChannel, error) {
+	path := "/api/admin/notification-channels/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.NotificationChannel
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutNotificationChannel 更新通知渠道
+// PUT /api/admin/notification-channels/{id}
+func (c *Client) PutNotificationChannel(ctx context.Context, id int, body models.NotificationChannel) (*models.NotificationChannel, error) {
+	path := "/api/admin/notification-channels/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.NotificationChannel
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// TestNotificationChannel 发送测试通知
+// POST /api/admin/notification-channels/{id}/test
+func (c *Client) TestNotificationChannel(ctx context.Context, id int) (*models.NotificationChannel, error) {
+	path := "/api/admin/notification-channels/" + url.PathEscape(strconv.Itoa(id)) + "/test"
+	query := url.Values{}
+	var out *models.NotificationChannel
+	err := c.do(ctx, "POST", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetNotificationDeliveriesParams GetNotificationDeliveries 的查询参数,零值字段不发送
+type GetNotificationDeliveriesParams struct {
+	// 通知渠道 ID 过滤
+	Channel int
+	// 故障事件 ID 过滤
+	Incident int
+	// 发送状态过滤: pending、deferred、sent、failed、suppressed
+	Status string
+	// 查询最近多少天,默认 7,最大 90
+	Days int
+	// 返回数量,默认 100,最大 500
+	Limit int
+}
+
+// GetNotificationDeliveries 获取通知发送历史
+// GET /api/admin/notification-deliveries
+func (c *Client) GetNotificationDeliveries(ctx context.Context, params *GetNotificationDeliveriesParams) ([]models.NotificationDelivery, error) {
+	path := "/api/admin/notification-deliveries"
+	query := url.Values{}
+	if params != nil {
+		if params.Channel != 0 {
+			query.Set("channel", strconv.Itoa(params.Channel))
+		}
+		if params.Incident != 0 {
+			query.Set("incident", strconv.Itoa(params.Incident))
+		}
+		if params.Status != "" {
+			query.Set("status", params.Status)
+		}
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out []models.NotificationDelivery
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetNotificationRules 获取通知路由规则
+// GET /api/admin/notification-rules
+func (c *Client) GetNotificationRules(ctx context.Context) ([]models.NotificationRule, error) {
+	path := "/api/admin/notification-rules"
+	query := url.Values{}
+	var out []models.NotificationRule
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PostNotificationRule 创建通知路由规则
+// POST /api/admin/notification-rules
+func (c *Client) PostNotificationRule(ctx context.Context, body models.NotificationRule) (*models.NotificationRule, error) {
+	path := "/api/admin/notification-rules"
+	query := url.Values{}
+	var out *models.NotificationRule
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteNotificationRule 删除通知路由规则
+// DELETE /api/admin/notification-rules/{id}
+func (c *Client) DeleteNotificationRule(ctx context.Context, id int) (*models.NotificationRule, error) {
+	path := "/api/admin/notification-rules/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.NotificationRule
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutNotificationRule 更新通知路由规则
+// PUT /api/admin/notification-rules/{id}
+func (c *Client) PutNotificationRule(ctx context.Context, id int, body models.NotificationRule) (*models.NotificationRule, error) {
+	path := "/api/admin/notification-rules/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.NotificationRule
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// GetMonitorOverrides 获取监控项展示覆盖
+// GET /api/admin/overrides
+func (c *Client) GetMonitorOverrides(ctx context.Context) ([]models.MonitorOverride, error) {
+	path := "/api/admin/overrides"
+	query := url.Values{}
+	var out []models.MonitorOverride
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// DeleteMonitorOverride 删除监控项展示覆盖
+// DELETE /api/admin/overrides/{id}
+func (c *Client) DeleteMonitorOverride(ctx context.Context, id int) (*models.MonitorOverride, error) {
+	path := "/api/admin/overrides/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.MonitorOverride
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutMonitorOverride 设置监控项展示覆盖
+// PUT /api/admin/overrides/{id}
+func (c *Client) PutMonitorOverride(ctx context.Context, id int, body models.MonitorOverride) (*models.MonitorOverride, error) {
+	path := "/api/admin/overrides/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.MonitorOverride
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// PostSLO 创建 SLO
+// POST /api/admin/slos
+func (c *Client) PostSLO(ctx context.Context, body models.SLO) (*models.SLO, error) {
+	path := "/api/admin/slos"
+	query := url.Values{}
+	var out *models.SLO
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// DeleteSLO 删除 SLO
+// DELETE /api/admin/slos/{id}
+func (c *Client) DeleteSLO(ctx context.Context, id int) (*models.SLO, error) {
+	path := "/api/admin/slos/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.SLO
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PutSLO 更新 SLO
+// PUT /api/admin/slos/{id}
+func (c *Client) PutSLO(ctx context.Context, id int, body models.SLO) (*models.SLO, error) {
+	path := "/api/admin/slos/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.SLO
+	err := c.do(ctx, "PUT", path, query, body, &out, nil)
+	return out, err
+}
+
+// GetSubscribers 获取邮件订阅者
+// GET /api/admin/subscribers
+func (c *Client) GetSubscribers(ctx context.Context) ([]models.Subscriber, error) {
+	path := "/api/admin/subscribers"
+	query := url.Values{}
+	var out []models.Subscriber
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// DeleteSubscriber 删除邮件订阅者
+// DELETE /api/admin/subscribers/{id}
+func (c *Client) DeleteSubscriber(ctx context.Context, id int) (*models.Subscriber, error) {
+	path := "/api/admin/subscribers/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	var out *models.Subscriber
+	err := c.do(ctx, "DELETE", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetCertificatesParams GetCertificates 的查询参数,零值字段不发送
+type GetCertificatesParams struct {
+	// 剩余天数不超过该值时标记为即将到期,默认 30
+	Days int
+}
+
+// GetCertificates 获取证书到期情况
+// GET /api/certificates
+func (c *Client) GetCertificates(ctx context.Context, params *GetCertificatesParams) ([]models.CertificateStatus, error) {
+	path := "/api/certificates"
+	query := url.Values{}
+	if params != nil {
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+	}
+	var out []models.CertificateStatus
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetComposites 获取组合监控项定义
+// GET /api/composites
+func (c *Client) GetComposites(ctx context.Context) ([]models.CompositeMonitor, error) {
+	path := "/api/composites"
+	query := url.Values{}
+	var out []models.CompositeMonitor
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetDependencies 获取监控项依赖图
+// GET /api/dependencies
+func (c *Client) GetDependencies(ctx context.Context) (*models.DependencyGraph, error) {
+	path := "/api/dependencies"
+	query := url.Values{}
+	var out *models.DependencyGraph
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetEventsParams GetEvents 的查询参数,零值字段不发送
+type GetEventsParams struct {
+	// 事件类型过滤,逗号分隔
+	Kind string
+	// 只返回指定监控项的事件
+	Monitor int
+	// 时间范围(天),默认 7,最大 90
+	Days int
+	// 返回条数,默认 100,最大 500
+	Limit int
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetEvents 获取事件列表
+// GET /api/events
+func (c *Client) GetEvents(ctx context.Context, params *GetEventsParams) ([]models.Event, error) {
+	path := "/api/events"
+	query := url.Values{}
+	if params != nil {
+		if params.Kind != "" {
+			query.Set("kind", params.Kind)
+		}
+		if params.Monitor != 0 {
+			query.Set("monitor", strconv.Itoa(params.Monitor))
+		}
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.Event
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// HealthCheck 健康检查
+// GET /api/health
+func (c *Client) HealthCheck(ctx context.Context) (*models.HealthStatus, error) {
+	path := "/api/health"
+	query := url.Values{}
+	var out *models.HealthStatus
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetSourceHealth 获取数据源健康状况
+// GET /api/health/source
+func (c *Client) GetSourceHealth(ctx context.Context) (*models.SourceHealth, error) {
+	path := "/api/health/source"
+	query := url.Values{}
+	var out *models.SourceHealth
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetMonitorsParams GetMonitors 的查询参数,零值字段不发送
+type GetMonitorsParams struct {
+	// 按 effectiveStatus 过滤,逗号分隔,可用数字或 down/up/pending/maintenance/impacted/degraded
+	Status string
+	// 分组过滤
+	Group string
+	// 监控类型过滤
+	Type string
+	// 名称子串过滤,不区分大小写
+	Name string
+	// 数据来源过滤
+	Source string
+	// 传 archived 时包含已从 Kuma 移除的归档监控项
+	Include string
+	// 标签过滤,逗号分隔,name 或 name:value,需同时满足
+	Tag string
+	// 排序字段: name, uptime, responseTime, group,默认按 ID
+	Sort string
+	// 排序方向: asc(默认)或 desc
+	Order string
+	// 每页数量,最大 500,不传时不分页
+	Limit int
+	// 起始位置
+	Offset int
+	// 上一页返回的 meta.nextCursor,优先于 offset
+	Cursor string
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitors 获取监控项列表
+// GET /api/monitors
+func (c *Client) GetMonitors(ctx context.Context, params *GetMonitorsParams) ([]models.Monitor, *models.Pagination, error) {
+	path := "/api/monitors"
+	query := url.Values{}
+	if params != nil {
+		if params.Status != "" {
+			query.Set("status", params.Status)
+		}
+		if params.Group != "" {
+			query.Set("group", params.Group)
+		}
+		if params.Type != "" {
+			query.Set("type", params.Type)
+		}
+		if params.Name != "" {
+			query.Set("name", params.Name)
+		}
+		if params.Source != "" {
+			query.Set("source", params.Source)
+		}
+		if params.Include != "" {
+			query.Set("include", params.Include)
+		}
+		if params.Tag != "" {
+			query.Set("tag", params.Tag)
+		}
+		if params.Sort != "" {
+			query.Set("sort", params.Sort)
+		}
+		if params.Order != "" {
+			query.Set("order", params.Order)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Cursor != "" {
+			query.Set("cursor", params.Cursor)
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.Monitor
+	var meta *models.Pagination
+	err := c.do(ctx, "GET", path, query, nil, &out, &meta)
+	return out, meta, err
+}
+
+// GetMonitorByIDParams GetMonitorByID 的查询参数,零值字段不发送
+type GetMonitorByIDParams struct {
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitorByID 获取单个监控项
+// GET /api/monitors/{id}
+func (c *Client) GetMonitorByID(ctx context.Context, id int, params *GetMonitorByIDParams) (*models.Monitor, error) {
+	path := "/api/monitors/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	if params != nil {
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out *models.Monitor
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetMonitorAnomaliesParams GetMonitorAnomalies 的查询参数,零值字段不发送
+type GetMonitorAnomaliesParams struct {
+	// 时间范围(天),默认 7,最大 90
+	Days int
+	// 返回条数,默认 100,最大 500
+	Limit int
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitorAnomalies 获取监控项响应时间异常
+// GET /api/monitors/{id}/anomalies
+func (c *Client) GetMonitorAnomalies(ctx context.Context, id int, params *GetMonitorAnomaliesParams) ([]models.LatencyAnomaly, error) {
+	path := "/api/monitors/" + url.PathEscape(strconv.Itoa(id)) + "/anomalies"
+	query := url.Values{}
+	if params != nil {
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.LatencyAnomaly
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetMonitorChangesParams GetMonitorChanges 的查询参数,零值字段不发送
+type GetMonitorChangesParams struct {
+	// 返回条数,默认 100,最大 500
+	Limit int
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitorChanges 获取监控项变更记录
+// GET /api/monitors/{id}/changes
+func (c *Client) GetMonitorChanges(ctx context.Context, id int, params *GetMonitorChangesParams) ([]models.MonitorChange, error) {
+	path := "/api/monitors/" + url.PathEscape(strconv.Itoa(id)) + "/changes"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.MonitorChange
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetMonitorFlapsParams GetMonitorFlaps 的查询参数,零值字段不发送
+type GetMonitorFlapsParams struct {
+	// 时间范围(天),默认 7,最大 90
+	Days int
+	// 返回条数,默认 100,最大 500
+	Limit int
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitorFlaps 获取监控项状态抖动记录
+// GET /api/monitors/{id}/flaps
+func (c *Client) GetMonitorFlaps(ctx context.Context, id int, params *GetMonitorFlapsParams) ([]models.FlapPeriod, error) {
+	path := "/api/monitors/" + url.PathEscape(strconv.Itoa(id)) + "/flaps"
+	query := url.Values{}
+	if params != nil {
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.FlapPeriod
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetMonitorHistoryParams GetMonitorHistory 的查询参数,零值字段不发送
+type GetMonitorHistoryParams struct {
+	// 获取最近 N 条记录,优先于 hours
+	Limit int
+	// 获取最近 N 小时的记录,默认 24
+	Hours int
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetMonitorHistory 获取监控历史
+// GET /api/monitors/{id}/history
+func (c *Client) GetMonitorHistory(ctx context.Context, id int, params *GetMonitorHistoryParams) ([]models.HeartBeat, error) {
+	path := "/api/monitors/" + url.PathEscape(strconv.Itoa(id)) + "/history"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Hours != 0 {
+			query.Set("hours", strconv.Itoa(params.Hours))
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.HeartBeat
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetReliabilityParams GetReliability 的查询参数,零值字段不发送
+type GetReliabilityParams struct {
+	// 开始时间,RFC 3339 或 YYYY-MM-DD
+	From string
+	// 结束时间,RFC 3339 或 YYYY-MM-DD(包含当天),默认当前时间
+	To string
+	// 未指定 from 时的天数,默认 30,最大 366
+	Days int
+	// 只统计指定分组
+	Group string
+	// 只统计指定监控项
+	Monitor int
+	// csv 时导出 CSV
+	Format string
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetReliability 获取 MTTR、MTBF 等可靠性指标
+// GET /api/reliability
+func (c *Client) GetReliability(ctx context.Context, params *GetReliabilityParams) (*models.ReliabilityReport, error) {
+	path := "/api/reliability"
+	query := url.Values{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.Group != "" {
+			query.Set("group", params.Group)
+		}
+		if params.Monitor != 0 {
+			query.Set("monitor", strconv.Itoa(params.Monitor))
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out *models.ReliabilityReport
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetSLOsParams GetSLOs 的查询参数,零值字段不发送
+type GetSLOsParams struct {
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetSLOs 获取 SLO 及错误预算、燃烧率
+// GET /api/slos
+func (c *Client) GetSLOs(ctx context.Context, params *GetSLOsParams) ([]models.SLOReport, error) {
+	path := "/api/slos"
+	query := url.Values{}
+	if params != nil {
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out []models.SLOReport
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetSLOParams GetSLO 的查询参数,零值字段不发送
+type GetSLOParams struct {
+	// 输出时间使用的 IANA 时区,如 Asia/Shanghai,默认 UTC
+	Tz string
+}
+
+// GetSLO 获取单个 SLO
+// GET /api/slos/{id}
+func (c *Client) GetSLO(ctx context.Context, id int, params *GetSLOParams) (*models.SLOReport, error) {
+	path := "/api/slos/" + url.PathEscape(strconv.Itoa(id))
+	query := url.Values{}
+	if params != nil {
+		if params.Tz != "" {
+			query.Set("tz", params.Tz)
+		}
+	}
+	var out *models.SLOReport
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// GetStats 获取统计信息
+// GET /api/stats
+func (c *Client) GetStats(ctx context.Context) (*models.Stats, error) {
+	path := "/api/stats"
+	query := url.Values{}
+	var out *models.Stats
+	err := c.do(ctx, "GET", path, query, nil, &out, nil)
+	return out, err
+}
+
+// PostSubscription 订阅状态更新邮件
+// POST /api/subscriptions
+func (c *Client) PostSubscription(ctx context.Context, body models.SubscriptionRequest) (*models.SubscriptionRequest, error) {
+	path := "/api/subscriptions"
+	query := url.Values{}
+	var out *models.SubscriptionRequest
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// PostSubscriptionConfirm 确认订阅
+// POST /api/subscriptions/confirm
+func (c *Client) PostSubscriptionConfirm(ctx context.Context, body models.SubscriptionToken) (*models.SubscriptionRequest, error) {
+	path := "/api/subscriptions/confirm"
+	query := url.Values{}
+	var out *models.SubscriptionRequest
+	err := c.do(ctx, "POST", path, query, body, &out, nil)
+	return out, err
+}
+
+// PostUnsubscribeParams PostUnsubscribe 的查询参数,零值字段不发送
+type PostUnsubscribeParams struct {
+	// 邮件中的退订令牌
+	Token string
+}
+
+// PostUnsubscribe 退订
+// POST /api/subscriptions/unsubscribe
+func (c *Client) PostUnsubscribe(ctx context.Context, params *PostUnsubscribeParams) (*models.SubscriptionRequest, error) {
+	path := "/api/subscriptions/unsubscribe"
+	query := url.Values{}
+	if params != nil {
+		if params.Token != "" {
+			query.Set("token", params.Token)
+		}
+	}
+	var out *models.SubscriptionRequest
+	err := c.do(ctx, "POST", path, query, nil, &out, nil)
+	return out, err
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedClientUpToDate 重新生成客户端代码,与提交的 client_gen.go 不一致说明修改接口后没有执行 go generate
+func TestGeneratedClientUpToDate(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("找不到 go 命令")
+	}
+
+	output := filepath.Join(t.TempDir(), "client_gen.go")
+	cmd := exec.Command(goBin, "run", "gen.go", "-o", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("执行 gen.go 失败: %v\n%s", err, out)
+	}
+
+	generated, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	committed, err := os.ReadFile("client_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, committed) {
+		t.Fatal("client_gen.go 与 OpenAPI 文档不一致,请在 backend/client 目录执行 go generate")
+	}
+}
@@ -0,0 +1,255 @@
+//go:build ignore
+
+// gen.go 根据 api.OpenAPISpec() 生成 client_gen.go
+// 用法: 在 backend/client 目录执行 go generate,-o 指定输出文件
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"kuma-lite/backend/api"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type spec struct {
+	Paths map[string]map[string]operation `json:"paths"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema struct {
+				GoType string `json:"x-go-type"`
+			} `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema struct {
+				AllOf []struct {
+					Properties map[string]struct {
+						GoType string `json:"x-go-type"`
+					} `json:"properties"`
+				} `json:"allOf"`
+			} `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description"`
+	Schema      struct {
+		Type string `json:"type"`
+	} `json:"schema"`
+}
+
+// goTypes OpenAPI 基本类型对应的 Go 类型
+var goTypes = map[string]string{
+	"integer": "int",
+	"number":  "float64",
+	"string":  "string",
+	"boolean": "bool",
+}
+
+func main() {
+	output := flag.String("o", "client_gen.go", "输出文件")
+	flag.Parse()
+
+	raw, err := json.Marshal(api.OpenAPISpec())
+	if err != nil {
+		log.Fatalf("序列化 OpenAPI 文档失败: %v", err)
+	}
+	var doc spec
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		log.Fatalf("解析 OpenAPI 文档失败: %v", err)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var body bytes.Buffer
+	for _, path := range paths {
+		methods := make([]string, 0, len(doc.Paths[path]))
+		for method := range doc.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			writeOperation(&body, path, strings.ToUpper(method), doc.Paths[path][method])
+		}
+	}
+
+	// 只导入实际用到的包
+	imports := []string{"context", "net/url"}
+	if bytes.Contains(body.Bytes(), []byte("models.")) {
+		imports = append(imports, "kuma-lite/backend/models")
+	}
+	if bytes.Contains(body.Bytes(), []byte("strconv.")) {
+		imports = append(imports, "strconv")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package client\n\nimport (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("格式化生成代码失败: %v\n%s", err, buf.String())
+	}
+	if err := os.WriteFile(*output, source, 0644); err != nil {
+		log.Fatalf("写入 %s 失败: %v", *output, err)
+	}
+}
+
+// writeOperation 生成单个接口的参数结构体和方法
+func writeOperation(buf *bytes.Buffer, path, method string, op operation) {
+	name := strings.ToUpper(op.OperationID[:1]) + op.OperationID[1:]
+	resultType := responseType(op, "data")
+	metaType := responseType(op, "meta")
+
+	var pathParams, queryParams []parameter
+	for _, param := range op.Parameters {
+		if param.In == "path" {
+			pathParams = append(pathParams, param)
+		} else if param.In == "query" {
+			queryParams = append(queryParams, param)
+		}
+	}
+
+	// 查询参数结构体,零值表示不传
+	if len(queryParams) > 0 {
+		fmt.Fprintf(buf, "\n// %sParams %s 的查询参数,零值字段不发送\n", name, name)
+		fmt.Fprintf(buf, "type %sParams struct {\n", name)
+		for _, param := range queryParams {
+			if param.Description != "" {
+				fmt.Fprintf(buf, "\t// %s\n", param.Description)
+			}
+			fmt.Fprintf(buf, "\t%s %s\n", fieldName(param.Name), goTypes[param.Schema.Type])
+		}
+		buf.WriteString("}\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, fmt.Sprintf("%s %s", param.Name, goTypes[param.Schema.Type]))
+	}
+	requestType := requestType(op)
+	if requestType != "" {
+		args = append(args, "body "+requestType)
+	}
+	if len(queryParams) > 0 {
+		args = append(args, fmt.Sprintf("params *%sParams", name))
+	}
+
+	fmt.Fprintf(buf, "\n// %s %s\n// %s %s\n", name, op.Summary, method, path)
+	results := resultType
+	if metaType != "" {
+		results += ", " + metaType
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), results)
+
+	// 拼接路径
+	pathExpr := fmt.Sprintf("%q", path)
+	for _, param := range pathParams {
+		value := param.Name
+		if param.Schema.Type == "integer" {
+			value = "strconv.Itoa(" + param.Name + ")"
+		}
+		placeholder := "{" + param.Name + "}"
+		pathExpr = strings.Replace(pathExpr, placeholder, `" + url.PathEscape(`+value+`) + "`, 1)
+	}
+	pathExpr = strings.TrimSuffix(strings.TrimPrefix(pathExpr, `"" + `), ` + ""`)
+	fmt.Fprintf(buf, "\tpath := %s\n", pathExpr)
+
+	buf.WriteString("\tquery := url.Values{}\n")
+	if len(queryParams) > 0 {
+		buf.WriteString("\tif params != nil {\n")
+		for _, param := range queryParams {
+			field := "params." + fieldName(param.Name)
+			switch param.Schema.Type {
+			case "integer":
+				fmt.Fprintf(buf, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.Itoa(%s))\n\t\t}\n", field, param.Name, field)
+			case "number":
+				fmt.Fprintf(buf, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.FormatFloat(%s, 'f', -1, 64))\n\t\t}\n", field, param.Name, field)
+			case "boolean":
+				fmt.Fprintf(buf, "\t\tif %s {\n\t\t\tquery.Set(%q, \"true\")\n\t\t}\n", field, param.Name)
+			default:
+				fmt.Fprintf(buf, "\t\tif %s != \"\" {\n\t\t\tquery.Set(%q, %s)\n\t\t}\n", field, param.Name, field)
+			}
+		}
+		buf.WriteString("\t}\n")
+	}
+
+	payload := "nil"
+	if requestType != "" {
+		payload = "body"
+	}
+	fmt.Fprintf(buf, "\tvar out %s\n", resultType)
+	if metaType != "" {
+		fmt.Fprintf(buf, "\tvar meta %s\n", metaType)
+		fmt.Fprintf(buf, "\terr := c.do(ctx, %q, path, query, %s, &out, &meta)\n", method, payload)
+		buf.WriteString("\treturn out, meta, err\n}\n")
+		return
+	}
+	fmt.Fprintf(buf, "\terr := c.do(ctx, %q, path, query, %s, &out, nil)\n", method, payload)
+	buf.WriteString("\treturn out, err\n}\n")
+}
+
+// responseType 从 200 响应的指定字段读取 x-go-type
+// data 字段缺失时返回 interface{},其他字段缺失时返回空字符串
+func responseType(op operation, field string) string {
+	for _, content := range op.Responses["200"].Content {
+		for _, part := range content.Schema.AllOf {
+			if prop, ok := part.Properties[field]; ok && prop.GoType != "" {
+				return prop.GoType
+			}
+		}
+	}
+	if field == "data" {
+		return "interface{}"
+	}
+	return ""
+}
+
+// requestType 读取请求体的 x-go-type,没有请求体时返回空字符串
+func requestType(op operation) string {
+	if op.RequestBody == nil {
+		return ""
+	}
+	for _, content := range op.RequestBody.Content {
+		return content.Schema.GoType
+	}
+	return ""
+}
+
+// fieldName 查询参数名转换为导出的字段名,如 group_id -> GroupID
+func fieldName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	for i, part := range parts {
+		if part == "id" {
+			parts[i] = "ID"
+			continue
+		}
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
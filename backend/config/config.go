@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,15 +19,75 @@ type Config struct {
 	// 服务器配置
 	ServerPort string
 
+	// 对外展示的状态页名称
+	StatusPageName string
+
+	// 管理接口令牌,为空时禁用 /api/admin 接口
+	AdminToken string
+
 	// 缓存配置
 	CacheDuration time.Duration
 	FetchInterval time.Duration
+	CacheBackend  string // memory 或 redis
+	RedisURL      string
+
+	// Kuma 认证和连接配置
+	KumaBasicUser     string
+	KumaBasicPassword string
+	KumaBearerToken   string
+	KumaHeaders       map[string]string // 附加请求头,如 Cloudflare Access 的 CF-Access-Client-Id
+	KumaProxyURL      string            // http(s):// 或 socks5:// 代理,为空时使用 HTTP(S)_PROXY 环境变量
+	KumaCAFile        string            // 额外信任的 CA 证书(PEM)
+	KumaClientCert    string            // mTLS 客户端证书(PEM)
+	KumaClientKey     string            // mTLS 客户端私钥(PEM)
+	KumaTLSInsecure   bool              // 跳过 TLS 证书校验,仅用于测试环境
+
+	// Kuma 时间解析配置
+	KumaLocation     *time.Location // Kuma 时间所在的时区,nil 表示自动检测
+	StrictTimestamps bool           // 时间无法解析的心跳也不用于监控项当前状态
+
+	// Kuma 请求配置
+	FetchTimeout      time.Duration // 单次请求超时
+	FetchRetries      int           // 临时错误的重试次数
+	FetchRetryBackoff time.Duration // 首次重试的基准等待时间,之后指数增长
+	BreakerThreshold  int           // 连续失败多少次后熔断,0 表示不熔断
+	BreakerCooldown   time.Duration // 熔断后暂停请求的时长
+
+	// 距离最近一次成功获取超过该时长视为数据过期
+	StaleThreshold time.Duration
+
+	// 响应时间基线的统计窗口,用于相对阈值
+	LatencyBaselineWindow time.Duration
+
+	// 响应时间异常检测
+	AnomalyThreshold    float64 // 稳健 z 分数超过该值视为异常,0 表示不检测
+	AnomalyBaselineDays int     // 学习同时段响应时间的历史天数
+
+	// 状态抖动检测
+	FlapThreshold int           // 窗口内正常与离线之间切换达到该次数视为抖动,0 表示不检测
+	FlapWindow    time.Duration // 统计切换次数的滑动窗口
+	FlapStable    time.Duration // 抖动中的监控项保持该时长没有切换后视为稳定
+
+	// 通知配置
+	NotifyTimeout  time.Duration // 单次发送通知的超时
+	TelegramAPIURL string        // Telegram Bot API 地址,可替换为代理
+	SMTPHost       string        // 为空时不能使用邮件通知
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SMTPFrom       string // 发件人地址
+	SMTPTLS        bool   // 使用隐式 TLS(通常为 465 端口),否则在服务器支持时使用 STARTTLS
+
+	// 邮件订阅配置,设置了 PUBLIC_URL 和 SMTP 服务器时启用
+	PublicURL            string        // 状态页的公开地址,用于邮件中的确认和退订链接
+	SubscriptionInterval time.Duration // 同一订阅者两封状态更新邮件的最小间隔,期间的更新合并发送
 
 	// 数据库配置
 	DBPath string
 
 	// 数据保留策略
 	DataRetentionDays int
+	ArchiveGraceDays  int // 归档监控项保留多少天后彻底删除
 }
 
 var AppConfig *Config
@@ -32,13 +95,49 @@ var AppConfig *Config
 // LoadConfig 加载配置
 func LoadConfig() *Config {
 	config := &Config{
-		KumaAPIURL:        getEnv("KUMA_API_URL", ""),
-		KumaStatusSlug:    getEnv("KUMA_STATUS_PAGE_SLUG", ""),
-		ServerPort:        getEnv("SERVER_PORT", "8080"),
-		CacheDuration:     time.Duration(getEnvInt("CACHE_DURATION", 60)) * time.Second,
-		FetchInterval:     time.Duration(getEnvInt("FETCH_INTERVAL", 60)) * time.Second,
-		DBPath:            getEnv("DB_PATH", "./data/kuma-lite.db"),
-		DataRetentionDays: getEnvInt("DATA_RETENTION_DAYS", 30),
+		KumaAPIURL:            getEnv("KUMA_API_URL", ""),
+		KumaStatusSlug:        getEnv("KUMA_STATUS_PAGE_SLUG", ""),
+		ServerPort:            getEnv("SERVER_PORT", "8080"),
+		StatusPageName:        getEnv("STATUS_PAGE_NAME", "Kuma-Lite"),
+		AdminToken:            getEnv("ADMIN_TOKEN", ""),
+		CacheDuration:         time.Duration(getEnvInt("CACHE_DURATION", 60)) * time.Second,
+		FetchInterval:         time.Duration(getEnvInt("FETCH_INTERVAL", 60)) * time.Second,
+		CacheBackend:          getEnv("CACHE_BACKEND", "memory"),
+		RedisURL:              getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		KumaBasicUser:         getEnv("KUMA_BASIC_AUTH_USER", ""),
+		KumaBasicPassword:     getEnv("KUMA_BASIC_AUTH_PASSWORD", ""),
+		KumaBearerToken:       getEnv("KUMA_BEARER_TOKEN", ""),
+		KumaProxyURL:          getEnv("KUMA_PROXY_URL", ""),
+		KumaCAFile:            getEnv("KUMA_CA_FILE", ""),
+		KumaClientCert:        getEnv("KUMA_CLIENT_CERT_FILE", ""),
+		KumaClientKey:         getEnv("KUMA_CLIENT_KEY_FILE", ""),
+		KumaTLSInsecure:       getEnvBool("KUMA_TLS_INSECURE", false),
+		StrictTimestamps:      getEnvBool("KUMA_STRICT_TIMESTAMPS", true),
+		FetchTimeout:          time.Duration(getEnvInt("FETCH_TIMEOUT", 15)) * time.Second,
+		FetchRetries:          getEnvInt("FETCH_RETRIES", 2),
+		FetchRetryBackoff:     time.Duration(getEnvInt("FETCH_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+		BreakerThreshold:      getEnvInt("BREAKER_THRESHOLD", 5),
+		BreakerCooldown:       time.Duration(getEnvInt("BREAKER_COOLDOWN", 300)) * time.Second,
+		StaleThreshold:        time.Duration(getEnvInt("STALE_THRESHOLD", 300)) * time.Second,
+		LatencyBaselineWindow: time.Duration(getEnvInt("LATENCY_BASELINE_HOURS", 24)) * time.Hour,
+		AnomalyThreshold:      getEnvFloat("ANOMALY_THRESHOLD", 3.5),
+		AnomalyBaselineDays:   getEnvInt("ANOMALY_BASELINE_DAYS", 14),
+		FlapThreshold:         getEnvInt("FLAP_THRESHOLD", 5),
+		FlapWindow:            time.Duration(getEnvInt("FLAP_WINDOW", 1800)) * time.Second,
+		FlapStable:            time.Duration(getEnvInt("FLAP_STABLE", 600)) * time.Second,
+		NotifyTimeout:         time.Duration(getEnvInt("NOTIFY_TIMEOUT", 10)) * time.Second,
+		TelegramAPIURL:        getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPPort:              getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:              getEnv("SMTP_FROM", ""),
+		SMTPTLS:               getEnvBool("SMTP_TLS", false),
+		PublicURL:             strings.TrimRight(getEnv("PUBLIC_URL", ""), "/"),
+		SubscriptionInterval:  time.Duration(getEnvInt("SUBSCRIPTION_INTERVAL", 900)) * time.Second,
+		DBPath:                getEnv("DB_PATH", "./data/kuma-lite.db"),
+		DataRetentionDays:     getEnvInt("DATA_RETENTION_DAYS", 30),
+		ArchiveGraceDays:      getEnvInt("ARCHIVE_GRACE_DAYS", 90),
 	}
 
 	// 验证必需配置
@@ -50,6 +149,39 @@ func LoadConfig() *Config {
 		log.Fatal("KUMA_STATUS_PAGE_SLUG 环境变量未设置")
 	}
 
+	headers, err := parseHeaders(getEnv("KUMA_HEADERS", ""))
+	if err != nil {
+		log.Fatalf("KUMA_HEADERS 格式错误: %v", err)
+	}
+	config.KumaHeaders = headers
+
+	if tz := getEnv("KUMA_TIMEZONE", "auto"); tz != "auto" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			log.Fatalf("KUMA_TIMEZONE 无效: %v", err)
+		}
+		config.KumaLocation = loc
+	} else {
+		log.Println("警告: 未设置 KUMA_TIMEZONE,将根据心跳时间自动推断 Kuma 的时区;所有监控项的心跳间隔都在 15 分钟以上时可能推断出错误的偏移,建议显式设置")
+	}
+	if !config.StrictTimestamps {
+		log.Println("警告: KUMA_STRICT_TIMESTAMPS=false,时间无法解析的最新心跳仍会用作监控项的当前状态")
+	}
+
+	if (config.KumaClientCert == "") != (config.KumaClientKey == "") {
+		log.Fatal("KUMA_CLIENT_CERT_FILE 和 KUMA_CLIENT_KEY_FILE 需要同时设置")
+	}
+
+	if config.SMTPHost != "" && config.SMTPFrom == "" {
+		log.Fatal("设置 SMTP_HOST 时需要同时设置 SMTP_FROM")
+	}
+
+	if config.PublicURL != "" {
+		if u, err := url.Parse(config.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatal("PUBLIC_URL 需要是有效的 http(s) 地址")
+		}
+	}
+
 	AppConfig = config
 	return config
 }
@@ -78,3 +210,53 @@ func getEnvInt(key string, defaultValue int) int {
 
 	return intValue
 }
+
+// getEnvFloat 获取浮点数类型环境变量
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("警告: %s 不是有效的数字,使用默认值 %v", key, defaultValue)
+		return defaultValue
+	}
+
+	return floatValue
+}
+
+// getEnvBool 获取布尔类型环境变量
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("警告: %s 不是有效的布尔值,使用默认值 %v", key, defaultValue)
+		return defaultValue
+	}
+
+	return boolValue
+}
+
+// parseHeaders 解析 "Name: value" 形式的请求头列表,多个请求头用分号分隔
+func parseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, headerValue, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("无效的请求头 %q,应为 Name: value", part)
+		}
+		headers[name] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
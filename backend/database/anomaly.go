@@ -0,0 +1,171 @@
+package database
+
+import (
+	"fmt"
+	"kuma-lite/backend/models"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// anomalyMinSamples 同时段基线所需的最少正常心跳数
+const anomalyMinSamples = 30
+
+// LatencyBaseline 监控项某个时段的响应时间基线
+type LatencyBaseline struct {
+	Median  float64 // 响应时间中位数(毫秒)
+	MAD     float64 // 绝对中位差(毫秒)
+	Samples int
+}
+
+// Score 计算响应时间相对基线的稳健 z 分数,只关注变慢,快于中位数时为 0
+// 绝对中位差过小时按中位数的 5% 计,避免响应时间极稳定的监控项对微小波动过于敏感
+func (b *LatencyBaseline) Score(responseTime float64) float64 {
+	if responseTime <= b.Median {
+		return 0
+	}
+	mad := math.Max(b.MAD, math.Max(b.Median*0.05, 1))
+	return 0.6745 * (responseTime - b.Median) / mad
+}
+
+// GetLatencyBaseline 统计 hour 所在时段的响应时间基线,时段按 UTC 划分
+// 优先使用最近 days 天中星期几和小时都相同的正常心跳,样本不足时使用同一小时的心跳,仍不足时返回 nil
+// 只使用 hour 之前的心跳,当前时段的异常不会影响基线
+func GetLatencyBaseline(monitorID int, hour time.Time, days int) (*LatencyBaseline, error) {
+	hour = hour.UTC().Truncate(time.Hour)
+	var rows []struct {
+		ResponseTime float64
+		Weekday      string
+	}
+	err := DB.Model(&models.HeartBeat{}).
+		Select("response_time, strftime('%w', created_at) AS weekday").
+		Where("monitor_id = ? AND status = ? AND response_time > 0", monitorID, models.StatusUp).
+		Where("created_at >= ? AND created_at < ?", hour.AddDate(0, 0, -days), hour).
+		Where("strftime('%H', created_at) = ?", fmt.Sprintf("%02d", hour.Hour())).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	weekday := strconv.Itoa(int(hour.Weekday()))
+	var sameWeekday, sameHour []float64
+	for _, row := range rows {
+		sameHour = append(sameHour, row.ResponseTime)
+		if row.Weekday == weekday {
+			sameWeekday = append(sameWeekday, row.ResponseTime)
+		}
+	}
+
+	samples := sameWeekday
+	if len(samples) < anomalyMinSamples {
+		samples = sameHour
+	}
+	if len(samples) < anomalyMinSamples {
+		return nil, nil
+	}
+
+	median := medianOf(samples)
+	deviations := make([]float64, len(samples))
+	for i, value := range samples {
+		deviations[i] = math.Abs(value - median)
+	}
+	return &LatencyBaseline{
+		Median:  median,
+		MAD:     medianOf(deviations),
+		Samples: len(samples),
+	}, nil
+}
+
+// GetRecentResponseTime 获取最近 n 个正常心跳响应时间的中位数,没有心跳时返回 0
+// 取中位数避免单次抖动被当作异常
+func GetRecentResponseTime(monitorID int, n int) (float64, error) {
+	var values []float64
+	err := DB.Model(&models.HeartBeat{}).
+		Where("monitor_id = ? AND status = ? AND response_time > 0", monitorID, models.StatusUp).
+		Order("created_at DESC").
+		Limit(n).
+		Pluck("response_time", &values).Error
+	if err != nil || len(values) == 0 {
+		return 0, err
+	}
+	return medianOf(values), nil
+}
+
+// medianOf 计算中位数,会对 values 排序
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// GetOpenAnomalies 获取未结束的响应时间异常,按监控项 ID 索引
+func GetOpenAnomalies() (map[int]models.LatencyAnomaly, error) {
+	var anomalies []models.LatencyAnomaly
+	if err := DB.Where("ended_at IS NULL").Find(&anomalies).Error; err != nil {
+		return nil, err
+	}
+	open := make(map[int]models.LatencyAnomaly, len(anomalies))
+	for _, anomaly := range anomalies {
+		open[anomaly.MonitorID] = anomaly
+	}
+	return open, nil
+}
+
+// OpenAnomaly 记录新的响应时间异常,同时记录一条事件
+func OpenAnomaly(anomaly *models.LatencyAnomaly, monitorName string) error {
+	if err := DB.Create(anomaly).Error; err != nil {
+		return err
+	}
+	return CreateEvent(&models.Event{
+		Kind:      models.EventLatencyAnomaly,
+		MonitorID: anomaly.MonitorID,
+		Title:     fmt.Sprintf("%s 响应时间异常", monitorName),
+		Message: fmt.Sprintf("响应时间 %dms,同时段通常为 %.0fms(偏离 %.1f)",
+			anomaly.ResponseTime, anomaly.Baseline, anomaly.Score),
+		CreatedAt: anomaly.StartedAt,
+	})
+}
+
+// UpdateAnomalyPeak 异常加剧时更新最大响应时间和偏离程度
+func UpdateAnomalyPeak(id int, responseTime int, score float64) error {
+	return DB.Model(&models.LatencyAnomaly{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"response_time": responseTime,
+		"score":         score,
+	}).Error
+}
+
+// CloseAnomaly 结束响应时间异常
+func CloseAnomaly(id int, endedAt time.Time) error {
+	return DB.Model(&models.LatencyAnomaly{}).Where("id = ?", id).Update("ended_at", endedAt).Error
+}
+
+// GetAnomaly 根据 ID 获取响应时间异常
+func GetAnomaly(id int) (*models.LatencyAnomaly, error) {
+	var anomaly models.LatencyAnomaly
+	if err := DB.Where("id = ?", id).First(&anomaly).Error; err != nil {
+		return nil, err
+	}
+	return &anomaly, nil
+}
+
+// GetAnomalies 获取监控项的响应时间异常(按开始时间倒序),包括 since 之后仍未结束的异常
+func GetAnomalies(monitorID int, since time.Time, limit int) ([]models.LatencyAnomaly, error) {
+	var anomalies []models.LatencyAnomaly
+	err := DB.Where("monitor_id = ?", monitorID).
+		Where("started_at >= ? OR ended_at IS NULL OR ended_at >= ?", since, since).
+		Order("started_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&anomalies).Error
+	return anomalies, err
+}
+
+// CleanOldAnomalies 清理已结束的旧响应时间异常
+func CleanOldAnomalies(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("ended_at IS NOT NULL AND ended_at < ?", threshold).Delete(&models.LatencyAnomaly{}).Error
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"fmt"
+	"kuma-lite/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateEvent 记录一条事件
+func CreateEvent(event *models.Event) error {
+	return createEvent(DB, event)
+}
+
+// createEvent 在指定的连接或事务中记录一条事件
+func createEvent(db *gorm.DB, event *models.Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	return db.Create(event).Error
+}
+
+// EventQuery 事件查询条件
+type EventQuery struct {
+	Kinds     []string // 为空时不过滤
+	MonitorID int      // 0 表示不过滤
+	Since     time.Time
+	Limit     int
+}
+
+// GetEvents 按条件查询事件(按时间倒序)
+func GetEvents(q EventQuery) ([]models.Event, error) {
+	query := DB.Where("created_at >= ?", q.Since)
+	if len(q.Kinds) > 0 {
+		query = query.Where("kind IN ?", q.Kinds)
+	}
+	if q.MonitorID > 0 {
+		query = query.Where("monitor_id = ?", q.MonitorID)
+	}
+
+	var events []models.Event
+	err := query.Order("created_at DESC").Order("id DESC").Limit(q.Limit).Find(&events).Error
+	return events, err
+}
+
+// GetMonitorChanges 获取监控项的变更记录(按时间倒序)
+func GetMonitorChanges(monitorID int, limit int) ([]models.MonitorChange, error) {
+	var changes []models.MonitorChange
+	err := DB.Where("monitor_id = ?", monitorID).
+		Order("changed_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
+
+// recordMonitorChanges 对比已保存的监控项和新获取的数据,记录名称、分组、URL 和类型的变化
+func recordMonitorChanges(existing, updated *models.Monitor) error {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", existing.Name, updated.Name},
+		{"group", existing.Group, updated.Group},
+		{"url", existing.URL, updated.URL},
+		{"type", existing.Type, updated.Type},
+	}
+
+	now := time.Now()
+	var changes []models.MonitorChange
+	var summary []string
+	for _, field := range fields {
+		if field.old == field.new {
+			continue
+		}
+		changes = append(changes, models.MonitorChange{
+			MonitorID: existing.ID,
+			Field:     field.name,
+			OldValue:  field.old,
+			NewValue:  field.new,
+			ChangedAt: now,
+		})
+		summary = append(summary, fmt.Sprintf("%s: %q → %q", field.name, field.old, field.new))
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := DB.Create(&changes).Error; err != nil {
+		return err
+	}
+	return CreateEvent(&models.Event{
+		Kind:      models.EventMonitorChanged,
+		MonitorID: existing.ID,
+		Title:     fmt.Sprintf("%s 配置变更", updated.Name),
+		Message:   strings.Join(summary, "; "),
+		CreatedAt: now,
+	})
+}
+
+// CleanOldEvents 清理旧的事件和变更记录
+func CleanOldEvents(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	if err := DB.Where("created_at < ?", threshold).Delete(&models.Event{}).Error; err != nil {
+		return err
+	}
+	return DB.Where("changed_at < ?", threshold).Delete(&models.MonitorChange{}).Error
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetComposites 获取所有组合监控项定义
+func GetComposites() ([]models.CompositeMonitor, error) {
+	var composites []models.CompositeMonitor
+	err := DB.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("id ASC").Find(&composites).Error
+	return composites, err
+}
+
+// GetComposite 获取单个组合监控项定义
+func GetComposite(id int) (*models.CompositeMonitor, error) {
+	var composite models.CompositeMonitor
+	err := DB.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id = ?", id).First(&composite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &composite, nil
+}
+
+// SaveComposite 创建或更新组合监控项,ID 为 0 时分配新 ID
+// 同时维护 monitors 表中对应的监控项,新建时状态为重试中,直到下一个获取周期计算出状态
+func SaveComposite(composite *models.CompositeMonitor) error {
+	if composite.ID == 0 {
+		var maxID int
+		if err := DB.Model(&models.CompositeMonitor{}).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error; err != nil {
+			return err
+		}
+		composite.ID = max(maxID+1, models.CompositeIDBase)
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(composite).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("composite_id = ?", composite.ID).Delete(&models.CompositeMember{}).Error; err != nil {
+			return err
+		}
+		if len(composite.Members) == 0 {
+			return nil
+		}
+		for i := range composite.Members {
+			composite.Members[i].ID = 0
+			composite.Members[i].CompositeID = composite.ID
+		}
+		return tx.Create(&composite.Members).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	monitor := &models.Monitor{
+		ID:          composite.ID,
+		Name:        composite.Name,
+		Type:        models.SourceComposite,
+		Source:      models.SourceComposite,
+		Group:       composite.Group,
+		Description: composite.Description,
+		Status:      models.StatusPending,
+	}
+	if err := compositePlacement(monitor); err != nil {
+		return err
+	}
+	return SaveMonitorMetadata(monitor)
+}
+
+// compositePlacement 计算组合监控项的分组顺序和分组内位置
+// 分组已有 Kuma 监控项时沿用其分组顺序,否则排在最后;新建时排在分组末尾
+func compositePlacement(monitor *models.Monitor) error {
+	var groupOrder *int
+	err := DB.Model(&models.Monitor{}).
+		Select("MIN(group_order)").
+		Where("`group` = ? AND source = ?", monitor.Group, models.SourceKuma).
+		Scan(&groupOrder).Error
+	if err != nil {
+		return err
+	}
+	if groupOrder == nil {
+		if err := DB.Model(&models.Monitor{}).Select("COALESCE(MAX(group_order), -1) + 1").Scan(&groupOrder).Error; err != nil {
+			return err
+		}
+	}
+	monitor.GroupOrder = *groupOrder
+
+	var existing models.Monitor
+	if err := DB.Unscoped().Where("id = ?", monitor.ID).First(&existing).Error; err == nil && existing.Group == monitor.Group {
+		monitor.Position = existing.Position
+		return nil
+	}
+	return DB.Model(&models.Monitor{}).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Where("`group` = ? AND id <> ?", monitor.Group, monitor.ID).
+		Scan(&monitor.Position).Error
+}
+
+// DeleteComposite 删除组合监控项定义及其监控项、心跳历史
+func DeleteComposite(id int) error {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("composite_id = ?", id).Delete(&models.CompositeMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.CompositeMonitor{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	return DeleteMonitor(id)
+}
+
+// GetMonitorsByIDs 获取指定 ID 的未归档监控项(不应用展示覆盖)
+func GetMonitorsByIDs(ids []int) ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	if len(ids) == 0 {
+		return monitors, nil
+	}
+	err := DB.Where("id IN ?", ids).Find(&monitors).Error
+	return monitors, err
+}
+
+// UpdateMonitorStatus 更新监控项的状态、可用率和响应时间
+func UpdateMonitorStatus(id, status int, uptime float64, responseTime int) error {
+	return DB.Model(&models.Monitor{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        status,
+		"uptime":        uptime,
+		"response_time": responseTime,
+	}).Error
+}
@@ -32,7 +32,12 @@ func InitDB(dbPath string) error {
 	DB = db
 
 	// 自动迁移数据表
-	if err := db.AutoMigrate(&models.Monitor{}, &models.HeartBeat{}); err != nil {
+	if err := db.AutoMigrate(&models.Monitor{}, &models.MonitorTag{}, &models.HeartBeat{}, &models.Announcement{}, &models.Incident{}, &models.FetchAttempt{},
+		&models.Event{}, &models.MonitorChange{}, &models.MonitorOverride{}, &models.GroupOverride{},
+		&models.MonitorDependency{}, &models.CompositeMonitor{}, &models.CompositeMember{},
+		&models.LatencyThreshold{}, &models.LatencyAnomaly{}, &models.SLO{}, &models.SLOAlert{},
+		&models.FlapPeriod{}, &models.NotificationChannel{}, &models.NotificationRule{}, &models.NotificationDelivery{},
+		&models.Subscriber{}, &models.StatusUpdate{}, &models.MaintenancePeriod{}); err != nil {
 		return err
 	}
 
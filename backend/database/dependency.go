@@ -0,0 +1,232 @@
+package database
+
+import (
+	"errors"
+	"kuma-lite/backend/models"
+	"sort"
+)
+
+// 依赖关系校验错误
+var (
+	ErrDependencySelf  = errors.New("监控项不能依赖自身")
+	ErrDependencyCycle = errors.New("依赖关系形成循环")
+)
+
+// GetDependencies 获取所有依赖关系
+func GetDependencies() ([]models.MonitorDependency, error) {
+	var dependencies []models.MonitorDependency
+	err := DB.Order("monitor_id ASC").Order("depends_on_id ASC").Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetDependency 获取单条依赖关系
+func GetDependency(id int) (*models.MonitorDependency, error) {
+	var dependency models.MonitorDependency
+	if err := DB.Where("id = ?", id).First(&dependency).Error; err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// AddDependency 添加依赖关系,已存在时返回现有记录
+// 会形成循环的依赖关系返回 ErrDependencyCycle
+func AddDependency(dependency *models.MonitorDependency) error {
+	if dependency.MonitorID == dependency.DependsOnID {
+		return ErrDependencySelf
+	}
+
+	dependencies, err := GetDependencies()
+	if err != nil {
+		return err
+	}
+	graph := dependencyMap(dependencies)
+	for _, id := range graph[dependency.MonitorID] {
+		if id == dependency.DependsOnID {
+			return DB.Where("monitor_id = ? AND depends_on_id = ?", dependency.MonitorID, dependency.DependsOnID).
+				First(dependency).Error
+		}
+	}
+	// 被依赖方能沿依赖关系到达依赖方时,新增的边会形成循环
+	if dependsOn(graph, dependency.DependsOnID, dependency.MonitorID) {
+		return ErrDependencyCycle
+	}
+
+	return DB.Create(dependency).Error
+}
+
+// DeleteDependency 删除依赖关系
+func DeleteDependency(id int) error {
+	return DB.Where("id = ?", id).Delete(&models.MonitorDependency{}).Error
+}
+
+// GetDependencyGraph 获取依赖图,节点为依赖关系涉及的未归档监控项
+func GetDependencyGraph() (*models.DependencyGraph, error) {
+	dependencies, err := GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+	monitors, err := GetAllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	involved := make(map[int]bool)
+	for _, dependency := range dependencies {
+		involved[dependency.MonitorID] = true
+		involved[dependency.DependsOnID] = true
+	}
+
+	graph := &models.DependencyGraph{Nodes: []models.DependencyNode{}}
+	present := make(map[int]bool)
+	for _, monitor := range monitors {
+		if !involved[monitor.ID] {
+			continue
+		}
+		present[monitor.ID] = true
+		graph.Nodes = append(graph.Nodes, models.DependencyNode{
+			ID:              monitor.ID,
+			Name:            monitor.Name,
+			Group:           monitor.Group,
+			Status:          monitor.Status,
+			EffectiveStatus: monitor.EffectiveStatus,
+			ImpactedBy:      monitor.ImpactedBy,
+		})
+	}
+
+	// 归档监控项的依赖关系保留,恢复后继续生效,但不出现在图中
+	edges := make([]models.MonitorDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		if present[dependency.MonitorID] && present[dependency.DependsOnID] {
+			edges = append(edges, dependency)
+		}
+	}
+	graph.Edges = edges
+	return graph, nil
+}
+
+// applyDependencyImpact 根据依赖关系计算监控项的 EffectiveStatus 和 ImpactedBy
+func applyDependencyImpact(monitors []models.Monitor) error {
+	if len(monitors) == 0 {
+		return nil
+	}
+	statuses, err := monitorStatuses()
+	if err != nil {
+		return err
+	}
+	impact, err := dependencyImpact(statuses)
+	if err != nil {
+		return err
+	}
+
+	for i := range monitors {
+		monitors[i].EffectiveStatus = monitors[i].Status
+		if roots := impact[monitors[i].ID]; len(roots) > 0 && monitors[i].Status == models.StatusDown {
+			monitors[i].EffectiveStatus = models.StatusImpacted
+			monitors[i].ImpactedBy = roots
+		}
+	}
+	return nil
+}
+
+// monitorStatuses 获取未归档监控项的当前状态
+func monitorStatuses() (map[int]int, error) {
+	var rows []struct {
+		ID     int
+		Status int
+	}
+	if err := DB.Model(&models.Monitor{}).Select("id, status").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	statuses := make(map[int]int, len(rows))
+	for _, row := range rows {
+		statuses[row.ID] = row.Status
+	}
+	return statuses, nil
+}
+
+// dependencyImpact 计算每个离线监控项的根因监控项
+// 沿离线的依赖方向查找,根因是自身离线但依赖均未离线的监控项;依赖未离线的监控项不受影响
+func dependencyImpact(statuses map[int]int) (map[int][]int, error) {
+	dependencies, err := GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+	graph := dependencyMap(dependencies)
+
+	isDown := func(id int) bool {
+		status, ok := statuses[id]
+		return ok && status == models.StatusDown
+	}
+
+	impact := make(map[int][]int)
+	for id := range graph {
+		if !isDown(id) {
+			continue
+		}
+
+		visited := map[int]bool{id: true}
+		rootSet := make(map[int]bool)
+		var walk func(int)
+		walk = func(current int) {
+			for _, dep := range graph[current] {
+				if visited[dep] || !isDown(dep) {
+					continue
+				}
+				visited[dep] = true
+
+				hasDownDependency := false
+				for _, next := range graph[dep] {
+					if isDown(next) {
+						hasDownDependency = true
+						break
+					}
+				}
+				if hasDownDependency {
+					walk(dep)
+				} else {
+					rootSet[dep] = true
+				}
+			}
+		}
+		walk(id)
+
+		if len(rootSet) > 0 {
+			roots := make([]int, 0, len(rootSet))
+			for root := range rootSet {
+				roots = append(roots, root)
+			}
+			sort.Ints(roots)
+			impact[id] = roots
+		}
+	}
+	return impact, nil
+}
+
+// dependencyMap 依赖关系转换为 监控项 -> 被依赖的监控项 列表
+func dependencyMap(dependencies []models.MonitorDependency) map[int][]int {
+	graph := make(map[int][]int)
+	for _, dependency := range dependencies {
+		graph[dependency.MonitorID] = append(graph[dependency.MonitorID], dependency.DependsOnID)
+	}
+	return graph
+}
+
+// dependsOn 判断 from 是否直接或间接依赖 to
+func dependsOn(graph map[int][]int, from, to int) bool {
+	visited := map[int]bool{from: true}
+	queue := []int{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[current] {
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
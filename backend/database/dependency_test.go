@@ -0,0 +1,119 @@
+package database
+
+import (
+	"errors"
+	"kuma-lite/backend/models"
+	"reflect"
+	"testing"
+)
+
+func TestDependencyImpact(t *testing.T) {
+	const down, up = models.StatusDown, models.StatusUp
+	tests := []struct {
+		name     string
+		edges    [][2]int // 监控项 -> 被依赖的监控项
+		statuses map[int]int
+		want     map[int][]int
+	}{
+		{"依赖链全部离线归因到最底层", [][2]int{{1, 2}, {2, 3}}, map[int]int{1: down, 2: down, 3: down},
+			map[int][]int{1: {3}, 2: {3}}},
+		{"底层正常时归因到中间层", [][2]int{{1, 2}, {2, 3}}, map[int]int{1: down, 2: down, 3: up},
+			map[int][]int{1: {2}}},
+		{"依赖正常时不受影响", [][2]int{{1, 2}}, map[int]int{1: down, 2: up}, map[int][]int{}},
+		{"自身正常时不受影响", [][2]int{{1, 2}}, map[int]int{1: up, 2: down}, map[int][]int{}},
+		{"多个根因", [][2]int{{1, 2}, {1, 3}}, map[int]int{1: down, 2: down, 3: down},
+			map[int][]int{1: {2, 3}}},
+		{"共同依赖", [][2]int{{1, 3}, {2, 3}}, map[int]int{1: down, 2: down, 3: down},
+			map[int][]int{1: {3}, 2: {3}}},
+		{"中间层正常时链条中断", [][2]int{{1, 2}, {2, 3}}, map[int]int{1: down, 2: up, 3: down}, map[int][]int{}},
+		{"已归档的依赖不计入", [][2]int{{1, 2}}, map[int]int{1: down}, map[int][]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			saveMonitors(t, 3)
+			for _, edge := range tt.edges {
+				if err := AddDependency(&models.MonitorDependency{MonitorID: edge[0], DependsOnID: edge[1]}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := dependencyImpact(tt.statuses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("根因 = %v,应为 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddDependencyValidation(t *testing.T) {
+	setupTestDB(t)
+	saveMonitors(t, 3)
+	for _, edge := range [][2]int{{1, 2}, {2, 3}} {
+		if err := AddDependency(&models.MonitorDependency{MonitorID: edge[0], DependsOnID: edge[1]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		from    int
+		to      int
+		wantErr error
+	}{
+		{"依赖自身", 1, 1, ErrDependencySelf},
+		{"直接循环", 2, 1, ErrDependencyCycle},
+		{"间接循环", 3, 1, ErrDependencyCycle},
+		{"已存在", 1, 2, nil},
+		{"新增", 1, 3, nil},
+	}
+	for _, tt := range tests {
+		dependency := &models.MonitorDependency{MonitorID: tt.from, DependsOnID: tt.to}
+		if err := AddDependency(dependency); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: AddDependency(%d -> %d) = %v,应为 %v", tt.name, tt.from, tt.to, err, tt.wantErr)
+		} else if err == nil && dependency.ID == 0 {
+			t.Errorf("%s: 应返回依赖关系的 ID", tt.name)
+		}
+	}
+	if dependencies, err := GetDependencies(); err != nil || len(dependencies) != 3 {
+		t.Errorf("应有 3 条依赖关系: %+v, %v", dependencies, err)
+	}
+}
+
+// TestEffectiveStatusImpacted 离线且根因是依赖的监控项 effectiveStatus 为受影响,并列出根因
+func TestEffectiveStatusImpacted(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 3)
+	for _, id := range []int{1, 2} {
+		monitors[id-1].Status = models.StatusDown
+		if err := SaveMonitor(&monitors[id-1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, edge := range [][2]int{{1, 2}, {3, 2}} {
+		if err := AddDependency(&models.MonitorDependency{MonitorID: edge[0], DependsOnID: edge[1]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := GetAllMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		status int
+		roots  []int
+	}{
+		{models.StatusImpacted, []int{2}},
+		{models.StatusDown, nil},
+		{models.StatusUp, nil},
+	}
+	for i, monitor := range got {
+		if monitor.EffectiveStatus != want[i].status || !reflect.DeepEqual(monitor.ImpactedBy, want[i].roots) {
+			t.Errorf("监控项 %d: effectiveStatus %d, impactedBy %v,应为 %d, %v",
+				monitor.ID, monitor.EffectiveStatus, monitor.ImpactedBy, want[i].status, want[i].roots)
+		}
+	}
+}
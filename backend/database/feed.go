@@ -0,0 +1,79 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// GetStatusTransitions 获取指定时间之后的状态变化记录(按时间倒序)
+// monitorIDs 为空时不过滤监控项
+func GetStatusTransitions(monitorIDs []int, since time.Time, limit int) ([]models.StatusTransition, error) {
+	var transitions []models.StatusTransition
+
+	// 使用窗口函数比较同一监控项相邻两条心跳的状态
+	// 窗口包含每个监控项在 since 之前的最后一条心跳,使窗口内第一条心跳也能和之前的状态比较,之后再按 since 过滤
+	inner := DB.Model(&models.HeartBeat{}).
+		Select("id, monitor_id, status, response_time, message, created_at, "+
+			"LAG(status) OVER (PARTITION BY monitor_id ORDER BY created_at) AS prev_status").
+		Where("created_at >= COALESCE((SELECT MAX(prev.created_at) FROM heart_beats AS prev "+
+			"WHERE prev.monitor_id = heart_beats.monitor_id AND prev.created_at < ?), ?)", since, since)
+	if len(monitorIDs) > 0 {
+		inner = inner.Where("monitor_id IN ?", monitorIDs)
+	}
+
+	err := DB.Table("(?) AS hb", inner).
+		Select("id AS heart_beat_id, monitor_id, prev_status AS from_status, status AS to_status, "+
+			"response_time, message, created_at").
+		Where("created_at >= ?", since).
+		Where("prev_status IS NOT NULL AND prev_status <> status").
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(&transitions).Error
+
+	return transitions, err
+}
+
+// GetLatestHeartBeatTime 获取最新一条心跳的时间
+func GetLatestHeartBeatTime() (time.Time, error) {
+	var heartbeat models.HeartBeat
+	err := DB.Order("created_at DESC").First(&heartbeat).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+	return heartbeat.CreatedAt, nil
+}
+
+// SyncAnnouncement 同步状态页公告
+// 传入 nil 表示 Kuma 当前没有置顶公告,所有公告标记为非活动
+// 标记为非活动时更新 UpdatedAt,使订阅源能看到公告结束
+func SyncAnnouncement(announcement *models.Announcement) error {
+	tx := DB.Begin()
+
+	query := tx.Model(&models.Announcement{}).Where("active = ?", true)
+	if announcement != nil {
+		query = query.Where("id <> ?", announcement.ID)
+	}
+	if err := query.Updates(map[string]interface{}{"active": false, "updated_at": time.Now()}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if announcement != nil {
+		if err := tx.Save(announcement).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// GetAnnouncements 获取指定时间之后更新过的公告(按更新时间倒序)
+func GetAnnouncements(since time.Time, limit int) ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := DB.Where("updated_at >= ?", since).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&announcements).Error
+	return announcements, err
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+	"time"
+)
+
+// TestGetStatusTransitions 窗口内第一条心跳与窗口前最后一条心跳比较,状态不变的心跳不算变化
+func TestGetStatusTransitions(t *testing.T) {
+	setupTestDB(t)
+	saveMonitors(t, 3)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	beats := []struct {
+		monitorID int
+		status    int
+		ago       time.Duration
+	}{
+		{1, models.StatusDown, 2 * time.Hour},
+		{1, models.StatusUp, 30 * time.Minute}, // 窗口内第一条,与窗口前的离线比较
+		{1, models.StatusUp, 20 * time.Minute},
+		{1, models.StatusDown, 10 * time.Minute},
+		{2, models.StatusUp, 2 * time.Hour},
+		{2, models.StatusUp, 30 * time.Minute},
+		{3, models.StatusDown, 40 * time.Minute}, // 窗口前没有心跳,第一条不算变化
+		{3, models.StatusUp, 5 * time.Minute},
+	}
+	for _, beat := range beats {
+		heartbeat := &models.HeartBeat{MonitorID: beat.monitorID, Status: beat.status, CreatedAt: now.Add(-beat.ago)}
+		if err := SaveHeartBeat(heartbeat); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type transition struct{ monitorID, from, to int }
+	tests := []struct {
+		name       string
+		monitorIDs []int
+		since      time.Duration
+		limit      int
+		want       []transition
+	}{
+		{"所有监控项", nil, time.Hour, 10, []transition{
+			{3, models.StatusDown, models.StatusUp},
+			{1, models.StatusUp, models.StatusDown},
+			{1, models.StatusDown, models.StatusUp},
+		}},
+		{"按监控项过滤", []int{1}, time.Hour, 10, []transition{
+			{1, models.StatusUp, models.StatusDown},
+			{1, models.StatusDown, models.StatusUp},
+		}},
+		{"窗口只包含最后一条", []int{1}, 15 * time.Minute, 10, []transition{
+			{1, models.StatusUp, models.StatusDown},
+		}},
+		{"状态没有变化", []int{2}, time.Hour, 10, nil},
+		{"限制条数", nil, time.Hour, 1, []transition{
+			{3, models.StatusDown, models.StatusUp},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transitions, err := GetStatusTransitions(tt.monitorIDs, now.Add(-tt.since), tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []transition
+			for _, tr := range transitions {
+				got = append(got, transition{tr.MonitorID, tr.FromStatus, tr.ToStatus})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("应有 %d 条状态变化,实际 %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("第 %d 条应为 %+v,实际 %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSyncAnnouncementDatesRemoval 公告从 Kuma 移除时标记为非活动并更新 UpdatedAt,订阅源能看到公告结束
+func TestSyncAnnouncementDatesRemoval(t *testing.T) {
+	setupTestDB(t)
+
+	created := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	announcement := &models.Announcement{ID: 7, Title: "计划维护", Active: true, CreatedAt: created, UpdatedAt: created}
+	if err := SyncAnnouncement(announcement); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Now().UTC().Add(-time.Hour)
+	if recent, err := GetAnnouncements(since, 10); err != nil || len(recent) != 0 {
+		t.Fatalf("更新时间早于 since 的公告不应返回: %+v, %v", recent, err)
+	}
+
+	if err := SyncAnnouncement(nil); err != nil {
+		t.Fatal(err)
+	}
+	recent, err := GetAnnouncements(since, 10)
+	if err != nil || len(recent) != 1 {
+		t.Fatalf("移除的公告应出现在最近更新中: %+v, %v", recent, err)
+	}
+	if recent[0].Active || !recent[0].UpdatedAt.After(created) {
+		t.Errorf("移除的公告应为非活动并更新 UpdatedAt: %+v", recent[0])
+	}
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"errors"
+	"kuma-lite/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SaveFetchAttempt 保存一次数据获取记录
+func SaveFetchAttempt(attempt *models.FetchAttempt) error {
+	return DB.Create(attempt).Error
+}
+
+// GetRecentFetchAttempts 获取最近 N 次数据获取记录(按时间倒序)
+func GetRecentFetchAttempts(limit int) ([]models.FetchAttempt, error) {
+	var attempts []models.FetchAttempt
+	err := DB.Order("started_at DESC").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
+
+// GetLastSuccessfulFetch 获取最近一次成功的数据获取记录,从未成功时返回 nil
+func GetLastSuccessfulFetch() (*models.FetchAttempt, error) {
+	var attempt models.FetchAttempt
+	err := DB.Where("success = ?", true).Order("started_at DESC").First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// CountConsecutiveFetchFailures 统计最近一次成功之后连续失败的次数
+func CountConsecutiveFetchFailures() (int64, error) {
+	last, err := GetLastSuccessfulFetch()
+	if err != nil {
+		return 0, err
+	}
+
+	query := DB.Model(&models.FetchAttempt{}).Where("success = ?", false)
+	if last != nil {
+		query = query.Where("started_at > ?", last.StartedAt)
+	}
+
+	var count int64
+	err = query.Count(&count).Error
+	return count, err
+}
+
+// CleanOldFetchAttempts 清理旧的数据获取记录
+func CleanOldFetchAttempts(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("started_at < ?", threshold).Delete(&models.FetchAttempt{}).Error
+}
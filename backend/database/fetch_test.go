@@ -0,0 +1,36 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+	"time"
+)
+
+// TestCountConsecutiveFetchFailures 只统计最近一次成功之后的失败,从未成功时统计全部失败
+func TestCountConsecutiveFetchFailures(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		results []bool
+		want    int64
+	}{
+		{"没有记录", nil, 0},
+		{"从未成功", []bool{false, false}, 2},
+		{"最近一次成功", []bool{false, true}, 0},
+		{"成功之后失败", []bool{false, false, true, false, false, false}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			for i, success := range tt.results {
+				attempt := &models.FetchAttempt{StartedAt: start.Add(time.Duration(i) * time.Minute), Success: success}
+				if err := SaveFetchAttempt(attempt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, err := CountConsecutiveFetchFailures(); err != nil || got != tt.want {
+				t.Errorf("连续失败次数 = %d, %v,应为 %d", got, err, tt.want)
+			}
+		})
+	}
+}
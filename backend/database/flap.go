@@ -0,0 +1,144 @@
+package database
+
+import (
+	"fmt"
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// FlapActivity 监控项在滑动窗口内的状态切换情况
+type FlapActivity struct {
+	Changes    int       // 正常与离线之间的切换次数,维护中不计
+	LastChange time.Time // 最近一次切换的时间,没有切换时为零值
+}
+
+// GetFlapActivity 统计监控项在 since 之后的心跳中正常与离线之间的切换次数
+// 重试中按正常计算,与可靠性报告一致;没有切换的监控项不在结果中
+func GetFlapActivity(monitorIDs []int, since time.Time) (map[int]FlapActivity, error) {
+	activity := make(map[int]FlapActivity)
+	if len(monitorIDs) == 0 {
+		return activity, nil
+	}
+
+	var heartbeats []reliabilityPoint
+	err := DB.Model(&models.HeartBeat{}).
+		Select("monitor_id, status, created_at").
+		Where("monitor_id IN ? AND created_at >= ?", monitorIDs, since).
+		Order("monitor_id ASC").
+		Order("created_at ASC").
+		Scan(&heartbeats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lastMonitor, lastActive := 0, -1
+	for _, hb := range heartbeats {
+		if hb.MonitorID != lastMonitor {
+			lastMonitor, lastActive = hb.MonitorID, -1
+		}
+		state := reliabilityState(hb.Status)
+		if state == reliabilityMaintenance {
+			continue
+		}
+		if lastActive != -1 && lastActive != state {
+			current := activity[hb.MonitorID]
+			current.Changes++
+			current.LastChange = hb.CreatedAt
+			activity[hb.MonitorID] = current
+		}
+		lastActive = state
+	}
+	return activity, nil
+}
+
+// GetOpenFlaps 获取未结束的抖动期间,按监控项 ID 索引
+func GetOpenFlaps() (map[int]models.FlapPeriod, error) {
+	var flaps []models.FlapPeriod
+	if err := DB.Where("ended_at IS NULL").Find(&flaps).Error; err != nil {
+		return nil, err
+	}
+	open := make(map[int]models.FlapPeriod, len(flaps))
+	for _, flap := range flaps {
+		open[flap.MonitorID] = flap
+	}
+	return open, nil
+}
+
+// OpenFlap 记录新的抖动期间,同时记录一条事件
+func OpenFlap(flap *models.FlapPeriod, monitorName string, window time.Duration) error {
+	if err := DB.Create(flap).Error; err != nil {
+		return err
+	}
+	return CreateEvent(&models.Event{
+		Kind:      models.EventFlapStarted,
+		MonitorID: flap.MonitorID,
+		Title:     fmt.Sprintf("%s 状态频繁切换", monitorName),
+		Message:   fmt.Sprintf("%d 分钟内在正常和离线之间切换了 %d 次,状态稳定前暂停故障事件", int(window.Minutes()), flap.Changes),
+		CreatedAt: flap.StartedAt,
+	})
+}
+
+// UpdateFlapChanges 切换更频繁时更新最大切换次数
+func UpdateFlapChanges(id int, changes int) error {
+	return DB.Model(&models.FlapPeriod{}).Where("id = ?", id).Update("changes", changes).Error
+}
+
+// CloseFlap 结束抖动期间,同时记录一条事件
+func CloseFlap(flap *models.FlapPeriod, monitorName string, endedAt time.Time) error {
+	if err := DB.Model(flap).Update("ended_at", endedAt).Error; err != nil {
+		return err
+	}
+	return CreateEvent(&models.Event{
+		Kind:      models.EventFlapEnded,
+		MonitorID: flap.MonitorID,
+		Title:     fmt.Sprintf("%s 状态恢复稳定", monitorName),
+		Message:   fmt.Sprintf("抖动持续 %d 分钟", int(endedAt.Sub(flap.StartedAt).Minutes())),
+		CreatedAt: endedAt,
+	})
+}
+
+// GetFlaps 获取监控项的抖动期间(按开始时间倒序),包括 since 之后仍未结束的期间
+func GetFlaps(monitorID int, since time.Time, limit int) ([]models.FlapPeriod, error) {
+	var flaps []models.FlapPeriod
+	err := DB.Where("monitor_id = ?", monitorID).
+		Where("started_at >= ? OR ended_at IS NULL OR ended_at >= ?", since, since).
+		Order("started_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&flaps).Error
+	return flaps, err
+}
+
+// CleanOldFlaps 清理已结束的旧抖动期间
+func CleanOldFlaps(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("ended_at IS NOT NULL AND ended_at < ?", threshold).Delete(&models.FlapPeriod{}).Error
+}
+
+// flappingMonitorIDs 获取正在抖动的监控项 ID
+func flappingMonitorIDs() (map[int]bool, error) {
+	var ids []int
+	if err := DB.Model(&models.FlapPeriod{}).Where("ended_at IS NULL").Pluck("monitor_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	flapping := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		flapping[id] = true
+	}
+	return flapping, nil
+}
+
+// applyFlapping 标记正在抖动的监控项
+func applyFlapping(monitors []models.Monitor) error {
+	if len(monitors) == 0 {
+		return nil
+	}
+	flapping, err := flappingMonitorIDs()
+	if err != nil {
+		return err
+	}
+	for i := range monitors {
+		monitors[i].Flapping = flapping[monitors[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+	"time"
+)
+
+func TestGetFlapActivity(t *testing.T) {
+	setupTestDB(t)
+	saveMonitors(t, 4)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	const down, up, pending, maintenance = models.StatusDown, models.StatusUp, models.StatusPending, models.StatusMaintenance
+	beats := map[int][]int{
+		1: {up, down, up, down},           // 3 次切换
+		2: {up, maintenance, down, up},    // 维护中不计,up->down->up 2 次
+		3: {up, pending, up, maintenance}, // 重试中按正常,没有切换
+		4: {down, down, down},             // 没有切换
+	}
+	for id, statuses := range beats {
+		for i, status := range statuses {
+			heartbeat := &models.HeartBeat{MonitorID: id, Status: status, CreatedAt: now.Add(time.Duration(i-len(statuses)) * time.Minute)}
+			if err := SaveHeartBeat(heartbeat); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// 窗口之前的切换不计入
+	if err := SaveHeartBeat(&models.HeartBeat{MonitorID: 4, Status: up, CreatedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	activity, err := GetFlapActivity([]int{1, 2, 3, 4}, now.Add(-30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		monitorID  int
+		changes    int
+		lastChange time.Time
+	}{
+		{1, 3, now.Add(-time.Minute)},
+		{2, 2, now.Add(-time.Minute)},
+		{3, 0, time.Time{}},
+		{4, 0, time.Time{}},
+	}
+	for _, tt := range tests {
+		got := activity[tt.monitorID]
+		if got.Changes != tt.changes || !got.LastChange.Equal(tt.lastChange) {
+			t.Errorf("监控项 %d: 切换 %d 次,最近 %v,应为 %d 次,最近 %v", tt.monitorID, got.Changes, got.LastChange, tt.changes, tt.lastChange)
+		}
+	}
+}
+
+// TestFlappingSuppressesIncidents 抖动期间不创建也不解决故障事件,结束后按最终状态处理
+func TestFlappingSuppressesIncidents(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 1)
+
+	flap := &models.FlapPeriod{MonitorID: 1, Changes: 6, StartedAt: time.Now()}
+	if err := OpenFlap(flap, "监控项A", 30*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetMonitorByID(1); err != nil || !got.Flapping {
+		t.Fatalf("监控项应标记为正在抖动: %+v, %v", got, err)
+	}
+
+	monitors[0].Status = models.StatusDown
+	if changes, err := SyncIncidents(monitors); err != nil || len(changes) != 0 {
+		t.Fatalf("抖动期间不应创建故障事件: %+v, %v", changes, err)
+	}
+
+	if err := CloseFlap(flap, "监控项A", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := SyncIncidents(monitors)
+	if err != nil || len(changes) != 1 || changes[0].Kind != models.IncidentChangeOpened {
+		t.Fatalf("抖动结束后应按离线创建故障事件: %+v, %v", changes, err)
+	}
+	events, err := GetEvents(EventQuery{Kinds: []string{models.EventFlapStarted, models.EventFlapEnded}, Limit: 10})
+	if err != nil || len(events) != 2 {
+		t.Errorf("应记录抖动开始和结束事件: %+v, %v", events, err)
+	}
+}
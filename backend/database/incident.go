@@ -0,0 +1,291 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"kuma-lite/backend/models"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// incidentImpactDegraded 响应缓慢的故障事件的影响程度
+const incidentImpactDegraded = "minor"
+
+// SyncIncidents 根据监控项最新状态创建或解决故障事件
+// 只应在获取到心跳数据时调用,否则状态不可信
+// 因依赖离线而离线的监控项,故障事件归入根因监控项的事件
+// 最近几个正常心跳响应时间的中位数超过阈值的监控项创建 minor 故障事件,离线后升级为 major,恢复正常且不再缓慢时解决
+// 离线恢复后仍然缓慢时解决离线事件,另起响应缓慢的事件
+// 按中位数判断,单次慢响应或单次快响应都不会创建或解决响应缓慢的事件
+// 正在抖动的监控项保持现有故障事件不变,状态稳定后再按最终状态创建或解决
+// 重试中的监控项尚未确认恢复,与统计信息一样按异常处理: 不创建也不解决故障事件
+// 返回本次创建、升级和解决的故障事件,用于发送通知
+func SyncIncidents(monitors []models.Monitor) ([]models.IncidentChange, error) {
+	statuses, err := monitorStatuses()
+	if err != nil {
+		return nil, err
+	}
+	flapping, err := flappingMonitorIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		statuses[monitor.ID] = monitor.Status
+	}
+	impact, err := dependencyImpact(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先处理不受依赖影响的监控项,保证根因的故障事件先于依赖方创建
+	ordered := append([]models.Monitor(nil), monitors...)
+	for i := range ordered {
+		ordered[i].EffectiveStatus = ordered[i].Status
+	}
+	if err := smoothResponseTimes(ordered); err != nil {
+		return nil, err
+	}
+	if err := applyLatencyThresholds(ordered); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return len(impact[ordered[i].ID]) == 0 && len(impact[ordered[j].ID]) > 0
+	})
+
+	var changes []models.IncidentChange
+	for _, monitor := range ordered {
+		if flapping[monitor.ID] {
+			continue
+		}
+		open, err := GetOpenIncident(monitor.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		degraded := monitor.EffectiveStatus == models.StatusDegraded
+		switch {
+		case monitor.Status == models.StatusDown && open == nil:
+			incident, err := openIncident(&monitor, impact[monitor.ID])
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeOpened, Incident: *incident})
+			if incident.ParentID != 0 {
+				log.Printf("监控项因依赖离线,故障归入根因事件: [%s] (事件 ID: %d, 根因事件 ID: %d)", monitor.Name, incident.ID, incident.ParentID)
+			} else {
+				log.Printf("监控项离线,创建故障事件: [%s] (事件 ID: %d)", monitor.Name, incident.ID)
+			}
+		case monitor.Status == models.StatusDown && open.Impact == incidentImpactDegraded:
+			// 响应缓慢发展为离线
+			if err := DB.Model(open).Updates(map[string]interface{}{
+				"title":  monitor.Name + " 离线",
+				"impact": "major",
+			}).Error; err != nil {
+				return nil, err
+			}
+			open.Title, open.Impact = monitor.Name+" 离线", "major"
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeEscalated, Incident: *open})
+			log.Printf("监控项由响应缓慢转为离线: [%s] (事件 ID: %d)", monitor.Name, open.ID)
+		case degraded && open != nil && open.Impact != incidentImpactDegraded:
+			// 离线恢复但仍然缓慢: 离线事件解决,另起响应缓慢的事件
+			if err := resolveIncident(open); err != nil {
+				return nil, err
+			}
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeResolved, Incident: *open})
+			incident, err := openDegradedIncident(&monitor)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeOpened, Incident: *incident})
+			log.Printf("监控项恢复但响应缓慢,故障事件转为响应缓慢: [%s] (事件 ID: %d -> %d)", monitor.Name, open.ID, incident.ID)
+		case degraded && open == nil:
+			incident, err := openDegradedIncident(&monitor)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeOpened, Incident: *incident})
+			log.Printf("监控项响应缓慢,创建故障事件: [%s] (事件 ID: %d)", monitor.Name, incident.ID)
+		case monitor.Status != models.StatusDown && monitor.Status != models.StatusPending && !degraded && open != nil:
+			if err := resolveIncident(open); err != nil {
+				return nil, err
+			}
+			changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeResolved, Incident: *open})
+			log.Printf("监控项恢复,故障事件已解决: [%s] (事件 ID: %d)", monitor.Name, open.ID)
+		}
+	}
+
+	detached, err := detachOrphanIncidents()
+	if err != nil {
+		return nil, err
+	}
+	for _, incident := range detached {
+		changes = append(changes, models.IncidentChange{Kind: models.IncidentChangeOpened, Incident: incident})
+	}
+	return changes, nil
+}
+
+// openDegradedIncident 创建响应缓慢的故障事件,原因取自 DegradedReason
+func openDegradedIncident(monitor *models.Monitor) (*models.Incident, error) {
+	incident := &models.Incident{
+		MonitorID: monitor.ID,
+		Title:     monitor.Name + " 响应缓慢",
+		Status:    models.IncidentInvestigating,
+		Impact:    incidentImpactDegraded,
+		Message:   fmt.Sprintf("最近 %d 次心跳的", degradedRecentSamples) + monitor.DegradedReason,
+		StartedAt: time.Now(),
+	}
+	return incident, DB.Create(incident).Error
+}
+
+// detachOrphanIncidents 根因事件已解决但依赖方仍离线时,依赖方的事件转为独立事件
+// 返回转为独立事件的故障事件,此前归入根因事件而没有单独通知
+func detachOrphanIncidents() ([]models.Incident, error) {
+	resolved := DB.Model(&models.Incident{}).Select("id").Where("status = ?", models.IncidentResolved)
+	var orphans []models.Incident
+	err := DB.Where("parent_id <> 0 AND status <> ? AND parent_id IN (?)", models.IncidentResolved, resolved).
+		Find(&orphans).Error
+	if err != nil || len(orphans) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int, len(orphans))
+	for i := range orphans {
+		ids[i] = orphans[i].ID
+		orphans[i].ParentID, orphans[i].Impact = 0, "major"
+	}
+	if err := DB.Model(&models.Incident{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"parent_id": 0, "impact": "major"}).Error; err != nil {
+		return nil, err
+	}
+	log.Printf("根因事件已解决,%d 个依赖方故障事件转为独立事件", len(orphans))
+	return orphans, nil
+}
+
+// GetOpenIncident 获取监控项未解决的故障事件,没有时返回 nil
+func GetOpenIncident(monitorID int) (*models.Incident, error) {
+	var incident models.Incident
+	err := DB.Where("monitor_id = ? AND status <> ?", monitorID, models.IncidentResolved).
+		Order("started_at DESC").
+		First(&incident).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// GetIncident 根据 ID 获取故障事件
+func GetIncident(id int) (*models.Incident, error) {
+	var incident models.Incident
+	if err := DB.Where("id = ?", id).First(&incident).Error; err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// GetIncidents 获取最近的故障事件(按开始时间倒序),不包括归入根因事件的事件
+func GetIncidents(limit int) ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := DB.Where("parent_id = 0").Order("started_at DESC").Limit(limit).Find(&incidents).Error
+	return incidents, err
+}
+
+// GetUnresolvedIncidents 获取所有未解决的故障事件,不包括归入根因事件的事件
+func GetUnresolvedIncidents() ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := DB.Where("parent_id = 0 AND status <> ?", models.IncidentResolved).
+		Order("started_at DESC").
+		Find(&incidents).Error
+	return incidents, err
+}
+
+// GetChildIncidents 获取归入指定根因事件的故障事件
+func GetChildIncidents(parentIDs []int) ([]models.Incident, error) {
+	var incidents []models.Incident
+	if len(parentIDs) == 0 {
+		return incidents, nil
+	}
+	err := DB.Where("parent_id IN ?", parentIDs).Order("started_at ASC").Find(&incidents).Error
+	return incidents, err
+}
+
+// openIncident 为离线的监控项创建故障事件,开始时间取本次连续离线的第一条心跳
+// roots 为导致离线的根因监控项,其中有未解决的故障事件时归入该事件
+func openIncident(monitor *models.Monitor, roots []int) (*models.Incident, error) {
+	incident := &models.Incident{
+		MonitorID: monitor.ID,
+		Title:     monitor.Name + " 离线",
+		Status:    models.IncidentInvestigating,
+		Impact:    "major",
+		StartedAt: time.Now(),
+	}
+	for _, root := range roots {
+		parent, err := GetOpenIncident(root)
+		if err != nil {
+			return nil, err
+		}
+		if parent != nil {
+			incident.ParentID = parent.ID
+			if parent.ParentID != 0 {
+				incident.ParentID = parent.ParentID
+			}
+			incident.Impact = "none"
+			break
+		}
+	}
+
+	var lastOK models.HeartBeat
+	query := DB.Where("monitor_id = ? AND status = ?", monitor.ID, models.StatusDown)
+	err := DB.Where("monitor_id = ? AND status <> ?", monitor.ID, models.StatusDown).
+		Order("created_at DESC").
+		First(&lastOK).Error
+	if err == nil {
+		query = query.Where("created_at > ?", lastOK.CreatedAt)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var firstDown models.HeartBeat
+	if err := query.Order("created_at ASC").First(&firstDown).Error; err == nil {
+		incident.StartedAt = firstDown.CreatedAt
+		incident.Message = firstDown.Message
+	}
+
+	return incident, DB.Create(incident).Error
+}
+
+// resolveIncident 解决故障事件,解决时间取最后一条离线心跳之后的第一条非离线心跳
+// 期间没有离线心跳(仅响应缓慢)时取当前时间
+func resolveIncident(incident *models.Incident) error {
+	resolvedAt := time.Now()
+
+	var lastDown models.HeartBeat
+	err := DB.Where("monitor_id = ? AND status = ? AND created_at >= ?",
+		incident.MonitorID, models.StatusDown, incident.StartedAt).
+		Order("created_at DESC").
+		First(&lastDown).Error
+	if err == nil {
+		var firstOK models.HeartBeat
+		err = DB.Where("monitor_id = ? AND status <> ? AND created_at > ?",
+			incident.MonitorID, models.StatusDown, lastDown.CreatedAt).
+			Order("created_at ASC").
+			First(&firstOK).Error
+		if err == nil {
+			resolvedAt = firstOK.CreatedAt
+		}
+	}
+
+	if err := DB.Model(incident).Updates(map[string]interface{}{
+		"status":      models.IncidentResolved,
+		"resolved_at": resolvedAt,
+	}).Error; err != nil {
+		return err
+	}
+	incident.Status, incident.ResolvedAt = models.IncidentResolved, &resolvedAt
+	return nil
+}
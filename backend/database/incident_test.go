@@ -0,0 +1,154 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDegradedIncidentUsesRecentMedian 响应缓慢的故障事件按最近心跳的中位数创建和解决,单次慢响应或快响应不改变事件
+func TestDegradedIncidentUsesRecentMedian(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 1)
+	if err := SaveLatencyThreshold(&models.LatencyThreshold{MonitorID: 1, ThresholdMs: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	beats := 0
+	// beat 写入一条心跳并以它作为监控项的最新响应时间同步故障事件
+	beat := func(responseTime int) []models.IncidentChange {
+		t.Helper()
+		beats++
+		heartbeat := &models.HeartBeat{MonitorID: 1, Status: models.StatusUp, ResponseTime: responseTime,
+			CreatedAt: start.Add(time.Duration(beats) * time.Minute)}
+		if err := SaveHeartBeat(heartbeat); err != nil {
+			t.Fatal(err)
+		}
+		monitors[0].ResponseTime = responseTime
+		changes, err := SyncIncidents(monitors)
+		if err != nil {
+			t.Fatalf("同步故障事件失败: %v", err)
+		}
+		return changes
+	}
+
+	for i := 0; i < 4; i++ {
+		beat(100)
+	}
+	if changes := beat(800); len(changes) != 0 {
+		t.Fatalf("单次慢响应不应创建故障事件: %+v", changes)
+	}
+	beat(800)
+	changes := beat(800)
+	if len(changes) != 1 || changes[0].Kind != models.IncidentChangeOpened || changes[0].Incident.Impact != incidentImpactDegraded {
+		t.Fatalf("中位数超过阈值时应创建响应缓慢事件: %+v", changes)
+	}
+
+	if changes := beat(100); len(changes) != 0 {
+		t.Fatalf("单次快响应不应解决故障事件: %+v", changes)
+	}
+	beat(100)
+	changes = beat(100)
+	if len(changes) != 1 || changes[0].Kind != models.IncidentChangeResolved {
+		t.Fatalf("中位数恢复后应解决故障事件: %+v", changes)
+	}
+}
+
+// TestIncidentAttributedToRootCause 因依赖离线的监控项的故障事件归入根因事件,根因恢复而依赖方仍离线时转为独立事件
+func TestIncidentAttributedToRootCause(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 3)
+	for _, edge := range [][2]int{{1, 2}, {2, 3}} {
+		if err := AddDependency(&models.MonitorDependency{MonitorID: edge[0], DependsOnID: edge[1]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range monitors {
+		monitors[i].Status = models.StatusDown
+	}
+
+	changes, err := SyncIncidents(monitors)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("应创建 3 个故障事件: %+v, %v", changes, err)
+	}
+	byMonitor := make(map[int]models.Incident)
+	for _, change := range changes {
+		byMonitor[change.Incident.MonitorID] = change.Incident
+	}
+	root := byMonitor[3]
+	if root.ParentID != 0 || root.Impact != "major" {
+		t.Errorf("根因事件应为独立的 major 事件: %+v", root)
+	}
+	for _, id := range []int{1, 2} {
+		if incident := byMonitor[id]; incident.ParentID != root.ID || incident.Impact != "none" {
+			t.Errorf("监控项 %d 的事件应归入根因事件 %d: %+v", id, root.ID, incident)
+		}
+	}
+	if changes[0].Incident.MonitorID != 3 {
+		t.Errorf("根因事件应先于依赖方创建: %+v", changes)
+	}
+
+	// 根因恢复,中间层仍离线: 根因事件解决,依赖方的事件转为独立事件
+	monitors[2].Status = models.StatusUp
+	changes, err = SyncIncidents(monitors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resolved, detached []int
+	for _, change := range changes {
+		switch change.Kind {
+		case models.IncidentChangeResolved:
+			resolved = append(resolved, change.Incident.MonitorID)
+		case models.IncidentChangeOpened:
+			if change.Incident.ParentID != 0 || change.Incident.Impact != "major" {
+				t.Errorf("转为独立的事件应为 major 且没有根因: %+v", change.Incident)
+			}
+			detached = append(detached, change.Incident.MonitorID)
+		}
+	}
+	if !reflect.DeepEqual(resolved, []int{3}) || len(detached) != 2 {
+		t.Errorf("应解决根因事件并将 2 个依赖方事件转为独立事件,实际解决 %v,转为独立 %v", resolved, detached)
+	}
+}
+
+// TestDownIncidentBecomesDegraded 离线恢复后仍然缓慢时解决离线事件,另起响应缓慢的事件
+func TestDownIncidentBecomesDegraded(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 1)
+	if err := SaveLatencyThreshold(&models.LatencyThreshold{MonitorID: 1, ThresholdMs: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	monitors[0].Status = models.StatusDown
+	changes, err := SyncIncidents(monitors)
+	if err != nil || len(changes) != 1 || changes[0].Incident.Impact != "major" {
+		t.Fatalf("离线时应创建 major 故障事件: %+v, %v", changes, err)
+	}
+	down := changes[0].Incident
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < degradedRecentSamples; i++ {
+		heartbeat := &models.HeartBeat{MonitorID: 1, Status: models.StatusUp, ResponseTime: 800, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := SaveHeartBeat(heartbeat); err != nil {
+			t.Fatal(err)
+		}
+	}
+	monitors[0].Status, monitors[0].ResponseTime = models.StatusUp, 800
+	changes, err = SyncIncidents(monitors)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("应解决离线事件并创建响应缓慢事件: %+v, %v", changes, err)
+	}
+	if changes[0].Kind != models.IncidentChangeResolved || changes[0].Incident.ID != down.ID {
+		t.Errorf("离线事件应已解决: %+v", changes[0])
+	}
+	degraded := changes[1].Incident
+	if changes[1].Kind != models.IncidentChangeOpened || degraded.Impact != incidentImpactDegraded || degraded.Title != "监控项A 响应缓慢" {
+		t.Errorf("应创建响应缓慢事件: %+v", changes[1])
+	}
+
+	if changes, err := SyncIncidents(monitors); err != nil || len(changes) != 0 {
+		t.Errorf("状态不变时不应再有变化: %+v, %v", changes, err)
+	}
+}
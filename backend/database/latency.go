@@ -0,0 +1,202 @@
+package database
+
+import (
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"math"
+	"time"
+)
+
+// latencyBaselineMinSamples 计算基线所需的最少正常心跳数,样本不足时不使用相对阈值
+const latencyBaselineMinSamples = 10
+
+// degradedRecentSamples 故障事件判断响应缓慢时取最近多少个正常心跳的中位数,单次慢响应不会创建或解决事件
+const degradedRecentSamples = 5
+
+// GetLatencyThresholds 获取所有响应时间阈值
+func GetLatencyThresholds() ([]models.LatencyThreshold, error) {
+	var thresholds []models.LatencyThreshold
+	err := DB.Order("id ASC").Find(&thresholds).Error
+	return thresholds, err
+}
+
+// GetLatencyThreshold 获取单个响应时间阈值
+func GetLatencyThreshold(id int) (*models.LatencyThreshold, error) {
+	var threshold models.LatencyThreshold
+	if err := DB.Where("id = ?", id).First(&threshold).Error; err != nil {
+		return nil, err
+	}
+	return &threshold, nil
+}
+
+// SaveLatencyThreshold 设置监控项或分组的响应时间阈值,同一目标已有阈值时替换
+func SaveLatencyThreshold(threshold *models.LatencyThreshold) error {
+	var existing models.LatencyThreshold
+	err := DB.Where("monitor_id = ? AND group_name = ?", threshold.MonitorID, threshold.GroupName).First(&existing).Error
+	if err == nil {
+		threshold.ID = existing.ID
+		threshold.CreatedAt = existing.CreatedAt
+	} else {
+		threshold.ID = 0
+	}
+	return DB.Save(threshold).Error
+}
+
+// DeleteLatencyThreshold 删除响应时间阈值
+func DeleteLatencyThreshold(id int) error {
+	return DB.Where("id = ?", id).Delete(&models.LatencyThreshold{}).Error
+}
+
+// applyEffectiveStatus 计算监控项考虑依赖关系和响应时间阈值后的状态,并标记正在抖动的监控项
+func applyEffectiveStatus(monitors []models.Monitor) error {
+	if err := applyDependencyImpact(monitors); err != nil {
+		return err
+	}
+	if err := applyLatencyThresholds(monitors); err != nil {
+		return err
+	}
+	return applyFlapping(monitors)
+}
+
+// applyLatencyThresholds 正常但响应时间超过阈值的监控项标记为响应缓慢
+// 只修改 EffectiveStatus 和 DegradedReason,调用前 EffectiveStatus 应已初始化
+// 分组阈值按应用展示覆盖后的分组匹配
+func applyLatencyThresholds(monitors []models.Monitor) error {
+	if len(monitors) == 0 {
+		return nil
+	}
+	thresholds, err := GetLatencyThresholds()
+	if err != nil || len(thresholds) == 0 {
+		return err
+	}
+
+	byMonitor := make(map[int]models.LatencyThreshold)
+	byGroup := make(map[string]models.LatencyThreshold)
+	for _, threshold := range thresholds {
+		if threshold.MonitorID != 0 {
+			byMonitor[threshold.MonitorID] = threshold
+		} else {
+			byGroup[threshold.GroupName] = threshold
+		}
+	}
+
+	ids := make([]int, 0, len(monitors))
+	for _, monitor := range monitors {
+		if monitor.Status == models.StatusUp && monitor.ResponseTime > 0 {
+			ids = append(ids, monitor.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	groups := make(map[int]string)
+	if len(byGroup) > 0 {
+		var rows []struct {
+			ID    int
+			Group string
+		}
+		err := presentedJoins(DB.Unscoped()).
+			Select("monitors.id AS id, "+presentedGroupExpr+" AS `group`").
+			Where("monitors.id IN ?", ids).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			groups[row.ID] = row.Group
+		}
+	}
+
+	resolved := make(map[int]models.LatencyThreshold)
+	var baselineIDs []int
+	for _, id := range ids {
+		threshold, ok := byMonitor[id]
+		if !ok {
+			if threshold, ok = byGroup[groups[id]]; !ok {
+				continue
+			}
+		}
+		resolved[id] = threshold
+		if threshold.BaselineFactor > 0 {
+			baselineIDs = append(baselineIDs, id)
+		}
+	}
+
+	baselines, err := latencyBaselines(baselineIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range monitors {
+		threshold, ok := resolved[monitors[i].ID]
+		if !ok {
+			continue
+		}
+		responseTime := monitors[i].ResponseTime
+		if threshold.ThresholdMs > 0 && responseTime > threshold.ThresholdMs {
+			monitors[i].EffectiveStatus = models.StatusDegraded
+			monitors[i].DegradedReason = fmt.Sprintf("响应时间 %dms 超过阈值 %dms", responseTime, threshold.ThresholdMs)
+			continue
+		}
+		if baseline, ok := baselines[monitors[i].ID]; ok && threshold.BaselineFactor > 0 &&
+			float64(responseTime) > baseline*threshold.BaselineFactor {
+			monitors[i].EffectiveStatus = models.StatusDegraded
+			monitors[i].DegradedReason = fmt.Sprintf("响应时间 %dms 超过基线 %.0fms 的 %.1f 倍",
+				responseTime, baseline, threshold.BaselineFactor)
+		}
+	}
+	return nil
+}
+
+// smoothResponseTimes 将正常监控项的响应时间替换为最近 degradedRecentSamples 个正常心跳的中位数
+// 用于故障事件判断,展示的 effectiveStatus 仍按最新响应时间;没有设置响应时间阈值时不查询
+func smoothResponseTimes(monitors []models.Monitor) error {
+	var count int64
+	if err := DB.Model(&models.LatencyThreshold{}).Count(&count).Error; err != nil || count == 0 {
+		return err
+	}
+	for i := range monitors {
+		if monitors[i].Status != models.StatusUp || monitors[i].ResponseTime <= 0 {
+			continue
+		}
+		median, err := GetRecentResponseTime(monitors[i].ID, degradedRecentSamples)
+		if err != nil {
+			return err
+		}
+		if median > 0 {
+			monitors[i].ResponseTime = int(math.Round(median))
+		}
+	}
+	return nil
+}
+
+// latencyBaselines 统计窗口内正常心跳的平均响应时间,样本不足的监控项不返回
+func latencyBaselines(ids []int) (map[int]float64, error) {
+	baselines := make(map[int]float64)
+	if len(ids) == 0 {
+		return baselines, nil
+	}
+
+	var rows []struct {
+		MonitorID int
+		Samples   int64
+		Average   float64
+	}
+	since := time.Now().Add(-config.AppConfig.LatencyBaselineWindow)
+	err := DB.Model(&models.HeartBeat{}).
+		Select("monitor_id, COUNT(*) AS samples, AVG(response_time) AS average").
+		Where("monitor_id IN ? AND status = ? AND created_at >= ?", ids, models.StatusUp, since).
+		Group("monitor_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.Samples >= latencyBaselineMinSamples && row.Average > 0 {
+			baselines[row.MonitorID] = row.Average
+		}
+	}
+	return baselines, nil
+}
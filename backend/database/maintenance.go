@@ -0,0 +1,45 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// SyncMaintenance 根据监控项最新状态记录维护期间的开始和结束
+// 与 SyncIncidents 一样只应在获取到心跳数据时调用,只处理传入的监控项
+// 返回本次开始和结束的维护期间,用于通知订阅者
+func SyncMaintenance(monitors []models.Monitor, now time.Time) (started, ended []models.MaintenancePeriod, err error) {
+	var periods []models.MaintenancePeriod
+	if err := DB.Where("ended_at IS NULL").Find(&periods).Error; err != nil {
+		return nil, nil, err
+	}
+	open := make(map[int]models.MaintenancePeriod, len(periods))
+	for _, period := range periods {
+		open[period.MonitorID] = period
+	}
+
+	for _, monitor := range monitors {
+		period, inPeriod := open[monitor.ID]
+		switch {
+		case monitor.Status == models.StatusMaintenance && !inPeriod:
+			period = models.MaintenancePeriod{MonitorID: monitor.ID, StartedAt: now}
+			if err := DB.Create(&period).Error; err != nil {
+				return nil, nil, err
+			}
+			started = append(started, period)
+		case monitor.Status != models.StatusMaintenance && inPeriod:
+			if err := DB.Model(&period).Update("ended_at", now).Error; err != nil {
+				return nil, nil, err
+			}
+			period.EndedAt = &now
+			ended = append(ended, period)
+		}
+	}
+	return started, ended, nil
+}
+
+// CleanOldMaintenance 清理已结束的旧维护期间
+func CleanOldMaintenance(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("ended_at IS NOT NULL AND ended_at < ?", threshold).Delete(&models.MaintenancePeriod{}).Error
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// GetNotificationChannels 获取所有通知渠道
+func GetNotificationChannels() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := DB.Order("id ASC").Find(&channels).Error
+	return channels, err
+}
+
+// GetEnabledNotificationChannels 获取未停用的通知渠道
+func GetEnabledNotificationChannels() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := DB.Where("disabled = ?", false).Order("id ASC").Find(&channels).Error
+	return channels, err
+}
+
+// GetNotificationChannel 获取单个通知渠道
+func GetNotificationChannel(id int) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	if err := DB.Where("id = ?", id).First(&channel).Error; err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// SaveNotificationChannel 创建或更新通知渠道,ID 为 0 时新建
+func SaveNotificationChannel(channel *models.NotificationChannel) error {
+	return DB.Save(channel).Error
+}
+
+// DeleteNotificationChannel 删除通知渠道
+func DeleteNotificationChannel(id int) error {
+	return DB.Where("id = ?", id).Delete(&models.NotificationChannel{}).Error
+}
+
+// GetNotificationRules 获取所有通知路由规则
+func GetNotificationRules() ([]models.NotificationRule, error) {
+	var rules []models.NotificationRule
+	err := DB.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetNotificationRule 获取单个通知路由规则
+func GetNotificationRule(id int) (*models.NotificationRule, error) {
+	var rule models.NotificationRule
+	if err := DB.Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveNotificationRule 创建或更新通知路由规则,ID 为 0 时新建
+func SaveNotificationRule(rule *models.NotificationRule) error {
+	return DB.Save(rule).Error
+}
+
+// DeleteNotificationRule 删除通知路由规则
+func DeleteNotificationRule(id int) error {
+	return DB.Where("id = ?", id).Delete(&models.NotificationRule{}).Error
+}
+
+// CreateNotificationDeliveries 记录待发送或不发送的通知
+func CreateNotificationDeliveries(deliveries []models.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return DB.Create(&deliveries).Error
+}
+
+// GetDueNotificationDeliveries 获取需要发送的通知: 待发送且已到重试时间的,以及延后时间已到的
+func GetDueNotificationDeliveries(now time.Time) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := DB.Where("(status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)) OR (status = ? AND send_after <= ?)",
+		models.DeliveryPending, now, models.DeliveryDeferred, now).
+		Order("id ASC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FinishNotificationDelivery 记录通知的发送结果
+func FinishNotificationDelivery(delivery *models.NotificationDelivery) error {
+	return DB.Model(delivery).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"error":           delivery.Error,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"sent_at":         delivery.SentAt,
+	}).Error
+}
+
+// GetIncidentNotificationDeliveries 获取故障事件的通知记录,用于判断重复提醒和升级
+func GetIncidentNotificationDeliveries(incidentIDs []int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	if len(incidentIDs) == 0 {
+		return deliveries, nil
+	}
+	err := DB.Where("incident_id IN ?", incidentIDs).Order("id ASC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+// NotificationDeliveryQuery 发送历史的过滤条件,零值表示不过滤
+type NotificationDeliveryQuery struct {
+	ChannelID  int
+	IncidentID int
+	Status     string
+	Since      time.Time
+	Limit      int
+}
+
+// GetNotificationDeliveries 获取通知发送历史(按记录时间倒序)
+func GetNotificationDeliveries(query NotificationDeliveryQuery) ([]models.NotificationDelivery, error) {
+	db := DB.Where("created_at >= ?", query.Since)
+	if query.ChannelID != 0 {
+		db = db.Where("channel_id = ?", query.ChannelID)
+	}
+	if query.IncidentID != 0 {
+		db = db.Where("incident_id = ?", query.IncidentID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var deliveries []models.NotificationDelivery
+	err := db.Order("created_at DESC").Order("id DESC").Limit(query.Limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// CleanOldNotificationDeliveries 清理旧的通知发送记录,保留仍待发送的记录
+func CleanOldNotificationDeliveries(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("created_at < ? AND status NOT IN ?", threshold, []string{models.DeliveryPending, models.DeliveryDeferred}).
+		Delete(&models.NotificationDelivery{}).Error
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// 应用展示覆盖后的字段表达式
+// 查询通过 LEFT JOIN monitor_overrides(mo) 和 group_overrides(og) 取得覆盖值
+const (
+	presentedGroupExpr = "COALESCE(NULLIF(mo.group_name, ''), monitors.`group`)"
+	presentedNameExpr  = "COALESCE(NULLIF(mo.display_name, ''), monitors.name)"
+	// 分组顺序: 分组覆盖 > 目标分组在 Kuma 中的顺序 > 原分组顺序
+	presentedGroupOrderExpr = "COALESCE(og.sort_order, (SELECT MIN(m2.group_order) FROM monitors AS m2 " +
+		"WHERE m2.`group` = " + presentedGroupExpr + " AND m2.removed_at IS NULL), monitors.group_order)"
+	presentedSortOrderExpr = "COALESCE(mo.sort_order, monitors.position)"
+)
+
+// presentedColumns 覆盖字段对应的表达式,其余字段直接取 monitors 表
+var presentedColumns = map[string]string{
+	"name":        presentedNameExpr,
+	"group":       presentedGroupExpr,
+	"group_order": presentedGroupOrderExpr,
+	"sort_order":  presentedSortOrderExpr,
+	"icon":        "COALESCE(mo.icon, '')",
+	"link":        "COALESCE(mo.link, '')",
+}
+
+var (
+	presentedSelectOnce sync.Once
+	presentedSelect     string
+)
+
+// presentedMonitors 返回应用了展示覆盖的监控项查询
+// 名称、分组、顺序、图标和链接取覆盖值,Kuma 的原始数据不变
+func presentedMonitors(db *gorm.DB) *gorm.DB {
+	return presentedJoins(db).Select(presentedSelectList())
+}
+
+// presentedJoins 关联覆盖表,用于按覆盖后的值过滤和计数
+func presentedJoins(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Monitor{}).
+		Joins("LEFT JOIN monitor_overrides AS mo ON mo.monitor_id = monitors.id").
+		Joins("LEFT JOIN group_overrides AS og ON og.group_name = " + presentedGroupExpr)
+}
+
+// presentedSelectList 按 Monitor 的字段生成查询列,覆盖字段替换为对应表达式
+func presentedSelectList() string {
+	presentedSelectOnce.Do(func() {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(&models.Monitor{}); err != nil {
+			panic(err)
+		}
+		columns := make([]string, 0, len(stmt.Schema.DBNames))
+		for _, name := range stmt.Schema.DBNames {
+			if expr, ok := presentedColumns[name]; ok {
+				columns = append(columns, expr+" AS `"+name+"`")
+			} else {
+				columns = append(columns, "monitors.`"+name+"`")
+			}
+		}
+		presentedSelect = strings.Join(columns, ", ")
+	})
+	return presentedSelect
+}
+
+// GetMonitorOverrides 获取所有监控项展示覆盖
+func GetMonitorOverrides() ([]models.MonitorOverride, error) {
+	var overrides []models.MonitorOverride
+	err := DB.Order("monitor_id ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// GetMonitorOverride 获取单个监控项的展示覆盖
+func GetMonitorOverride(monitorID int) (*models.MonitorOverride, error) {
+	var override models.MonitorOverride
+	if err := DB.Where("monitor_id = ?", monitorID).First(&override).Error; err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// SaveMonitorOverride 创建或替换监控项展示覆盖
+func SaveMonitorOverride(override *models.MonitorOverride) error {
+	return DB.Save(override).Error
+}
+
+// DeleteMonitorOverride 删除监控项展示覆盖
+func DeleteMonitorOverride(monitorID int) error {
+	return DB.Where("monitor_id = ?", monitorID).Delete(&models.MonitorOverride{}).Error
+}
+
+// GetGroupOverrides 获取所有分组顺序覆盖
+func GetGroupOverrides() ([]models.GroupOverride, error) {
+	var overrides []models.GroupOverride
+	err := DB.Order("sort_order ASC").Order("group_name ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// GetGroupOverride 获取单个分组的顺序覆盖
+func GetGroupOverride(groupName string) (*models.GroupOverride, error) {
+	var override models.GroupOverride
+	if err := DB.Where("group_name = ?", groupName).First(&override).Error; err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// SaveGroupOverride 创建或替换分组顺序覆盖
+func SaveGroupOverride(override *models.GroupOverride) error {
+	return DB.Save(override).Error
+}
+
+// DeleteGroupOverride 删除分组顺序覆盖
+func DeleteGroupOverride(groupName string) error {
+	return DB.Where("group_name = ?", groupName).Delete(&models.GroupOverride{}).Error
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"slices"
+	"testing"
+)
+
+// TestOverridesAppliedOnRead 读取时应用展示覆盖,过滤和排序按覆盖后的值,Kuma 的原始数据不变
+func TestOverridesAppliedOnRead(t *testing.T) {
+	setupTestDB(t)
+	// 应用(分组顺序 0): 1、3;数据库(分组顺序 1): 2
+	monitors := []models.Monitor{
+		{ID: 1, Name: "官网", Group: "应用", GroupOrder: 0, Position: 0, Status: models.StatusUp},
+		{ID: 2, Name: "主库", Group: "数据库", GroupOrder: 1, Position: 2, Status: models.StatusUp},
+		{ID: 3, Name: "后台", Group: "应用", GroupOrder: 0, Position: 1, Status: models.StatusUp},
+	}
+	for i := range monitors {
+		if err := SaveMonitor(&monitors[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first := 0
+	override := &models.MonitorOverride{MonitorID: 3, DisplayName: "管理后台", GroupName: "数据库", SortOrder: &first, Icon: "admin.png", Link: "https://admin.example.com"}
+	if err := SaveMonitorOverride(override); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor, err := GetMonitorByID(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Name != "管理后台" || monitor.Group != "数据库" || monitor.GroupOrder != 1 || monitor.SortOrder != 0 ||
+		monitor.Icon != "admin.png" || monitor.Link != "https://admin.example.com" {
+		t.Errorf("应用覆盖后的监控项不正确: %+v", monitor)
+	}
+	var raw models.Monitor
+	if err := DB.First(&raw, 3).Error; err != nil || raw.Name != "后台" || raw.Group != "应用" {
+		t.Errorf("覆盖不应修改原始数据: %+v, %v", raw, err)
+	}
+
+	tests := []struct {
+		name  string
+		query MonitorQuery
+		want  []int
+	}{
+		{"按覆盖后的分组过滤", MonitorQuery{Group: "数据库"}, []int{2, 3}},
+		{"原分组不再包含", MonitorQuery{Group: "应用"}, []int{1}},
+		{"按覆盖后的名称搜索", MonitorQuery{Name: "管理"}, []int{3}},
+		{"原名称不再匹配", MonitorQuery{Name: "后台"}, []int{3}},
+		{"按覆盖后的名称排序", MonitorQuery{Sort: "name"}, []int{2, 1, 3}},
+		{"覆盖的顺序排在分组内最前", MonitorQuery{Sort: "group"}, []int{1, 3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := QueryMonitors(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, monitor := range result {
+				got = append(got, monitor.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("应返回 %v,实际 %v", tt.want, got)
+			}
+		})
+	}
+
+	// 分组覆盖调整分组顺序
+	if err := SaveGroupOverride(&models.GroupOverride{GroupName: "数据库", SortOrder: -1}); err != nil {
+		t.Fatal(err)
+	}
+	result, _, err := QueryMonitors(MonitorQuery{Sort: "group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for _, monitor := range result {
+		got = append(got, monitor.ID)
+	}
+	if want := []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Errorf("分组覆盖后应返回 %v,实际 %v", want, got)
+	}
+
+	if err := DeleteMonitorOverride(3); err != nil {
+		t.Fatal(err)
+	}
+	if monitor, err := GetMonitorByID(3); err != nil || monitor.Name != "后台" || monitor.Group != "应用" || monitor.Icon != "" {
+		t.Errorf("删除覆盖后应恢复原始值: %+v, %v", monitor, err)
+	}
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MonitorQuery 监控项列表的过滤、排序和分页条件
+type MonitorQuery struct {
+	Statuses []int    // 状态,按 EffectiveStatus 匹配,为空时不过滤
+	Group    string   // 分组
+	Type     string   // 监控类型
+	Name     string   // 名称子串,不区分大小写
+	Source   string   // 数据来源
+	Tags     []string // 标签,name 或 name:value,需同时满足
+	Archived bool     // 是否包含已归档的监控项
+	Sort     string   // name, uptime, responseTime, group,默认按 ID
+	Desc     bool     // 是否倒序
+	Limit    int      // 0 表示不分页
+	Offset   int
+}
+
+// monitorSortColumns 允许排序的字段,避免拼接任意列名
+// 名称和分组按应用展示覆盖后的值排序
+var monitorSortColumns = map[string]string{
+	"name":         presentedNameExpr + " COLLATE NOCASE",
+	"uptime":       "monitors.uptime",
+	"responseTime": "monitors.response_time",
+	"group":        presentedGroupOrderExpr,
+}
+
+// QueryMonitors 按条件查询监控项,返回当前页数据和过滤后的总数
+func QueryMonitors(q MonitorQuery) ([]models.Monitor, int64, error) {
+	query := presentedJoins(DB)
+	if q.Archived {
+		query = query.Unscoped()
+	}
+	if q.Group != "" {
+		query = query.Where(presentedGroupExpr+" = ?", q.Group)
+	}
+	if q.Type != "" {
+		query = query.Where("monitors.type = ?", q.Type)
+	}
+	if q.Source != "" {
+		query = query.Where("monitors.source = ?", q.Source)
+	}
+	if q.Name != "" {
+		// 转义 LIKE 通配符,按字面子串匹配
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(q.Name)
+		query = query.Where(presentedNameExpr+" LIKE ? ESCAPE '\\'", "%"+escaped+"%")
+	}
+
+	for _, tag := range q.Tags {
+		name, value, hasValue := strings.Cut(tag, ":")
+		sub := DB.Model(&models.MonitorTag{}).Select("monitor_id").Where("name = ? COLLATE NOCASE", name)
+		if hasValue {
+			sub = sub.Where("value = ?", value)
+		}
+		query = query.Where("monitors.id IN (?)", sub)
+	}
+
+	if len(q.Statuses) > 0 {
+		ids, err := effectiveStatusIDs(query.Session(&gorm.Session{}), q.Statuses)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("monitors.id IN ?", ids)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := " ASC"
+	if q.Desc {
+		direction = " DESC"
+	}
+	if column, ok := monitorSortColumns[q.Sort]; ok {
+		query = query.Order(column + direction)
+		if q.Sort == "group" {
+			// 同一分组内按分组内顺序排列
+			query = query.Order(presentedSortOrderExpr + direction)
+		}
+		// 排序值相同时按 ID 排列,保证分页结果稳定
+		query = query.Order("monitors.id ASC")
+	} else {
+		query = query.Order("monitors.id" + direction)
+	}
+
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit).Offset(q.Offset)
+	}
+
+	var monitors []models.Monitor
+	if err := query.Select(presentedSelectList()).Preload("Tags").Find(&monitors).Error; err != nil {
+		return nil, 0, err
+	}
+	return monitors, total, applyEffectiveStatus(monitors)
+}
+
+// effectiveStatusIDs 返回 EffectiveStatus 属于 statuses 的监控项 ID
+// EffectiveStatus 在读取时计算,需先按原始状态取出候选项再计算,之后才能计数和分页
+func effectiveStatusIDs(query *gorm.DB, statuses []int) ([]int, error) {
+	// 受影响由离线推导,响应缓慢由正常推导,其余状态与原始状态相同
+	raw := make([]int, 0, len(statuses))
+	for _, status := range statuses {
+		switch status {
+		case models.StatusImpacted:
+			raw = append(raw, models.StatusDown)
+		case models.StatusDegraded:
+			raw = append(raw, models.StatusUp)
+		default:
+			raw = append(raw, status)
+		}
+	}
+
+	var candidates []models.Monitor
+	err := query.Where("monitors.status IN ?", raw).
+		Select("monitors.id, monitors.status, monitors.response_time").
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEffectiveStatus(candidates); err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	for _, monitor := range candidates {
+		if slices.Contains(statuses, monitor.EffectiveStatus) {
+			ids = append(ids, monitor.ID)
+		}
+	}
+	return ids, nil
+}
+
+// GetCertificates 获取有证书信息的监控项,按剩余天数升序
+func GetCertificates() ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	err := presentedMonitors(DB).Where("monitors.cert_expiry_days IS NOT NULL").
+		Order("monitors.cert_expiry_days ASC").
+		Order("monitors.id ASC").
+		Find(&monitors).Error
+	return monitors, err
+}
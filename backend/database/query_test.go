@@ -0,0 +1,106 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"slices"
+	"testing"
+)
+
+// TestQueryMonitorsEffectiveStatus 状态过滤按 EffectiveStatus 匹配,总数和分页只计算匹配的监控项
+func TestQueryMonitorsEffectiveStatus(t *testing.T) {
+	setupTestDB(t)
+	// 1 离线(根因),2 离线且依赖 1(受影响),3 响应缓慢,4 正常,5 维护
+	monitors := saveMonitors(t, 5)
+	monitors[0].Status = models.StatusDown
+	monitors[1].Status = models.StatusDown
+	monitors[2].ResponseTime = 800
+	monitors[3].ResponseTime = 100
+	monitors[4].Status = models.StatusMaintenance
+	for i := range monitors {
+		if err := SaveMonitor(&monitors[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddDependency(&models.MonitorDependency{MonitorID: 2, DependsOnID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLatencyThreshold(&models.LatencyThreshold{MonitorID: 3, ThresholdMs: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		statuses []int
+		limit    int
+		want     []int
+		total    int64
+	}{
+		{"离线不含受影响", []int{models.StatusDown}, 0, []int{1}, 1},
+		{"受影响", []int{models.StatusImpacted}, 0, []int{2}, 1},
+		{"响应缓慢", []int{models.StatusDegraded}, 0, []int{3}, 1},
+		{"正常不含响应缓慢", []int{models.StatusUp}, 0, []int{4}, 1},
+		{"维护", []int{models.StatusMaintenance}, 0, []int{5}, 1},
+		{"多个状态", []int{models.StatusImpacted, models.StatusDegraded}, 0, []int{2, 3}, 2},
+		{"分页", []int{models.StatusDown, models.StatusImpacted, models.StatusDegraded}, 2, []int{1, 2}, 3},
+		{"没有匹配", []int{models.StatusPending}, 0, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := QueryMonitors(MonitorQuery{Statuses: tt.statuses, Limit: tt.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, monitor := range result {
+				if !slices.Contains(tt.statuses, monitor.EffectiveStatus) {
+					t.Errorf("监控项 %d 的 effectiveStatus %d 不在过滤条件中", monitor.ID, monitor.EffectiveStatus)
+				}
+				got = append(got, monitor.ID)
+			}
+			if !slices.Equal(got, tt.want) || total != tt.total {
+				t.Errorf("应返回 %v,总数 %d,实际 %v,总数 %d", tt.want, tt.total, got, total)
+			}
+		})
+	}
+}
+
+// TestQueryMonitorsTags 标签按 name 或 name:value 过滤,名称不区分大小写,多个标签需同时满足
+func TestQueryMonitorsTags(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 3)
+	monitors[0].Tags = []models.MonitorTag{{Name: "env", Value: "prod"}, {Name: "team", Value: "core"}}
+	monitors[1].Tags = []models.MonitorTag{{Name: "env", Value: "dev"}}
+	for i := range monitors {
+		if err := SaveMonitor(&monitors[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []int
+	}{
+		{"按名称", []string{"env"}, []int{1, 2}},
+		{"按名称和值", []string{"env:prod"}, []int{1}},
+		{"名称不区分大小写", []string{"ENV:dev"}, []int{2}},
+		{"值区分大小写", []string{"env:PROD"}, nil},
+		{"同时满足多个标签", []string{"env", "team:core"}, []int{1}},
+		{"没有匹配", []string{"owner"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := QueryMonitors(MonitorQuery{Tags: tt.tags})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, monitor := range result {
+				got = append(got, monitor.ID)
+			}
+			if !slices.Equal(got, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("应返回 %v,实际 %v,总数 %d", tt.want, got, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// 可靠性计算中心跳状态的归类
+const (
+	reliabilityUp          = iota // 正常或重试中
+	reliabilityDown               // 离线
+	reliabilityMaintenance        // 维护中,不计入时长
+)
+
+// reliabilityState 将心跳状态归类
+func reliabilityState(status int) int {
+	switch status {
+	case models.StatusDown:
+		return reliabilityDown
+	case models.StatusMaintenance:
+		return reliabilityMaintenance
+	default:
+		return reliabilityUp
+	}
+}
+
+// reliabilityAccumulator 累计时长和次数,监控项和分组共用
+type reliabilityAccumulator struct {
+	hasData        bool
+	uptime         time.Duration
+	downtime       time.Duration
+	failures       int
+	recovered      int
+	recoveredTotal time.Duration
+	longest        time.Duration
+	longestAt      time.Time
+	flaps          int
+}
+
+// add 合并另一个累计结果
+func (a *reliabilityAccumulator) add(b *reliabilityAccumulator) {
+	a.hasData = a.hasData || b.hasData
+	a.uptime += b.uptime
+	a.downtime += b.downtime
+	a.failures += b.failures
+	a.recovered += b.recovered
+	a.recoveredTotal += b.recoveredTotal
+	if b.longest > a.longest {
+		a.longest = b.longest
+		a.longestAt = b.longestAt
+	}
+	a.flaps += b.flaps
+}
+
+// outage 记录一次离线,recovered 表示在范围内开始且已恢复,计入 MTTR
+func (a *reliabilityAccumulator) outage(start, end time.Time, recovered bool) {
+	duration := end.Sub(start)
+	if recovered {
+		a.recovered++
+		a.recoveredTotal += duration
+	}
+	if duration > a.longest {
+		a.longest = duration
+		a.longestAt = start
+	}
+}
+
+// addSegment 累计一段状态的时长
+func (a *reliabilityAccumulator) addSegment(state int, duration time.Duration) {
+	switch state {
+	case reliabilityUp:
+		a.uptime += duration
+	case reliabilityDown:
+		a.downtime += duration
+	}
+}
+
+// stats 计算最终指标,days 为范围的天数
+func (a *reliabilityAccumulator) stats(days float64) models.ReliabilityStats {
+	stats := models.ReliabilityStats{
+		Availability:  -1,
+		Uptime:        int64(a.uptime.Seconds()),
+		Downtime:      int64(a.downtime.Seconds()),
+		Failures:      a.failures,
+		LongestOutage: int64(a.longest.Seconds()),
+		Flaps:         a.flaps,
+	}
+	if total := a.uptime + a.downtime; a.hasData && total > 0 {
+		stats.Availability = a.uptime.Seconds() / total.Seconds()
+	}
+	if a.recovered > 0 {
+		stats.MTTR = a.recoveredTotal.Seconds() / float64(a.recovered)
+	}
+	if a.failures > 0 {
+		stats.MTBF = a.uptime.Seconds() / float64(a.failures)
+	}
+	if !a.longestAt.IsZero() {
+		longestAt := a.longestAt
+		stats.LongestAt = &longestAt
+	}
+	if days > 0 {
+		stats.FlapsPerDay = float64(a.flaps) / days
+	}
+	return stats
+}
+
+// reliabilityPoint 状态开始的时间点
+type reliabilityPoint struct {
+	MonitorID int
+	Status    int
+	CreatedAt time.Time
+}
+
+// GetReliabilityReport 根据心跳计算监控项和分组在 [from, to) 内的可靠性指标
+// 范围开始时的状态取 from 之前的最后一条心跳,最后的状态持续到 to(不晚于当前时间)
+// 分组按 monitors 中的分组汇总,monitors 应已应用展示覆盖
+func GetReliabilityReport(monitors []models.Monitor, from, to time.Time) (*models.ReliabilityReport, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	report := &models.ReliabilityReport{
+		From:     from,
+		To:       to,
+		Monitors: []models.MonitorReliability{},
+		Groups:   []models.GroupReliability{},
+	}
+	if len(monitors) == 0 || !to.After(from) {
+		return report, nil
+	}
+
+	ids := make([]int, 0, len(monitors))
+	for _, monitor := range monitors {
+		ids = append(ids, monitor.ID)
+	}
+
+	// SQLite 中与 MAX 一起查询的列取自最大值所在的行
+	var initial []struct {
+		MonitorID int
+		Status    int
+		Latest    string
+	}
+	err := DB.Model(&models.HeartBeat{}).
+		Select("monitor_id, status, MAX(created_at) AS latest").
+		Where("monitor_id IN ? AND created_at < ?", ids, from).
+		Group("monitor_id").
+		Scan(&initial).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 只取状态变化的心跳,避免读取范围内的全部心跳
+	inner := DB.Model(&models.HeartBeat{}).
+		Select("monitor_id, status, created_at, "+
+			"LAG(status) OVER (PARTITION BY monitor_id ORDER BY created_at) AS prev_status").
+		Where("monitor_id IN ? AND created_at >= ? AND created_at < ?", ids, from, to)
+	var changes []reliabilityPoint
+	err = DB.Table("(?) AS hb", inner).
+		Select("monitor_id, status, created_at").
+		Where("prev_status IS NULL OR prev_status <> status").
+		Order("monitor_id ASC").
+		Order("created_at ASC").
+		Scan(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[int][]reliabilityPoint, len(monitors))
+	for _, row := range initial {
+		points[row.MonitorID] = append(points[row.MonitorID], reliabilityPoint{MonitorID: row.MonitorID, Status: row.Status, CreatedAt: from})
+	}
+	for _, change := range changes {
+		points[change.MonitorID] = append(points[change.MonitorID], change)
+	}
+
+	days := to.Sub(from).Hours() / 24
+	groups := make(map[string]*reliabilityAccumulator)
+	groupMonitors := make(map[string]int)
+	var groupOrder []string
+	for _, monitor := range monitors {
+		acc := reliabilityTimeline(points[monitor.ID], to)
+		report.Monitors = append(report.Monitors, models.MonitorReliability{
+			MonitorID:        monitor.ID,
+			Name:             monitor.Name,
+			Group:            monitor.Group,
+			ReliabilityStats: acc.stats(days),
+		})
+
+		group, ok := groups[monitor.Group]
+		if !ok {
+			group = &reliabilityAccumulator{}
+			groups[monitor.Group] = group
+			groupOrder = append(groupOrder, monitor.Group)
+		}
+		group.add(acc)
+		groupMonitors[monitor.Group]++
+	}
+
+	for _, name := range groupOrder {
+		report.Groups = append(report.Groups, models.GroupReliability{
+			Group:            name,
+			Monitors:         groupMonitors[name],
+			ReliabilityStats: groups[name].stats(days),
+		})
+	}
+	return report, nil
+}
+
+// reliabilityTimeline 按时间顺序遍历状态变化,累计时长、故障和切换次数
+// 范围开始时已离线的故障计入时长和最长离线,但不计入故障次数和 MTTR
+func reliabilityTimeline(points []reliabilityPoint, to time.Time) *reliabilityAccumulator {
+	acc := &reliabilityAccumulator{}
+	if len(points) == 0 {
+		return acc
+	}
+	acc.hasData = true
+
+	state := -1
+	lastActive := -1 // 最近一个非维护中的状态,用于判断切换
+	var segmentStart, outageStart time.Time
+	outageInRange := false
+
+	for i, point := range points {
+		next := reliabilityState(point.Status)
+		if i > 0 && next == state {
+			continue
+		}
+		if state != -1 {
+			acc.addSegment(state, point.CreatedAt.Sub(segmentStart))
+		}
+
+		if state == reliabilityDown && next != reliabilityDown {
+			acc.outage(outageStart, point.CreatedAt, outageInRange && next == reliabilityUp)
+		}
+		if next == reliabilityDown && state != reliabilityDown {
+			outageStart = point.CreatedAt
+			// 第一个点的离线开始时间未知,不计入故障次数
+			outageInRange = i > 0
+			if outageInRange {
+				acc.failures++
+			}
+		}
+		if next != reliabilityMaintenance {
+			if lastActive != -1 && lastActive != next {
+				acc.flaps++
+			}
+			lastActive = next
+		}
+
+		state = next
+		segmentStart = point.CreatedAt
+	}
+
+	acc.addSegment(state, to.Sub(segmentStart))
+	if state == reliabilityDown {
+		acc.outage(outageStart, to, false)
+	}
+	return acc
+}
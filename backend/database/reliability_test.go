@@ -0,0 +1,79 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestGetReliabilityReport 按 LAG 窗口取出的状态变化计算时长、故障次数、MTTR 和 MTBF
+func TestGetReliabilityReport(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 3)
+
+	from := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Hour)
+	to := from.Add(10 * time.Hour)
+	const down, up, maintenance = models.StatusDown, models.StatusUp, models.StatusMaintenance
+	beats := []struct {
+		monitorID int
+		status    int
+		at        float64 // 距 from 的小时数
+	}{
+		// 监控项 1: 范围前正常,1-2h 和 5-8h 离线
+		{1, up, -1}, {1, up, 0.5}, {1, down, 1}, {1, down, 1.5}, {1, up, 2}, {1, down, 5}, {1, up, 8},
+		{1, down, 10}, // 范围结束时刻的心跳不计入
+		// 监控项 2: 范围开始时已离线,3h 恢复,4-6h 维护,9h 再次离线直到结束
+		{2, down, -1}, {2, up, 3}, {2, maintenance, 4}, {2, up, 6}, {2, down, 9},
+		// 监控项 3: 没有心跳
+	}
+	for _, beat := range beats {
+		heartbeat := &models.HeartBeat{MonitorID: beat.monitorID, Status: beat.status,
+			CreatedAt: from.Add(time.Duration(beat.at * float64(time.Hour)))}
+		if err := SaveHeartBeat(heartbeat); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := GetReliabilityReport(monitors, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hour := int64(time.Hour.Seconds())
+	tests := []struct {
+		name         string
+		got          models.ReliabilityStats
+		availability float64
+		uptime       int64
+		downtime     int64
+		failures     int
+		mttr, mtbf   float64
+		longest      int64
+		longestAt    time.Time
+		flaps        int
+	}{
+		{"恢复的故障计入 MTTR", report.Monitors[0].ReliabilityStats,
+			0.6, 6 * hour, 4 * hour, 2, 2 * 3600, 3 * 3600, 3 * hour, from.Add(5 * time.Hour), 4},
+		{"范围开始前的故障和未恢复的故障不计入 MTTR", report.Monitors[1].ReliabilityStats,
+			0.5, 4 * hour, 4 * hour, 1, 0, 4 * 3600, 3 * hour, from, 2},
+		{"没有心跳", report.Monitors[2].ReliabilityStats,
+			-1, 0, 0, 0, 0, 0, 0, time.Time{}, 0},
+		{"分组汇总", report.Groups[0].ReliabilityStats,
+			10.0 / 18, 10 * hour, 8 * hour, 3, 2 * 3600, 12000, 3 * hour, from.Add(5 * time.Hour), 6},
+	}
+	for _, tt := range tests {
+		got := tt.got
+		if math.Abs(got.Availability-tt.availability) > 1e-9 || got.Uptime != tt.uptime || got.Downtime != tt.downtime ||
+			got.Failures != tt.failures || got.MTTR != tt.mttr || got.MTBF != tt.mtbf || got.Flaps != tt.flaps {
+			t.Errorf("%s: %+v", tt.name, got)
+		}
+		if got.LongestOutage != tt.longest || (got.LongestAt == nil) != tt.longestAt.IsZero() ||
+			(got.LongestAt != nil && !got.LongestAt.Equal(tt.longestAt)) {
+			t.Errorf("%s: 最长离线 %d 开始于 %v,应为 %d 开始于 %v", tt.name, got.LongestOutage, got.LongestAt, tt.longest, tt.longestAt)
+		}
+	}
+	if len(report.Groups) != 1 || report.Groups[0].Monitors != 3 {
+		t.Errorf("应有 1 个包含 3 个监控项的分组: %+v", report.Groups)
+	}
+}
@@ -1,40 +1,141 @@
 package database
 
 import (
+	"fmt"
 	"kuma-lite/backend/models"
 	"log"
 	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SaveMonitor 保存或更新监控项
 func SaveMonitor(monitor *models.Monitor) error {
 	var existing models.Monitor
-	result := DB.Where("id = ?", monitor.ID).First(&existing)
+	result := DB.Unscoped().Where("id = ?", monitor.ID).First(&existing)
 
 	if result.Error != nil {
 		// 不存在,创建新记录
-		return DB.Create(monitor).Error
+		if err := DB.Omit(clause.Associations).Create(monitor).Error; err != nil {
+			return err
+		}
+		return syncMonitorTags(monitor)
+	}
+
+	if err := restoreMonitor(&existing); err != nil {
+		return err
 	}
 
-	// 存在,更新记录
-	return DB.Model(&existing).Updates(monitor).Error
+	if err := recordMonitorChanges(&existing, monitor); err != nil {
+		return err
+	}
+
+	// 存在,更新记录。显式指定字段,使离线(0)、可用率 0 等零值也能写入
+	if err := DB.Model(&existing).Select(monitorDataFields).Updates(monitor).Error; err != nil {
+		return err
+	}
+	return syncMonitorTags(monitor)
 }
 
-// GetAllMonitors 获取所有监控项
+// SaveMonitorMetadata 保存监控项,但不修改状态、可用率和响应时间
+// 用于没有心跳数据的情况,避免用未知状态覆盖最后一次已知状态
+func SaveMonitorMetadata(monitor *models.Monitor) error {
+	var existing models.Monitor
+	result := DB.Unscoped().Where("id = ?", monitor.ID).First(&existing)
+
+	if result.Error != nil {
+		// 不存在,按解析出的默认状态创建
+		if err := DB.Omit(clause.Associations).Create(monitor).Error; err != nil {
+			return err
+		}
+		return syncMonitorTags(monitor)
+	}
+
+	if err := restoreMonitor(&existing); err != nil {
+		return err
+	}
+
+	if err := recordMonitorChanges(&existing, monitor); err != nil {
+		return err
+	}
+
+	if err := DB.Model(&existing).Select(monitorMetadataFields).Updates(monitor).Error; err != nil {
+		return err
+	}
+	return syncMonitorTags(monitor)
+}
+
+// restoreMonitor 已归档的监控项重新出现在数据源中时恢复
+func restoreMonitor(existing *models.Monitor) error {
+	if !existing.RemovedAt.Valid {
+		return nil
+	}
+	if err := DB.Unscoped().Model(existing).Update("removed_at", nil).Error; err != nil {
+		return err
+	}
+	log.Printf("监控项重新出现,已恢复: [%s] (ID: %d)", existing.Name, existing.ID)
+	return CreateEvent(&models.Event{
+		Kind:      models.EventMonitorRestored,
+		MonitorID: existing.ID,
+		Title:     fmt.Sprintf("%s 重新出现在状态页", existing.Name),
+	})
+}
+
+// syncMonitorTags 用 monitor.Tags 替换已保存的标签
+// Tags 为 nil 表示数据源没有提供标签信息,保留原有标签
+func syncMonitorTags(monitor *models.Monitor) error {
+	if monitor.Tags == nil {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("monitor_id = ?", monitor.ID).Delete(&models.MonitorTag{}).Error; err != nil {
+			return err
+		}
+		if len(monitor.Tags) == 0 {
+			return nil
+		}
+		tags := make([]models.MonitorTag, len(monitor.Tags))
+		for i, tag := range monitor.Tags {
+			tags[i] = models.MonitorTag{MonitorID: monitor.ID, Name: tag.Name, Value: tag.Value, Color: tag.Color}
+		}
+		return tx.Create(&tags).Error
+	})
+}
+
+// monitorMetadataFields 从数据源同步的监控项基本信息字段
+var monitorMetadataFields = []string{"Name", "Type", "URL", "Source", "Group", "GroupOrder", "Position",
+	"Description", "CertExpiryDays", "CertValid"}
+
+// monitorDataFields 从数据源同步的全部字段,包括状态
+var monitorDataFields = append(append([]string{}, monitorMetadataFields...), "Status", "Uptime", "ResponseTime")
+
+// GetAllMonitors 获取所有监控项,按分组内的展示顺序排列
 func GetAllMonitors() ([]models.Monitor, error) {
 	var monitors []models.Monitor
-	err := DB.Order("id ASC").Find(&monitors).Error
-	return monitors, err
+	err := presentedMonitors(DB).
+		Order(presentedSortOrderExpr + " ASC").
+		Order("monitors.id ASC").
+		Find(&monitors).Error
+	if err != nil {
+		return nil, err
+	}
+	return monitors, applyEffectiveStatus(monitors)
 }
 
-// GetMonitorByID 根据 ID 获取监控项
+// GetMonitorByID 根据 ID 获取监控项,包括已归档的监控项
 func GetMonitorByID(id int) (*models.Monitor, error) {
 	var monitor models.Monitor
-	err := DB.Where("id = ?", id).First(&monitor).Error
+	err := presentedMonitors(DB.Unscoped()).Preload("Tags").Where("monitors.id = ?", id).First(&monitor).Error
 	if err != nil {
 		return nil, err
 	}
-	return &monitor, nil
+	monitors := []models.Monitor{monitor}
+	if err := applyEffectiveStatus(monitors); err != nil {
+		return nil, err
+	}
+	return &monitors[0], nil
 }
 
 // SaveHeartBeat 保存心跳记录
@@ -99,6 +200,31 @@ func GetStats() (*models.Stats, error) {
 	// 异常监控数（包括离线和重试中）
 	DB.Model(&models.Monitor{}).Where("status IN ?", []int{0, 2}).Count(&stats.DownMonitors)
 
+	// 受依赖影响的监控数
+	statuses, err := monitorStatuses()
+	if err != nil {
+		return nil, err
+	}
+	impact, err := dependencyImpact(statuses)
+	if err != nil {
+		return nil, err
+	}
+	stats.ImpactedMonitors = int64(len(impact))
+
+	// 响应缓慢和状态频繁切换的监控数
+	monitors, err := GetAllMonitors()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.EffectiveStatus == models.StatusDegraded {
+			stats.DegradedMonitors++
+		}
+		if monitor.Flapping {
+			stats.FlappingMonitors++
+		}
+	}
+
 	// 平均可用率
 	var avgUptime float64
 	DB.Model(&models.Monitor{}).Select("AVG(uptime)").Scan(&avgUptime)
@@ -113,12 +239,14 @@ func GetStats() (*models.Stats, error) {
 }
 
 // CleanOldHeartBeats 清理旧的心跳记录
+// 已归档的监控项同样按保留期清理,归档保留的是监控项本身、故障事件和事件记录,恢复时只能找回保留期内的心跳
 func CleanOldHeartBeats(days int) error {
 	threshold := time.Now().AddDate(0, 0, -days)
 	return DB.Where("created_at < ?", threshold).Delete(&models.HeartBeat{}).Error
 }
 
-// DeleteMonitor 删除监控项及其相关的心跳记录
+// DeleteMonitor 删除监控项及其相关的心跳记录、标签、故障事件、事件记录和通知发送记录
+// 同时移除依赖关系和组合监控项中对它的引用
 func DeleteMonitor(id int) error {
 	// 开启事务
 	tx := DB.Begin()
@@ -129,8 +257,83 @@ func DeleteMonitor(id int) error {
 		return err
 	}
 
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.MonitorTag{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ? OR depends_on_id = ?", id, id).Delete(&models.MonitorDependency{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.MonitorOverride{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.LatencyThreshold{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.LatencyAnomaly{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.FlapPeriod{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.MaintenancePeriod{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("slo_id IN (?)", tx.Model(&models.SLO{}).Select("id").Where("monitor_id = ?", id)).Delete(&models.SLOAlert{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.SLO{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.NotificationDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.Incident{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.Event{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.MonitorChange{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.StatusUpdate{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("monitor_id = ?", id).Delete(&models.CompositeMember{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 再删除监控项
-	if err := tx.Where("id = ?", id).Delete(&models.Monitor{}).Error; err != nil {
+	if err := tx.Unscoped().Where("id = ?", id).Delete(&models.Monitor{}).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -138,55 +341,135 @@ func DeleteMonitor(id int) error {
 	return tx.Commit().Error
 }
 
-// SyncMonitors 同步监控项列表，删除不在新列表中的监控项
-// 为避免误删除，只有在新列表数量达到一定阈值时才执行删除操作
-func SyncMonitors(currentMonitorIDs []int) error {
-	if len(currentMonitorIDs) == 0 {
-		// 如果当前没有监控项，不执行删除操作（可能是获取数据失败）
-		log.Println("警告: 获取到的监控项数量为0，跳过同步删除操作")
+// SyncMonitors 同步监控项列表，归档不在新列表中的监控项，并记录新增和移除事件
+// 为避免误归档，只有在新列表数量达到一定阈值时才执行归档操作,未通过检查时也不记录新增事件
+// 归档和事件在同一个事务中写入,中途失败时不会留下只归档了一部分的状态
+func SyncMonitors(current []models.Monitor) error {
+	if len(current) == 0 {
+		// 如果当前没有监控项，不执行归档操作（可能是获取数据失败）
+		log.Println("警告: 获取到的监控项数量为0，跳过同步归档操作")
 		return nil
 	}
 
-	// 获取数据库中所有的监控项
-	var existingMonitors []models.Monitor
-	if err := DB.Find(&existingMonitors).Error; err != nil {
+	// 获取数据库中所有来自 Kuma 的监控项,包括已归档的
+	var knownMonitors []models.Monitor
+	if err := DB.Unscoped().Where("source = ?", models.SourceKuma).Find(&knownMonitors).Error; err != nil {
 		return err
 	}
+	known := make(map[int]bool, len(knownMonitors))
+	var existingMonitors []models.Monitor
+	for _, monitor := range knownMonitors {
+		known[monitor.ID] = true
+		if !monitor.RemovedAt.Valid {
+			existingMonitors = append(existingMonitors, monitor)
+		}
+	}
 
 	// 安全检查：如果数据库中有监控项，但获取到的数量显著少于现有数量
-	// 则认为可能是数据获取异常，不执行删除操作
+	// 则认为可能是数据获取异常，不执行归档操作
 	if len(existingMonitors) > 0 {
 		// 如果新获取的监控项数量少于现有数量的50%，认为异常
-		if len(currentMonitorIDs) < len(existingMonitors)/2 {
-			log.Printf("警告: 获取到的监控项数量(%d)显著少于现有数量(%d)，可能是Kuma服务异常，跳过同步删除操作",
-				len(currentMonitorIDs), len(existingMonitors))
+		if len(current) < len(existingMonitors)/2 {
+			log.Printf("警告: 获取到的监控项数量(%d)显著少于现有数量(%d)，可能是Kuma服务异常，跳过同步归档操作",
+				len(current), len(existingMonitors))
 			return nil
 		}
 	}
 
 	// 创建当前监控项ID的map，便于快速查找
 	currentIDMap := make(map[int]bool)
-	for _, id := range currentMonitorIDs {
-		currentIDMap[id] = true
-	}
-
-	// 找出需要删除的监控项
-	deletedCount := 0
-	for _, monitor := range existingMonitors {
-		if !currentIDMap[monitor.ID] {
-			// 这个监控项在Kuma中已不存在，需要删除
-			log.Printf("检测到监控项已从Kuma删除: [%s] (ID: %d)", monitor.Name, monitor.ID)
-			if err := DeleteMonitor(monitor.ID); err != nil {
-				log.Printf("删除监控项失败 [%s]: %v", monitor.Name, err)
+	for _, monitor := range current {
+		currentIDMap[monitor.ID] = true
+	}
+
+	archivedCount := 0
+	now := time.Now()
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		// 记录新出现的监控项,首次导入时不记录
+		if len(knownMonitors) > 0 {
+			for _, monitor := range current {
+				if known[monitor.ID] {
+					continue
+				}
+				if err := createEvent(tx, &models.Event{
+					Kind:      models.EventMonitorAdded,
+					MonitorID: monitor.ID,
+					Title:     fmt.Sprintf("%s 已添加到状态页", monitor.Name),
+					Message:   "分组: " + monitor.Group,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		// 找出需要归档的监控项,保留心跳历史,重新出现时自动恢复
+		for _, monitor := range existingMonitors {
+			if currentIDMap[monitor.ID] {
+				continue
+			}
+			log.Printf("检测到监控项已从Kuma移除,归档: [%s] (ID: %d)", monitor.Name, monitor.ID)
+			if err := tx.Delete(&models.Monitor{}, monitor.ID).Error; err != nil {
+				log.Printf("归档监控项失败 [%s]: %v", monitor.Name, err)
+				return err
+			}
+			if err := closeArchivedMonitor(tx, monitor.ID, now); err != nil {
 				return err
 			}
-			deletedCount++
+			if err := createEvent(tx, &models.Event{
+				Kind:      models.EventMonitorRemoved,
+				MonitorID: monitor.ID,
+				Title:     fmt.Sprintf("%s 已从状态页移除", monitor.Name),
+				Message:   "分组: " + monitor.Group,
+			}); err != nil {
+				return err
+			}
+			archivedCount++
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if deletedCount > 0 {
-		log.Printf("同步删除完成: 删除了 %d 个监控项", deletedCount)
+	if archivedCount > 0 {
+		log.Printf("同步归档完成: 归档了 %d 个监控项", archivedCount)
 	}
 
 	return nil
 }
+
+// closeArchivedMonitor 解决归档监控项未解决的故障事件,结束其维护和抖动期间
+// 归档后不会再有新的状态,未结束的记录会让重复提醒和升级一直发送;移除已由 monitor_removed 事件记录,不再另外通知
+func closeArchivedMonitor(db *gorm.DB, monitorID int, now time.Time) error {
+	if err := db.Model(&models.Incident{}).
+		Where("monitor_id = ? AND status <> ?", monitorID, models.IncidentResolved).
+		Updates(map[string]interface{}{"status": models.IncidentResolved, "resolved_at": now}).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.MaintenancePeriod{}).
+		Where("monitor_id = ? AND ended_at IS NULL", monitorID).
+		Update("ended_at", now).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.FlapPeriod{}).
+		Where("monitor_id = ? AND ended_at IS NULL", monitorID).
+		Update("ended_at", now).Error
+}
+
+// PurgeArchivedMonitors 彻底删除归档超过 graceDays 天的监控项及其历史
+func PurgeArchivedMonitors(graceDays int) (int, error) {
+	threshold := time.Now().AddDate(0, 0, -graceDays)
+
+	var monitors []models.Monitor
+	if err := DB.Unscoped().Where("removed_at IS NOT NULL AND removed_at < ?", threshold).Find(&monitors).Error; err != nil {
+		return 0, err
+	}
+
+	for _, monitor := range monitors {
+		if err := DeleteMonitor(monitor.ID); err != nil {
+			return 0, err
+		}
+		log.Printf("归档监控项已超过 %d 天,彻底删除: [%s] (ID: %d)", graceDays, monitor.Name, monitor.ID)
+	}
+	return len(monitors), nil
+}
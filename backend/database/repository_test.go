@@ -0,0 +1,165 @@
+package database
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupTestDB 使用临时数据库和默认配置
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		DataRetentionDays:     30,
+		LatencyBaselineWindow: 24 * time.Hour,
+	}
+	if err := InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	t.Cleanup(func() { CloseDB() })
+}
+
+// saveMonitors 保存 ID 为 1..n 的监控项并返回
+func saveMonitors(t *testing.T, n int) []models.Monitor {
+	t.Helper()
+	monitors := make([]models.Monitor, n)
+	for i := range monitors {
+		monitors[i] = models.Monitor{ID: i + 1, Name: "监控项" + string(rune('A'+i)), Group: "应用", Status: models.StatusUp}
+		if err := SaveMonitor(&monitors[i]); err != nil {
+			t.Fatalf("保存监控项失败: %v", err)
+		}
+	}
+	return monitors
+}
+
+// TestSyncMonitorsArchiveClosesOpenRecords 归档时解决故障事件,结束维护和抖动期间
+func TestSyncMonitorsArchiveClosesOpenRecords(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 4)
+
+	started := time.Now().Add(-time.Hour)
+	incident := &models.Incident{MonitorID: 1, Title: "监控项A 离线", Status: models.IncidentInvestigating, Impact: "major", StartedAt: started}
+	maintenance := &models.MaintenancePeriod{MonitorID: 1, StartedAt: started}
+	flap := &models.FlapPeriod{MonitorID: 1, Changes: 6, StartedAt: started}
+	for _, record := range []interface{}{incident, maintenance, flap} {
+		if err := DB.Create(record).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := SyncMonitors(monitors[1:]); err != nil {
+		t.Fatalf("同步监控项失败: %v", err)
+	}
+
+	archived, err := GetMonitorByID(1)
+	if err != nil || !archived.RemovedAt.Valid {
+		t.Fatalf("监控项应已归档: %+v, %v", archived, err)
+	}
+	if open, err := GetUnresolvedIncidents(); err != nil || len(open) != 0 {
+		t.Errorf("归档后不应有未解决的故障事件: %+v, %v", open, err)
+	}
+	if resolved, _ := GetIncident(incident.ID); resolved.ResolvedAt == nil {
+		t.Error("故障事件应记录解决时间")
+	}
+	if err := DB.First(maintenance, maintenance.ID).Error; err != nil || maintenance.EndedAt == nil {
+		t.Errorf("维护期间应已结束: %+v, %v", maintenance, err)
+	}
+	if open, err := GetOpenFlaps(); err != nil || len(open) != 0 {
+		t.Errorf("抖动期间应已结束: %+v, %v", open, err)
+	}
+
+	events, err := GetEvents(EventQuery{Kinds: []string{models.EventMonitorRemoved}, Limit: 10})
+	if err != nil || len(events) != 1 || events[0].MonitorID != 1 {
+		t.Errorf("应记录一条移除事件: %+v, %v", events, err)
+	}
+}
+
+// TestSyncMonitorsSafetyCheck 获取到的监控项显著减少时既不归档也不记录新增事件
+func TestSyncMonitorsSafetyCheck(t *testing.T) {
+	setupTestDB(t)
+	saveMonitors(t, 4)
+
+	added := models.Monitor{ID: 10, Name: "新监控项", Group: "应用", Status: models.StatusUp}
+	if err := SyncMonitors([]models.Monitor{added}); err != nil {
+		t.Fatalf("同步监控项失败: %v", err)
+	}
+
+	monitors, err := GetAllMonitors()
+	if err != nil || len(monitors) != 4 {
+		t.Errorf("未通过安全检查时不应归档: %d, %v", len(monitors), err)
+	}
+	events, err := GetEvents(EventQuery{Limit: 10})
+	if err != nil || len(events) != 0 {
+		t.Errorf("未通过安全检查时不应记录事件: %+v, %v", events, err)
+	}
+}
+
+// TestSyncMonitorsRollback 记录事件失败时归档整体回滚
+func TestSyncMonitorsRollback(t *testing.T) {
+	setupTestDB(t)
+	monitors := saveMonitors(t, 4)
+	if err := DB.Migrator().DropTable(&models.Event{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SyncMonitors(monitors[2:]); err == nil {
+		t.Fatal("记录事件失败时应返回错误")
+	}
+	current, err := GetAllMonitors()
+	if err != nil || len(current) != 4 {
+		t.Errorf("失败时不应归档任何监控项: %d, %v", len(current), err)
+	}
+}
+
+// TestPurgeArchivedMonitorsRemovesHistory 彻底删除归档监控项时一并删除它的所有记录,其他监控项的记录保留
+func TestPurgeArchivedMonitorsRemovesHistory(t *testing.T) {
+	setupTestDB(t)
+	saveMonitors(t, 2)
+
+	now := time.Now()
+	for _, id := range []int{1, 2} {
+		records := []interface{}{
+			&models.HeartBeat{MonitorID: id, Status: models.StatusUp, CreatedAt: now},
+			&models.Incident{MonitorID: id, Title: "离线", Status: models.IncidentResolved, Impact: "major", StartedAt: now},
+			&models.Event{Kind: models.EventMonitorRemoved, MonitorID: id, CreatedAt: now},
+			&models.MonitorChange{MonitorID: id, Field: "name", ChangedAt: now},
+			&models.NotificationDelivery{ChannelID: 1, MonitorID: id, Kind: "opened", Status: models.DeliverySent},
+			&models.StatusUpdate{Kind: "incident", MonitorID: id, CreatedAt: now},
+			&models.CompositeMember{CompositeID: 100, MonitorID: id},
+		}
+		for _, record := range records {
+			if err := DB.Create(record).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := DB.Model(&models.Monitor{}).Where("id = ?", 1).Update("removed_at", now.AddDate(0, 0, -100)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := PurgeArchivedMonitors(90)
+	if err != nil || purged != 1 {
+		t.Fatalf("应彻底删除 1 个监控项: %d, %v", purged, err)
+	}
+
+	for _, model := range []interface{}{
+		&models.Monitor{}, &models.HeartBeat{}, &models.Incident{}, &models.Event{}, &models.MonitorChange{},
+		&models.NotificationDelivery{}, &models.StatusUpdate{}, &models.CompositeMember{},
+	} {
+		column := "monitor_id"
+		if _, ok := model.(*models.Monitor); ok {
+			column = "id"
+		}
+		var counts [2]int64
+		for i, id := range []int{1, 2} {
+			if err := DB.Unscoped().Model(model).Where(column+" = ?", id).Count(&counts[i]).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		if counts[0] != 0 || counts[1] != 1 {
+			t.Errorf("%T: 归档监控项应剩 0 条,其他监控项应剩 1 条,实际 %d, %d", model, counts[0], counts[1])
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package database
+
+import (
+	"fmt"
+	"kuma-lite/backend/models"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetSLOs 获取所有 SLO 定义
+func GetSLOs() ([]models.SLO, error) {
+	var slos []models.SLO
+	err := DB.Order("id ASC").Find(&slos).Error
+	return slos, err
+}
+
+// GetSLO 获取单个 SLO 定义
+func GetSLO(id int) (*models.SLO, error) {
+	var slo models.SLO
+	if err := DB.Where("id = ?", id).First(&slo).Error; err != nil {
+		return nil, err
+	}
+	return &slo, nil
+}
+
+// SaveSLO 创建或更新 SLO,ID 为 0 时新建
+func SaveSLO(slo *models.SLO) error {
+	return DB.Save(slo).Error
+}
+
+// DeleteSLO 删除 SLO 及其告警记录
+func DeleteSLO(id int) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slo_id = ?", id).Delete(&models.SLOAlert{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.SLO{}).Error
+	})
+}
+
+// GetSLOReports 计算所有 SLO 的当前结果
+func GetSLOReports(now time.Time) ([]models.SLOReport, error) {
+	slos, err := GetSLOs()
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := GetOpenSLOAlerts()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]models.SLOReport, 0, len(slos))
+	for _, slo := range slos {
+		report, err := computeSLOReport(slo, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, alert := range alerts {
+			if alert.SLOID == slo.ID {
+				report.Alerts = append(report.Alerts, alert)
+			}
+		}
+		reports = append(reports, *report)
+	}
+	return reports, nil
+}
+
+// GetSLOReport 计算单个 SLO 的当前结果
+func GetSLOReport(id int, now time.Time) (*models.SLOReport, error) {
+	slo, err := GetSLO(id)
+	if err != nil {
+		return nil, err
+	}
+	report, err := computeSLOReport(*slo, now)
+	if err != nil {
+		return nil, err
+	}
+	err = DB.Where("slo_id = ? AND ended_at IS NULL", id).Order("started_at ASC").Find(&report.Alerts).Error
+	return report, err
+}
+
+// sloWindows 需要计算燃烧率的窗口,由告警规则的长短窗口去重并从短到长排列
+func sloWindows() []time.Duration {
+	var windows []time.Duration
+	seen := make(map[time.Duration]bool)
+	for _, rule := range models.SLOBurnRateRules {
+		for _, window := range []time.Duration{rule.Short, rule.Long} {
+			if !seen[window] {
+				seen[window] = true
+				windows = append(windows, window)
+			}
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	return windows
+}
+
+// computeSLOReport 根据心跳统计 SLO 窗口和各燃烧率窗口的好、坏事件数
+// 所有窗口在一次查询中按时间分段求和;维护中的心跳不计入
+func computeSLOReport(slo models.SLO, now time.Time) (*models.SLOReport, error) {
+	report := &models.SLOReport{SLO: slo, SLI: -1, ErrorBudgetRemaining: 1, BurnRates: []models.SLOBurnRate{}, Alerts: []models.SLOAlert{}}
+
+	ids, err := sloMonitorIDs(&slo)
+	if err != nil || len(ids) == 0 {
+		return report, err
+	}
+
+	bad := "status = ?"
+	badArgs := []interface{}{models.StatusDown}
+	if slo.LatencyMs > 0 {
+		bad = "(status = ? OR (status = ? AND response_time > ?))"
+		badArgs = append(badArgs, models.StatusUp, slo.LatencyMs)
+	}
+
+	windows := append([]time.Duration{time.Duration(slo.WindowDays) * 24 * time.Hour}, sloWindows()...)
+	earliest := now
+	var columns []string
+	var args []interface{}
+	for _, window := range windows {
+		since := now.Add(-window)
+		if since.Before(earliest) {
+			earliest = since
+		}
+		columns = append(columns,
+			"COALESCE(SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN created_at >= ? AND "+bad+" THEN 1 ELSE 0 END), 0)")
+		args = append(args, since, since)
+		args = append(args, badArgs...)
+	}
+
+	counts := make([]int64, len(columns))
+	dest := make([]interface{}, len(counts))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	err = DB.Model(&models.HeartBeat{}).
+		Select(strings.Join(columns, ", "), args...).
+		Where("monitor_id IN ? AND created_at >= ? AND created_at <= ? AND status <> ?", ids, earliest, now, models.StatusMaintenance).
+		Row().Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := 1 - slo.Target/100
+	report.TotalEvents = counts[0]
+	report.GoodEvents = counts[0] - counts[1]
+	if report.TotalEvents > 0 {
+		badRatio := float64(counts[1]) / float64(report.TotalEvents)
+		report.SLI = (1 - badRatio) * 100
+		report.ErrorBudgetRemaining = 1 - badRatio/budget
+	}
+	for i, window := range windows[1:] {
+		total, badCount := counts[2*(i+1)], counts[2*(i+1)+1]
+		rate := 0.0
+		if total > 0 {
+			rate = float64(badCount) / float64(total) / budget
+		}
+		report.BurnRates = append(report.BurnRates, models.SLOBurnRate{
+			Window: models.FormatSLOWindow(window),
+			Total:  total,
+			Rate:   rate,
+		})
+	}
+	return report, nil
+}
+
+// sloMonitorIDs SLO 覆盖的未归档监控项 ID,分组按应用展示覆盖后的分组匹配
+func sloMonitorIDs(slo *models.SLO) ([]int, error) {
+	var ids []int
+	if slo.MonitorID != 0 {
+		err := DB.Model(&models.Monitor{}).Where("id = ?", slo.MonitorID).Pluck("id", &ids).Error
+		return ids, err
+	}
+	err := presentedJoins(DB.Model(&models.Monitor{})).
+		Where(presentedGroupExpr+" = ?", slo.GroupName).
+		Pluck("monitors.id", &ids).Error
+	return ids, err
+}
+
+// SLOBurnRate 从结果中取出指定窗口的燃烧率
+func SLOBurnRate(report *models.SLOReport, window time.Duration) float64 {
+	name := models.FormatSLOWindow(window)
+	for _, rate := range report.BurnRates {
+		if rate.Window == name {
+			return rate.Rate
+		}
+	}
+	return 0
+}
+
+// GetOpenSLOAlerts 获取正在触发的燃烧率告警
+func GetOpenSLOAlerts() ([]models.SLOAlert, error) {
+	var alerts []models.SLOAlert
+	err := DB.Where("ended_at IS NULL").Order("started_at ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+// GetSLOAlert 根据 ID 获取燃烧率告警
+func GetSLOAlert(id int) (*models.SLOAlert, error) {
+	var alert models.SLOAlert
+	if err := DB.Where("id = ?", id).First(&alert).Error; err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// OpenSLOAlert 记录新的燃烧率告警,同时记录一条事件
+func OpenSLOAlert(alert *models.SLOAlert, report *models.SLOReport, message string) error {
+	if err := DB.Create(alert).Error; err != nil {
+		return err
+	}
+	return CreateEvent(&models.Event{
+		Kind:      models.EventSLOBurnRate,
+		MonitorID: report.MonitorID,
+		Title:     fmt.Sprintf("SLO %s 错误预算消耗过快", report.Name),
+		Message:   message,
+		CreatedAt: alert.StartedAt,
+	})
+}
+
+// CloseSLOAlert 结束燃烧率告警
+func CloseSLOAlert(id int, endedAt time.Time) error {
+	return DB.Model(&models.SLOAlert{}).Where("id = ?", id).Update("ended_at", endedAt).Error
+}
+
+// CleanOldSLOAlerts 清理已结束的旧燃烧率告警
+func CleanOldSLOAlerts(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("ended_at IS NOT NULL AND ended_at < ?", threshold).Delete(&models.SLOAlert{}).Error
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// GetSubscribers 获取所有邮件订阅者
+func GetSubscribers() ([]models.Subscriber, error) {
+	var subscribers []models.Subscriber
+	err := DB.Order("id ASC").Find(&subscribers).Error
+	return subscribers, err
+}
+
+// GetConfirmedSubscribers 获取已确认的邮件订阅者
+func GetConfirmedSubscribers() ([]models.Subscriber, error) {
+	var subscribers []models.Subscriber
+	err := DB.Where("confirmed = ?", true).Order("id ASC").Find(&subscribers).Error
+	return subscribers, err
+}
+
+// GetSubscriber 获取单个邮件订阅者
+func GetSubscriber(id int) (*models.Subscriber, error) {
+	return findSubscriber("id = ?", id)
+}
+
+// GetSubscriberByEmail 根据邮箱地址(小写)获取订阅者
+func GetSubscriberByEmail(email string) (*models.Subscriber, error) {
+	return findSubscriber("email = ?", email)
+}
+
+// GetSubscriberByConfirmToken 根据确认令牌获取订阅者,空令牌不匹配任何订阅者
+func GetSubscriberByConfirmToken(token string) (*models.Subscriber, error) {
+	return findSubscriber("confirm_token = ? AND confirm_token <> ''", token)
+}
+
+// GetSubscriberByUnsubscribeToken 根据退订令牌获取订阅者,空令牌不匹配任何订阅者
+func GetSubscriberByUnsubscribeToken(token string) (*models.Subscriber, error) {
+	return findSubscriber("unsubscribe_token = ? AND unsubscribe_token <> ''", token)
+}
+
+func findSubscriber(query string, arg interface{}) (*models.Subscriber, error) {
+	var subscriber models.Subscriber
+	if err := DB.Where(query, arg).First(&subscriber).Error; err != nil {
+		return nil, err
+	}
+	return &subscriber, nil
+}
+
+// SaveSubscriber 创建或更新邮件订阅者,ID 为 0 时新建
+func SaveSubscriber(subscriber *models.Subscriber) error {
+	return DB.Save(subscriber).Error
+}
+
+// ConfirmSubscriber 确认订阅,等待确认的分组生效
+// 首次确认时从最新的状态更新之后开始发送,不补发确认前的更新
+func ConfirmSubscriber(subscriber *models.Subscriber, now time.Time) error {
+	if !subscriber.Confirmed {
+		var latest models.StatusUpdate
+		if err := DB.Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+			return err
+		}
+		subscriber.LastUpdateID = latest.ID
+		subscriber.ConfirmedAt = &now
+	}
+	subscriber.Confirmed = true
+	subscriber.Groups = subscriber.PendingGroups
+	subscriber.PendingGroups = []string{}
+	subscriber.ConfirmToken = ""
+	return DB.Save(subscriber).Error
+}
+
+// MarkSubscriberSent 记录已向订阅者发送到 lastUpdateID 为止的状态更新
+func MarkSubscriberSent(id int, lastUpdateID int, sentAt time.Time) error {
+	return DB.Model(&models.Subscriber{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_update_id": lastUpdateID,
+		"last_sent_at":   sentAt,
+	}).Error
+}
+
+// DeleteSubscriber 删除邮件订阅者
+func DeleteSubscriber(id int) error {
+	return DB.Where("id = ?", id).Delete(&models.Subscriber{}).Error
+}
+
+// CleanUnconfirmedSubscribers 删除在 before 之前发送确认邮件但仍未确认的订阅者
+func CleanUnconfirmedSubscribers(before time.Time) error {
+	return DB.Where("confirmed = ? AND confirm_sent_at < ?", false, before).Delete(&models.Subscriber{}).Error
+}
+
+// CreateStatusUpdates 记录发送给订阅者的状态更新
+func CreateStatusUpdates(updates []models.StatusUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return DB.Create(&updates).Error
+}
+
+// GetStatusUpdatesAfter 获取 ID 大于 afterID 的状态更新(按 ID 升序)
+func GetStatusUpdatesAfter(afterID int) ([]models.StatusUpdate, error) {
+	var updates []models.StatusUpdate
+	err := DB.Where("id > ?", afterID).Order("id ASC").Find(&updates).Error
+	return updates, err
+}
+
+// CleanOldStatusUpdates 清理旧的状态更新
+func CleanOldStatusUpdates(days int) error {
+	threshold := time.Now().AddDate(0, 0, -days)
+	return DB.Where("created_at < ?", threshold).Delete(&models.StatusUpdate{}).Error
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// GetUptimeSummary 根据心跳记录统计指定时间之后的可用率
+// 维护中的心跳不计入分母,重试中按非离线计算,与 Kuma 的口径一致
+func GetUptimeSummary(monitorID int, since time.Time) (*models.UptimeSummary, error) {
+	var row struct {
+		Total           int64
+		Down            int64
+		AvgResponseTime float64
+	}
+
+	err := DB.Model(&models.HeartBeat{}).
+		Select("COUNT(*) AS total, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS down, "+
+			"COALESCE(AVG(CASE WHEN status = ? THEN response_time END), 0) AS avg_response_time",
+			models.StatusDown, models.StatusUp).
+		Where("monitor_id = ? AND created_at >= ? AND status <> ?", monitorID, since, models.StatusMaintenance).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.UptimeSummary{
+		MonitorID:       monitorID,
+		Total:           row.Total,
+		Up:              row.Total - row.Down,
+		Down:            row.Down,
+		Uptime:          -1,
+		AvgResponseTime: row.AvgResponseTime,
+	}
+	if row.Total > 0 {
+		summary.Uptime = float64(summary.Up) / float64(row.Total)
+	}
+	return summary, nil
+}
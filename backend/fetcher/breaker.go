@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"errors"
+	"kuma-lite/backend/config"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断期间不请求数据源
+var ErrCircuitOpen = errors.New("熔断器已打开,暂停请求数据源")
+
+// CircuitBreaker 数据源熔断器
+// 连续失败达到阈值后打开,冷却期内拒绝请求;冷却结束后进入半开状态,只放行一次试探请求,
+// 试探请求返回前拒绝其他请求,成功则关闭,失败则重新进入冷却
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	name      string
+	threshold int           // 连续失败多少次后打开,0 表示不熔断
+	cooldown  time.Duration // 打开后的冷却时长
+	failures  int
+	openUntil time.Time
+	probing   bool // 半开状态下已放行试探请求,等待其结果
+}
+
+// BreakerState 熔断器状态
+type BreakerState struct {
+	Open      bool
+	Failures  int
+	OpenUntil time.Time
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{name: name, threshold: threshold, cooldown: cooldown}
+}
+
+// Allow 是否允许发起请求,放行后必须调用 Success 或 Failure 报告结果
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// Success 记录一次成功,关闭熔断器
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold > 0 && b.failures >= b.threshold {
+		log.Printf("数据源 [%s] 已恢复,熔断器关闭", b.name)
+	}
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.probing = false
+}
+
+// Failure 记录一次失败,达到阈值时打开熔断器
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	b.failures++
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		log.Printf("数据源 [%s] 连续失败 %d 次,熔断 %v", b.name, b.failures, b.cooldown)
+	}
+}
+
+// State 返回熔断器当前状态
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BreakerState{
+		Open:      time.Now().Before(b.openUntil),
+		Failures:  b.failures,
+		OpenUntil: b.openUntil,
+	}
+}
+
+var (
+	breakerOnce sync.Once
+	kumaCircuit *CircuitBreaker
+)
+
+// kumaBreaker Kuma 数据源的熔断器
+func kumaBreaker() *CircuitBreaker {
+	breakerOnce.Do(func() {
+		cfg := config.AppConfig
+		kumaCircuit = NewCircuitBreaker("kuma", cfg.BreakerThreshold, cfg.BreakerCooldown)
+	})
+	return kumaCircuit
+}
+
+// KumaBreakerState 返回 Kuma 数据源熔断器的状态
+func KumaBreakerState() BreakerState {
+	return kumaBreaker().State()
+}
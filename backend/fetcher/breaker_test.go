@@ -0,0 +1,60 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker 熔断器按事件序列在关闭、打开和半开状态之间转换
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		steps     string // f 失败,s 成功,e 冷却结束,a/x 期望 Allow 返回 true/false
+	}{
+		{"未达阈值", 3, "ffa"},
+		{"达到阈值后打开", 3, "fffx"},
+		{"成功重置计数", 3, "ffsffa"},
+		{"冷却结束放行一次试探", 3, "fffeax"},
+		{"试探成功后关闭", 3, "fffeasaa"},
+		{"试探失败后重新打开", 3, "fffeafxeax"},
+		{"阈值为 0 不熔断", 0, "ffffffa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker("test", tt.threshold, time.Hour)
+			for i, step := range tt.steps {
+				switch step {
+				case 'f':
+					b.Failure()
+				case 's':
+					b.Success()
+				case 'e':
+					b.mu.Lock()
+					b.openUntil = time.Now().Add(-time.Millisecond)
+					b.mu.Unlock()
+				case 'a', 'x':
+					if got := b.Allow(); got != (step == 'a') {
+						t.Fatalf("第 %d 步 Allow = %v,应为 %v", i, got, step == 'a')
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerState(t *testing.T) {
+	b := NewCircuitBreaker("test", 2, time.Hour)
+	b.Failure()
+	if state := b.State(); state.Open || state.Failures != 1 {
+		t.Fatalf("未达阈值时不应打开: %+v", state)
+	}
+	b.Failure()
+	if state := b.State(); !state.Open || state.Failures != 2 || state.OpenUntil.IsZero() {
+		t.Fatalf("达到阈值后应打开: %+v", state)
+	}
+	b.Success()
+	if state := b.State(); state.Open || state.Failures != 0 {
+		t.Fatalf("成功后应关闭: %+v", state)
+	}
+}
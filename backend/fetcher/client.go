@@ -0,0 +1,172 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kuma-lite/backend/config"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetryBackoff 单次重试等待的上限
+const maxRetryBackoff = 30 * time.Second
+
+var (
+	clientMu     sync.Mutex
+	sharedClient *http.Client
+)
+
+// InitClient 根据配置创建所有 Kuma 请求共用的 HTTP 客户端
+// 证书、代理等配置有误时返回错误,应在启动调度器之前调用
+func InitClient() error {
+	client, err := newHTTPClient(config.AppConfig)
+	if err != nil {
+		return err
+	}
+
+	clientMu.Lock()
+	sharedClient = client
+	clientMu.Unlock()
+	return nil
+}
+
+// httpClient 返回共用的 HTTP 客户端,未初始化时按当前配置创建
+func httpClient() *http.Client {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	if sharedClient == nil {
+		client, err := newHTTPClient(config.AppConfig)
+		if err != nil {
+			log.Printf("创建 HTTP 客户端失败,使用默认配置: %v", err)
+			client = &http.Client{Timeout: config.AppConfig.FetchTimeout}
+		}
+		sharedClient = client
+	}
+	return sharedClient
+}
+
+// statusError 非 200 的 HTTP 响应
+type statusError struct {
+	StatusCode int
+	RetryAfter time.Duration // 服务端通过 Retry-After 要求的等待时间
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP 状态码: %d", e.StatusCode)
+}
+
+// permanentError 不可重试的错误,如构造请求失败、响应读取或解析失败
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// getJSON 请求 url 并将 JSON 响应解析到 out
+// 网络错误、429 和 5xx 按 FETCH_RETRIES 重试,重试间隔为带抖动的指数退避
+func getJSON(url string, out interface{}) error {
+	cfg := config.AppConfig
+	for attempt := 0; ; attempt++ {
+		err := getJSONOnce(url, out)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.FetchRetries || !retryable(err) {
+			return err
+		}
+
+		delay := retryDelay(cfg.FetchRetryBackoff, attempt, err)
+		log.Printf("请求 %s 失败,%v 后重试 (%d/%d): %v", url, delay.Round(time.Millisecond), attempt+1, cfg.FetchRetries, err)
+		time.Sleep(delay)
+	}
+}
+
+// getJSONOnce 发送一次请求
+func getJSONOnce(url string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header.Set("Accept", "application/json")
+	authorize(req, config.AppConfig)
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// 读完响应体以便连接复用
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return &statusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &permanentError{fmt.Errorf("读取响应失败: %w", err)}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &permanentError{fmt.Errorf("解析 JSON 失败: %w", err)}
+	}
+	return nil
+}
+
+// retryable 判断错误是否为可重试的临时错误
+func retryable(err error) bool {
+	var decodeErr *permanentError
+	if errors.As(err, &decodeErr) {
+		return false
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	// 其余为连接、超时等网络错误
+	return true
+}
+
+// retryDelay 计算第 attempt 次重试前的等待时间
+// 取 base*2^attempt 的一半加上随机的另一半,服务端给出 Retry-After 时不少于该值
+func retryDelay(base time.Duration, attempt int, err error) time.Duration {
+	delay := base << attempt
+	if delay <= 0 || delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+		if delay > maxRetryBackoff {
+			delay = maxRetryBackoff
+		}
+	}
+	return delay
+}
+
+// parseRetryAfter 解析 Retry-After 头,支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"网络错误", errors.New("connection refused"), true},
+		{"429", &statusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"500", &statusError{StatusCode: http.StatusInternalServerError}, true},
+		{"503", &statusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"404", &statusError{StatusCode: http.StatusNotFound}, false},
+		{"401", &statusError{StatusCode: http.StatusUnauthorized}, false},
+		{"解析失败", &permanentError{errors.New("invalid character")}, false},
+		{"包装的状态码错误", fmt.Errorf("获取状态页: %w", &statusError{StatusCode: http.StatusBadGateway}), true},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("%s: retryable = %v,应为 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		attempt  int
+		err      error
+		min, max time.Duration
+	}{
+		{"第一次重试", time.Second, 0, errors.New("timeout"), 500 * time.Millisecond, time.Second},
+		{"指数增长", time.Second, 3, errors.New("timeout"), 4 * time.Second, 8 * time.Second},
+		{"不超过上限", time.Second, 10, errors.New("timeout"), maxRetryBackoff / 2, maxRetryBackoff},
+		{"位移溢出时取上限", time.Second, 62, errors.New("timeout"), maxRetryBackoff / 2, maxRetryBackoff},
+		{"Retry-After 更长", time.Second, 0, &statusError{StatusCode: 429, RetryAfter: 10 * time.Second}, 10 * time.Second, 10 * time.Second},
+		{"Retry-After 更短", 4 * time.Second, 0, &statusError{StatusCode: 429, RetryAfter: time.Second}, 2 * time.Second, 4 * time.Second},
+		{"Retry-After 不超过上限", time.Second, 0, &statusError{StatusCode: 503, RetryAfter: time.Hour}, maxRetryBackoff, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		// 等待时间带随机抖动,多次计算都应落在范围内
+		for i := 0; i < 20; i++ {
+			if got := retryDelay(tt.base, tt.attempt, tt.err); got < tt.min || got > tt.max {
+				t.Errorf("%s: retryDelay = %v,应在 %v 到 %v 之间", tt.name, got, tt.min, tt.max)
+				break
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		value    string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"120", 2 * time.Minute, 2 * time.Minute},
+		{"0", 0, 0},
+		{"-5", 0, 0},
+		{"soon", 0, 0},
+		{future, 58 * time.Second, time.Minute},
+		{past, -time.Hour, 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v,应在 %v 到 %v 之间", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
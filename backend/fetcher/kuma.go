@@ -1,20 +1,29 @@
 package fetcher
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"kuma-lite/backend/config"
 	"kuma-lite/backend/models"
 	"log"
-	"net/http"
+	"strconv"
 	"time"
 )
 
 type KumaStatusPage struct {
+	Incident        *KumaIncident `json:"incident"`
 	PublicGroupList []PublicGroup `json:"publicGroupList"`
 }
 
+// KumaIncident 状态页置顶公告
+type KumaIncident struct {
+	ID              int    `json:"id"`
+	Style           string `json:"style"`
+	Title           string `json:"title"`
+	Content         string `json:"content"`
+	CreatedDate     string `json:"createdDate"`
+	LastUpdatedDate string `json:"lastUpdatedDate"`
+}
+
 type KumaHeartBeatResponse struct {
 	HeartbeatList map[string][]KumaHeartBeat `json:"heartbeatList"`
 	UptimeList    map[string]float64         `json:"uptimeList"`
@@ -26,11 +35,23 @@ type PublicGroup struct {
 }
 
 type KumaMonitor struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	URL     string `json:"url"`
-	SendUrl int    `json:"sendUrl"`
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	SendUrl     int       `json:"sendUrl"`
+	Description string    `json:"description"`
+	Tags        []KumaTag `json:"tags"` // 状态页开启"显示标签"时才有
+	// 状态页开启"显示证书到期"时才有,非 HTTPS 监控为空字符串
+	CertExpiryDaysRemaining interface{} `json:"certExpiryDaysRemaining"`
+	ValidCert               *bool       `json:"validCert"`
+}
+
+// KumaTag 监控项标签
+type KumaTag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Color string `json:"color"`
 }
 
 type KumaHeartBeat struct {
@@ -40,78 +61,92 @@ type KumaHeartBeat struct {
 	Ping   float64 `json:"ping"`
 }
 
+// FetchKumaData 获取状态页和心跳数据
+// 状态页请求失败计入熔断器;心跳请求失败时只返回状态页,心跳数据为 nil
 func FetchKumaData() (*KumaStatusPage, *KumaHeartBeatResponse, error) {
 	cfg := config.AppConfig
+	breaker := kumaBreaker()
+	if !breaker.Allow() {
+		return nil, nil, ErrCircuitOpen
+	}
+
 	statusPage, err := fetchStatusPage(cfg.KumaAPIURL, cfg.KumaStatusSlug)
 	if err != nil {
+		breaker.Failure()
 		return nil, nil, err
 	}
+	breaker.Success()
+
 	heartbeatData, err := fetchHeartbeatData(cfg.KumaAPIURL, cfg.KumaStatusSlug)
 	if err != nil {
 		log.Printf("获取心跳数据失败: %v", err)
 		return statusPage, nil, nil
 	}
+	resolveLocation(heartbeatData)
 	return statusPage, heartbeatData, nil
 }
 
 func fetchStatusPage(apiURL, slug string) (*KumaStatusPage, error) {
 	url := fmt.Sprintf("%s/api/status-page/%s", apiURL, slug)
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP 状态码: %d", resp.StatusCode)
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
 	var statusPage KumaStatusPage
-	if err := json.Unmarshal(body, &statusPage); err != nil {
-		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	if err := getJSON(url, &statusPage); err != nil {
+		return nil, fmt.Errorf("请求状态页失败: %w", err)
 	}
 	return &statusPage, nil
 }
 
 func fetchHeartbeatData(apiURL, slug string) (*KumaHeartBeatResponse, error) {
 	url := fmt.Sprintf("%s/api/status-page/heartbeat/%s", apiURL, slug)
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("请求心跳数据失败: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("心跳数据 HTTP 状态码: %d", resp.StatusCode)
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取心跳响应失败: %w", err)
-	}
 	var heartbeatData KumaHeartBeatResponse
-	if err := json.Unmarshal(body, &heartbeatData); err != nil {
-		return nil, fmt.Errorf("解析心跳 JSON 失败: %w", err)
+	if err := getJSON(url, &heartbeatData); err != nil {
+		return nil, fmt.Errorf("请求心跳数据失败: %w", err)
 	}
 	return &heartbeatData, nil
 }
 
+// HasHeartBeats 心跳数据中是否包含该监控项的心跳
+// 没有心跳时监控项的状态未知,不应覆盖已保存的状态
+func HasHeartBeats(monitorID int, heartbeatData *KumaHeartBeatResponse) bool {
+	if heartbeatData == nil {
+		return false
+	}
+	return len(heartbeatData.HeartbeatList[strconv.Itoa(monitorID)]) > 0
+}
+
 func ParseMonitors(statusPage *KumaStatusPage, heartbeatData *KumaHeartBeatResponse) []models.Monitor {
 	var monitors []models.Monitor
 	for groupIndex, group := range statusPage.PublicGroupList {
-		for _, kumaMonitor := range group.MonitorList {
+		for position, kumaMonitor := range group.MonitorList {
 			monitor := models.Monitor{
 				ID:           kumaMonitor.ID,
 				Name:         kumaMonitor.Name,
 				Type:         kumaMonitor.Type,
 				URL:          kumaMonitor.URL,
-				Group:        group.Name, // 保存 Kuma 分组名称
-				GroupOrder:   groupIndex, // 保存分组在原始列表中的顺序
-				Status:       0,
+				Source:       models.SourceKuma,
+				Group:        group.Name,           // 保存 Kuma 分组名称
+				GroupOrder:   groupIndex,           // 保存分组在原始列表中的顺序
+				Position:     position,             // 保存监控项在分组内的顺序
+				Status:       models.StatusPending, // 没有心跳时状态未知
 				Uptime:       0,
 				ResponseTime: 0,
+				Description:  kumaMonitor.Description,
+			}
+			if kumaMonitor.Tags != nil {
+				// 空切片表示 Kuma 返回了标签且为空,nil 表示没有标签信息
+				monitor.Tags = make([]models.MonitorTag, 0, len(kumaMonitor.Tags))
+				for _, tag := range kumaMonitor.Tags {
+					monitor.Tags = append(monitor.Tags, models.MonitorTag{
+						MonitorID: kumaMonitor.ID,
+						Name:      tag.Name,
+						Value:     tag.Value,
+						Color:     tag.Color,
+					})
+				}
+			}
+			if days, ok := kumaMonitor.CertExpiryDaysRemaining.(float64); ok {
+				remaining := int(days)
+				monitor.CertExpiryDays = &remaining
+				monitor.CertValid = kumaMonitor.ValidCert
 			}
 			if heartbeatData != nil {
 				monitorIDStr := fmt.Sprintf("%d", kumaMonitor.ID)
@@ -120,8 +155,7 @@ func ParseMonitors(statusPage *KumaStatusPage, heartbeatData *KumaHeartBeatRespo
 				if uptime, ok := heartbeatData.UptimeList[uptimeKey]; ok {
 					monitor.Uptime = uptime
 				}
-				if heartbeats, ok := heartbeatData.HeartbeatList[monitorIDStr]; ok && len(heartbeats) > 0 {
-					latestHeartBeat := heartbeats[len(heartbeats)-1]
+				if latestHeartBeat, ok := latestHeartBeat(heartbeatData.HeartbeatList[monitorIDStr]); ok {
 					monitor.Status = latestHeartBeat.Status
 					monitor.ResponseTime = int(latestHeartBeat.Ping)
 				}
@@ -149,23 +183,82 @@ func ParseHeartBeats(monitorID int, heartbeatData *KumaHeartBeatResponse) []mode
 			ResponseTime: int(kumaHB.Ping),
 			Message:      kumaHB.Msg,
 		}
-		timeFormats := []string{
-			"2006-01-02 15:04:05.999",
-			"2006-01-02 15:04:05",
-			time.RFC3339,
-		}
-		parsed := false
-		for _, format := range timeFormats {
-			if t, err := time.Parse(format, kumaHB.Time); err == nil {
-				hb.CreatedAt = t
-				parsed = true
-				break
-			}
-		}
-		if !parsed {
-			hb.CreatedAt = time.Now()
+		t, ok := parseKumaTime(kumaHB.Time)
+		if !ok {
+			// 不编造时间: 心跳按 monitorID 和时间去重,使用当前时间会在每次获取时重复写入
+			droppedHeartBeats.Add(1)
+			log.Printf("丢弃时间无法解析的心跳 [监控项 %d]: %q", monitorID, kumaHB.Time)
+			continue
 		}
+		hb.CreatedAt = t
 		heartbeats = append(heartbeats, hb)
 	}
 	return heartbeats
 }
+
+// latestHeartBeat 返回用于监控项当前状态的最新心跳
+// 严格模式下跳过时间无法解析的心跳,与写入历史的心跳保持一致
+func latestHeartBeat(heartbeats []KumaHeartBeat) (KumaHeartBeat, bool) {
+	if len(heartbeats) == 0 {
+		return KumaHeartBeat{}, false
+	}
+	if !config.AppConfig.StrictTimestamps {
+		return heartbeats[len(heartbeats)-1], true
+	}
+	for i := len(heartbeats) - 1; i >= 0; i-- {
+		if _, ok := parseKumaTime(heartbeats[i].Time); ok {
+			return heartbeats[i], true
+		}
+	}
+	return KumaHeartBeat{}, false
+}
+
+// ParseAnnouncement 解析状态页公告,没有公告时返回 nil
+func ParseAnnouncement(statusPage *KumaStatusPage) *models.Announcement {
+	if statusPage == nil || statusPage.Incident == nil {
+		return nil
+	}
+	incident := statusPage.Incident
+	announcement := &models.Announcement{
+		ID:      incident.ID,
+		Title:   incident.Title,
+		Content: incident.Content,
+		Style:   incident.Style,
+		Active:  true,
+	}
+	if t, ok := parseKumaTime(incident.CreatedDate); ok {
+		announcement.CreatedAt = t
+	}
+	if t, ok := parseKumaTime(incident.LastUpdatedDate); ok {
+		announcement.UpdatedAt = t
+	} else {
+		announcement.UpdatedAt = announcement.CreatedAt
+	}
+	return announcement
+}
+
+// parseKumaTime 按 Kuma 的时区解析时间字符串,结果统一为 UTC
+func parseKumaTime(value string) (time.Time, bool) {
+	t, _, ok := parseKumaTimeIn(value, SourceLocation())
+	return t, ok
+}
+
+// kumaTimeFormats Kuma 返回的不带时区的时间格式
+var kumaTimeFormats = []string{
+	"2006-01-02 15:04:05.999",
+	"2006-01-02 15:04:05",
+}
+
+// parseKumaTimeIn 解析时间字符串,不带时区的按 loc 解析
+// zoned 表示字符串本身带有时区信息
+func parseKumaTimeIn(value string, loc *time.Location) (t time.Time, zoned bool, ok bool) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), true, true
+	}
+	for _, format := range kumaTimeFormats {
+		if t, err := time.ParseInLocation(format, value, loc); err == nil {
+			return t.UTC(), false, true
+		}
+	}
+	return time.Time{}, false, false
+}
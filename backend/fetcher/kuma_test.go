@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"kuma-lite/backend/config"
+	"testing"
+	"time"
+)
+
+// TestParseHeartBeatsDropsUnparsableTime 时间无法解析的心跳在两种模式下都不写入历史,并计入丢弃数
+func TestParseHeartBeatsDropsUnparsableTime(t *testing.T) {
+	data := &KumaHeartBeatResponse{HeartbeatList: map[string][]KumaHeartBeat{
+		"1": {
+			{Status: 1, Time: "2026-10-19 08:00:00", Ping: 100},
+			{Status: 0, Time: "invalid"},
+			{Status: 1, Time: "2026-10-19 08:02:00.123", Ping: 120},
+		},
+	}}
+	for _, strict := range []bool{true, false} {
+		config.AppConfig = &config.Config{KumaLocation: time.UTC, StrictTimestamps: strict}
+		before := DroppedHeartBeats()
+		heartbeats := ParseHeartBeats(1, data)
+		if len(heartbeats) != 2 {
+			t.Fatalf("strict=%v: 应保留 2 条心跳,实际 %+v", strict, heartbeats)
+		}
+		want := time.Date(2026, 10, 19, 8, 2, 0, 123e6, time.UTC)
+		if !heartbeats[1].CreatedAt.Equal(want) {
+			t.Errorf("strict=%v: 心跳时间 %v,应为 %v", strict, heartbeats[1].CreatedAt, want)
+		}
+		if dropped := DroppedHeartBeats() - before; dropped != 1 {
+			t.Errorf("strict=%v: 丢弃数增加 %d,应为 1", strict, dropped)
+		}
+	}
+}
+
+func TestLatestHeartBeat(t *testing.T) {
+	valid := KumaHeartBeat{Status: 1, Time: "2026-10-19 08:00:00", Ping: 100}
+	invalid := KumaHeartBeat{Status: 0, Time: "invalid"}
+	tests := []struct {
+		name       string
+		strict     bool
+		heartbeats []KumaHeartBeat
+		want       KumaHeartBeat
+		wantOK     bool
+	}{
+		{"没有心跳", true, nil, KumaHeartBeat{}, false},
+		{"最新心跳有效", true, []KumaHeartBeat{invalid, valid}, valid, true},
+		{"严格模式跳过无效的最新心跳", true, []KumaHeartBeat{valid, invalid}, valid, true},
+		{"严格模式全部无效", true, []KumaHeartBeat{invalid}, KumaHeartBeat{}, false},
+		{"宽松模式使用最新心跳", false, []KumaHeartBeat{valid, invalid}, invalid, true},
+	}
+	for _, tt := range tests {
+		config.AppConfig = &config.Config{KumaLocation: time.UTC, StrictTimestamps: tt.strict}
+		got, ok := latestHeartBeat(tt.heartbeats)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("%s: latestHeartBeat = %+v, %v,应为 %+v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package fetcher
+
+import (
+	"fmt"
+	"kuma-lite/backend/config"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// 自动检测时区的参数: 偏移按 15 分钟取整,取整后的误差不超过 maxOffsetResidual 才采用
+const (
+	offsetStep        = 15 * time.Minute
+	maxOffsetResidual = 2 * time.Minute
+	maxOffset         = 14 * time.Hour
+)
+
+var (
+	// sourceLocation Kuma 返回的不带时区的时间所在的时区
+	sourceLocation atomic.Pointer[time.Location]
+	// droppedHeartBeats 因时间无法解析而丢弃的心跳总数
+	droppedHeartBeats atomic.Int64
+)
+
+// SourceLocation 返回当前用于解析 Kuma 时间的时区
+func SourceLocation() *time.Location {
+	if loc := sourceLocation.Load(); loc != nil {
+		return loc
+	}
+	if loc := config.AppConfig.KumaLocation; loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// DroppedHeartBeats 返回启动以来因时间无法解析而丢弃的心跳数量
+func DroppedHeartBeats() int64 {
+	return droppedHeartBeats.Load()
+}
+
+// resolveLocation 确定本次获取的数据使用的时区
+// 配置了 KUMA_TIMEZONE 时直接使用,否则根据最新心跳时间与当前时间的差值推断
+func resolveLocation(heartbeatData *KumaHeartBeatResponse) {
+	if loc := config.AppConfig.KumaLocation; loc != nil {
+		sourceLocation.Store(loc)
+		return
+	}
+
+	offset, ok := detectOffset(heartbeatData, time.Now())
+	if !ok {
+		// 无法判断时沿用上一次的结果
+		return
+	}
+
+	if current := sourceLocation.Load(); current != nil {
+		if _, currentOffset := time.Now().In(current).Zone(); currentOffset == int(offset.Seconds()) {
+			return
+		}
+	}
+	loc := time.UTC
+	if offset != 0 {
+		loc = time.FixedZone(formatOffset(offset), int(offset.Seconds()))
+	}
+	sourceLocation.Store(loc)
+	log.Printf("警告: 根据最新心跳时间自动推断 Kuma 时区为 %s,如不正确请设置 KUMA_TIMEZONE", loc)
+}
+
+// detectOffset 推断 Kuma 时间相对 UTC 的偏移
+// Kuma 的心跳间隔通常为几十秒,最新一条心跳按 UTC 解析后与当前时间的差值即为时区偏移
+// 心跳间隔在 15 分钟以上时最新心跳本身可能落后一个取整步长,推断结果不可靠,应设置 KUMA_TIMEZONE
+func detectOffset(heartbeatData *KumaHeartBeatResponse, now time.Time) (time.Duration, bool) {
+	if heartbeatData == nil {
+		return 0, false
+	}
+
+	var latest time.Time
+	for _, heartbeats := range heartbeatData.HeartbeatList {
+		for _, hb := range heartbeats {
+			t, zoned, ok := parseKumaTimeIn(hb.Time, time.UTC)
+			if !ok || zoned {
+				// 带时区的时间不需要推断
+				continue
+			}
+			if t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	if latest.IsZero() {
+		return 0, false
+	}
+
+	diff := latest.Sub(now)
+	offset := diff.Round(offsetStep)
+	residual := diff - offset
+	if residual < 0 {
+		residual = -residual
+	}
+	if residual > maxOffsetResidual || offset > maxOffset || offset < -maxOffset {
+		return 0, false
+	}
+	return offset, true
+}
+
+// formatOffset 将偏移格式化为 UTC+08:00 形式
+func formatOffset(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"kuma-lite/backend/config"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// newHTTPClient 根据配置创建访问 Kuma 的 HTTP 客户端
+// 支持代理(HTTP/HTTPS/SOCKS5)、自定义 CA、mTLS 客户端证书和跳过证书校验
+func newHTTPClient(cfg *config.Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 4
+
+	if cfg.KumaProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.KumaProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("解析代理地址失败: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("不支持的代理协议: %s", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Timeout:   cfg.FetchTimeout,
+		Transport: transport,
+	}, nil
+}
+
+// newTLSConfig 创建 TLS 配置
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.KumaCAFile != "" {
+		pem, err := os.ReadFile(cfg.KumaCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		// 在系统证书的基础上追加,而不是替换
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书文件中没有有效的 PEM 证书: %s", cfg.KumaCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.KumaClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.KumaClientCert, cfg.KumaClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.KumaTLSInsecure {
+		log.Println("警告: 已跳过 Kuma 的 TLS 证书校验 (KUMA_TLS_INSECURE)")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
+}
+
+// authorize 为请求添加认证信息和自定义请求头
+func authorize(req *http.Request, cfg *config.Config) {
+	if cfg.KumaBasicUser != "" {
+		req.SetBasicAuth(cfg.KumaBasicUser, cfg.KumaBasicPassword)
+	}
+	if cfg.KumaBearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.KumaBearerToken)
+	}
+	// 自定义请求头最后设置,可覆盖上面的 Authorization
+	for name, value := range cfg.KumaHeaders {
+		req.Header.Set(name, value)
+	}
+}
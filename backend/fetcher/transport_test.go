@@ -0,0 +1,368 @@
+package fetcher
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"kuma-lite/backend/config"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM 将 PEM 块写入临时目录下的文件,返回路径
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// get 使用按 cfg 创建的客户端请求 target
+func get(t *testing.T, cfg *config.Config, target string) (*http.Response, error) {
+	t.Helper()
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	resp, err := client.Get(target)
+	if err == nil {
+		t.Cleanup(func() { resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "ok")
+}
+
+func TestCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer server.Close()
+
+	if _, err := get(t, &config.Config{FetchTimeout: 5 * time.Second}, server.URL); err == nil {
+		t.Fatal("没有配置 CA 时应拒绝自签名证书")
+	} else if !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("应为证书校验错误,实际: %v", err)
+	}
+
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	resp, err := get(t, &config.Config{FetchTimeout: 5 * time.Second, KumaCAFile: caFile}, server.URL)
+	if err != nil {
+		t.Fatalf("配置 CA 后应信任服务端证书: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("状态码 %d", resp.StatusCode)
+	}
+}
+
+func TestInvalidCAFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newHTTPClient(&config.Config{KumaCAFile: path}); err == nil {
+		t.Fatal("没有有效证书的 CA 文件应报错")
+	}
+	if _, err := newHTTPClient(&config.Config{KumaCAFile: path + ".missing"}); err == nil {
+		t.Fatal("不存在的 CA 文件应报错")
+	}
+}
+
+func TestTLSInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer server.Close()
+
+	if _, err := get(t, &config.Config{FetchTimeout: 5 * time.Second, KumaTLSInsecure: true}, server.URL); err != nil {
+		t.Fatalf("KUMA_TLS_INSECURE 时应跳过证书校验: %v", err)
+	}
+}
+
+// newClientCertificate 生成 CA 和由它签发的客户端证书,返回 CA 证书和客户端证书、私钥文件路径
+func newClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "kuma-lite"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, writePEM(t, "client.pem", "CERTIFICATE", clientDER), writePEM(t, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca, certFile, keyFile := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	var commonName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		okHandler(w, r)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writePEM(t, "server-ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	if _, err := get(t, &config.Config{FetchTimeout: 5 * time.Second, KumaCAFile: caFile}, server.URL); err == nil {
+		t.Fatal("没有客户端证书时服务端应拒绝连接")
+	}
+
+	cfg := &config.Config{FetchTimeout: 5 * time.Second, KumaCAFile: caFile, KumaClientCert: certFile, KumaClientKey: keyFile}
+	if _, err := get(t, cfg, server.URL); err != nil {
+		t.Fatalf("带客户端证书的请求失败: %v", err)
+	}
+	if commonName != "kuma-lite" {
+		t.Fatalf("服务端收到的客户端证书 CN 为 %q", commonName)
+	}
+
+	if _, err := newHTTPClient(&config.Config{KumaClientCert: certFile, KumaClientKey: certFile}); err == nil {
+		t.Fatal("私钥无效时应报错")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   *config.Config
+		check func(t *testing.T, r *http.Request)
+	}{
+		{
+			name: "basic",
+			cfg:  &config.Config{KumaBasicUser: "user", KumaBasicPassword: "pass"},
+			check: func(t *testing.T, r *http.Request) {
+				if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+					t.Errorf("Basic 认证为 %q/%q/%v", user, pass, ok)
+				}
+			},
+		},
+		{
+			name: "bearer",
+			cfg:  &config.Config{KumaBearerToken: "token"},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer token" {
+					t.Errorf("Authorization 为 %q", got)
+				}
+			},
+		},
+		{
+			name: "headers",
+			cfg: &config.Config{
+				KumaBearerToken: "token",
+				KumaHeaders:     map[string]string{"CF-Access-Client-Id": "id", "Authorization": "Custom x"},
+			},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("CF-Access-Client-Id"); got != "id" {
+					t.Errorf("CF-Access-Client-Id 为 %q", got)
+				}
+				if got := r.Header.Get("Authorization"); got != "Custom x" {
+					t.Errorf("自定义请求头应覆盖 Authorization,实际 %q", got)
+				}
+			},
+		},
+		{
+			name: "none",
+			cfg:  &config.Config{},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "" {
+					t.Errorf("未配置认证时不应发送 Authorization,实际 %q", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				okHandler(w, r)
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authorize(req, tt.cfg)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			tt.check(t, received)
+		})
+	}
+}
+
+func TestHTTPProxy(t *testing.T) {
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		okHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	resp, err := get(t, &config.Config{FetchTimeout: 5 * time.Second, KumaProxyURL: proxy.URL}, "http://kuma.test/api/status-page/main")
+	if err != nil {
+		t.Fatalf("通过 HTTP 代理请求失败: %v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Fatalf("响应为 %q", body)
+	}
+	if requested != "http://kuma.test/api/status-page/main" {
+		t.Fatalf("代理收到的请求地址为 %q", requested)
+	}
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(okHandler))
+	defer target.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	requested := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// 不论请求哪个地址都转发到 target
+		address, err := socks5Handshake(conn)
+		if err != nil {
+			t.Errorf("SOCKS5 握手失败: %v", err)
+			return
+		}
+		requested <- address
+		upstream, err := net.Dial("tcp", target.Listener.Addr().String())
+		if err != nil {
+			return
+		}
+		defer upstream.Close()
+		go io.Copy(upstream, conn)
+		io.Copy(conn, upstream)
+	}()
+
+	cfg := &config.Config{FetchTimeout: 5 * time.Second, KumaProxyURL: "socks5://" + listener.Addr().String()}
+	resp, err := get(t, cfg, "http://kuma.test:3001/api/status-page/main")
+	if err != nil {
+		t.Fatalf("通过 SOCKS5 代理请求失败: %v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Fatalf("响应为 %q", body)
+	}
+	if address := <-requested; address != "kuma.test:3001" {
+		t.Fatalf("代理收到的目标地址为 %q", address)
+	}
+}
+
+// socks5Handshake 处理无认证的 SOCKS5 CONNECT 请求,返回请求的目标地址
+func socks5Handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return "", err
+		}
+		name := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", errors.New("不支持的地址类型")
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func TestUnsupportedProxy(t *testing.T) {
+	if _, err := newHTTPClient(&config.Config{KumaProxyURL: "ftp://proxy:21"}); err == nil {
+		t.Fatal("不支持的代理协议应报错")
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"kuma-lite/backend/cache"
 	"kuma-lite/backend/config"
 	"kuma-lite/backend/database"
+	"kuma-lite/backend/fetcher"
 	"kuma-lite/backend/scheduler"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+
+	// 内嵌时区数据,运行镜像没有安装 tzdata 时 KUMA_TIMEZONE 和 tz 参数仍可用
+	_ "time/tzdata"
 )
 
 func main() {
@@ -26,8 +30,22 @@ func main() {
 	defer database.CloseDB()
 
 	// 初始化缓存
-	cache.InitCache(cfg.CacheDuration, cfg.CacheDuration*2)
-	log.Println("缓存初始化成功")
+	switch cfg.CacheBackend {
+	case "redis":
+		store, err := cache.NewRedisStore(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("连接 Redis 失败: %v", err)
+		}
+		cache.InitCache(store)
+	default:
+		cache.InitCache(cache.NewMemoryStore(cfg.CacheDuration, cfg.CacheDuration*2))
+	}
+	log.Printf("缓存初始化成功: %s", cfg.CacheBackend)
+
+	// 初始化 Kuma HTTP 客户端
+	if err := fetcher.InitClient(); err != nil {
+		log.Fatalf("初始化 Kuma 客户端失败: %v", err)
+	}
 
 	// 启动调度器
 	scheduler.StartScheduler()
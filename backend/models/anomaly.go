@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LatencyAnomaly 响应时间异常,响应时间明显偏离该监控项同时段的历史水平时记录
+type LatencyAnomaly struct {
+	ID           int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID    int        `gorm:"index;not null" json:"monitorId"`
+	ResponseTime int        `json:"responseTime"` // 异常期间最近心跳响应时间中位数的最大值(毫秒)
+	Baseline     float64    `json:"baseline"`     // 同时段正常心跳响应时间的中位数(毫秒)
+	Score        float64    `json:"score"`        // 异常期间的最大偏离程度(稳健 z 分数)
+	StartedAt    time.Time  `gorm:"index" json:"startedAt"`
+	EndedAt      *time.Time `gorm:"index" json:"endedAt"` // 未结束时为 null
+}
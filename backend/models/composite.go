@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// 组合监控项的判定规则
+const (
+	CompositeAll      = "all"      // 所有成员正常
+	CompositeAny      = "any"      // 任一成员正常
+	CompositeAtLeast  = "at_least" // 至少 Threshold 个成员正常
+	CompositeWeighted = "weighted" // 正常成员的权重占比不低于 Threshold(0-1)
+)
+
+// CompositeIDBase 组合监控项的 ID 从该值开始分配,避免与 Kuma 监控项 ID 冲突
+const CompositeIDBase = 1000000
+
+// CompositeMonitor 组合监控项定义,状态由其他监控项按规则计算
+// 同时在 monitors 表中有 Source 为 composite 的同 ID 记录,每个获取周期写入心跳
+type CompositeMonitor struct {
+	ID          int               `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name        string            `gorm:"size:255;not null" json:"name"`
+	Group       string            `gorm:"size:100" json:"group"`
+	Description string            `gorm:"size:1000" json:"description"`
+	Rule        string            `gorm:"size:20;not null" json:"rule"`
+	Threshold   float64           `json:"threshold"` // at_least 为成员个数,weighted 为权重占比
+	Members     []CompositeMember `gorm:"foreignKey:CompositeID" json:"members"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time         `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// CompositeMember 组合监控项的成员
+type CompositeMember struct {
+	ID          int     `gorm:"primaryKey;autoIncrement" json:"-"`
+	CompositeID int     `gorm:"index;not null" json:"-"`
+	MonitorID   int     `gorm:"not null" json:"monitorId"`
+	Weight      float64 `json:"weight"` // 仅 weighted 规则使用,不大于 0 时按 1 计
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+)
+
+// Kuma 心跳状态
+const (
+	StatusDown        = 0 // 离线
+	StatusUp          = 1 // 正常
+	StatusPending     = 2 // 重试中
+	StatusMaintenance = 3 // 维护中
+
+	// StatusImpacted 离线且依赖的监控项也离线,仅用于 effectiveStatus
+	StatusImpacted = 4
+	// StatusDegraded 正常但响应时间超过阈值,仅用于 effectiveStatus
+	StatusDegraded = 5
+)
+
+// StatusText 返回状态的中文描述
+func StatusText(status int) string {
+	switch status {
+	case StatusUp:
+		return "正常"
+	case StatusPending:
+		return "重试中"
+	case StatusMaintenance:
+		return "维护中"
+	case StatusImpacted:
+		return "受依赖影响"
+	case StatusDegraded:
+		return "响应缓慢"
+	default:
+		return "离线"
+	}
+}
+
+// StatusTransition 状态变化记录(由相邻心跳推导,不单独建表)
+type StatusTransition struct {
+	HeartBeatID  int       `json:"heartbeatId"`
+	MonitorID    int       `json:"monitorId"`
+	FromStatus   int       `json:"fromStatus"`
+	ToStatus     int       `json:"toStatus"`
+	ResponseTime int       `json:"responseTime"`
+	Message      string    `json:"message"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Announcement 状态页公告(来自 Kuma 状态页的 incident)
+type Announcement struct {
+	ID        int       `gorm:"primaryKey" json:"id"` // Kuma incident ID
+	Title     string    `gorm:"size:255" json:"title"`
+	Content   string    `gorm:"type:text" json:"content"`
+	Style     string    `gorm:"size:50" json:"style"` // info, warning, danger, primary, light, dark
+	Active    bool      `gorm:"default:false" json:"active"`
+	CreatedAt time.Time `gorm:"autoCreateTime:false" json:"createdAt"` // Kuma 创建时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime:false" json:"updatedAt"` // Kuma 最后更新时间
+}
+
+// 事件状态(与 Statuspage 的 incident status 取值一致)
+const (
+	IncidentInvestigating = "investigating"
+	IncidentResolved      = "resolved"
+)
+
+// Incident 故障事件,监控项离线时自动创建,恢复后自动解决
+type Incident struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID  int        `gorm:"index;not null" json:"monitorId"`
+	Title      string     `gorm:"size:255" json:"title"`
+	Status     string     `gorm:"size:20;index" json:"status"`     // investigating, resolved
+	Impact     string     `gorm:"size:20" json:"impact"`           // none, minor, major, critical
+	Message    string     `gorm:"size:500" json:"message"`         // 离线时的心跳消息
+	ParentID   int        `gorm:"index" json:"parentId,omitempty"` // 根因故障事件 ID,监控项因依赖离线而离线时设置
+	StartedAt  time.Time  `gorm:"index" json:"startedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// 事件类型
+const (
+	EventMonitorAdded    = "monitor_added"    // 监控项出现在状态页
+	EventMonitorRemoved  = "monitor_removed"  // 监控项从状态页移除(归档)
+	EventMonitorRestored = "monitor_restored" // 归档的监控项重新出现
+	EventMonitorChanged  = "monitor_changed"  // 名称、分组、URL 或类型变化
+	EventLatencyAnomaly  = "latency_anomaly"  // 响应时间偏离历史水平
+	EventSLOBurnRate     = "slo_burn_rate"    // SLO 错误预算燃烧率超过告警阈值
+	EventFlapStarted     = "flap_started"     // 状态开始频繁切换
+	EventFlapEnded       = "flap_ended"       // 状态恢复稳定
+)
+
+// Event 事件记录,用于把故障与配置、拓扑变化对照
+type Event struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind      string    `gorm:"size:30;index" json:"kind"`
+	MonitorID int       `gorm:"index" json:"monitorId"` // 与监控项无关的事件为 0
+	Title     string    `gorm:"size:255" json:"title"`
+	Message   string    `gorm:"size:1000" json:"message"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// MonitorChange 监控项基本信息的变更记录
+type MonitorChange struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID int       `gorm:"index;not null" json:"monitorId"`
+	Field     string    `gorm:"size:30" json:"field"` // name, group, url, type
+	OldValue  string    `gorm:"size:500" json:"oldValue"`
+	NewValue  string    `gorm:"size:500" json:"newValue"`
+	ChangedAt time.Time `gorm:"index" json:"changedAt"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// FlapPeriod 状态抖动期间,监控项在短时间内频繁在正常和离线之间切换时记录
+// 抖动期间不创建或解决故障事件,状态稳定后再按最终状态处理
+type FlapPeriod struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID int        `gorm:"index;not null" json:"monitorId"`
+	Changes   int        `json:"changes"` // 抖动期间滑动窗口内的最大切换次数
+	StartedAt time.Time  `gorm:"index" json:"startedAt"`
+	EndedAt   *time.Time `gorm:"index" json:"endedAt"` // 未结束时为 null
+}
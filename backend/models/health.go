@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// FetchAttempt 一次从 Kuma 获取数据的记录
+type FetchAttempt struct {
+	ID                int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	StartedAt         time.Time `gorm:"index" json:"startedAt"`
+	DurationMs        int64     `json:"durationMs"`
+	Success           bool      `gorm:"index" json:"success"`
+	Error             string    `gorm:"size:500" json:"error,omitempty"`
+	MonitorCount      int       `json:"monitorCount"`
+	Heartbeats        bool      `json:"heartbeats"`        // 是否获取到心跳数据
+	DroppedHeartbeats int       `json:"droppedHeartbeats"` // 因时间无法解析而丢弃的心跳数量
+}
+
+// SourceHealth 数据源(Kuma)的健康状况
+type SourceHealth struct {
+	Source              string         `json:"source"`
+	LastAttempt         *FetchAttempt  `json:"lastAttempt"`
+	LastSuccessAt       *time.Time     `json:"lastSuccessAt"`
+	ConsecutiveFailures int64          `json:"consecutiveFailures"`
+	CircuitOpen         bool           `json:"circuitOpen"`      // 熔断器是否打开
+	CircuitOpenUntil    *time.Time     `json:"circuitOpenUntil"` // 熔断结束时间
+	Stale               bool           `json:"stale"`
+	DataAgeSeconds      float64        `json:"dataAgeSeconds"`        // 距离最近一次成功获取的秒数,从未成功时为 -1
+	StaleThreshold      float64        `json:"staleThresholdSeconds"` // 超过该秒数视为数据过期
+	Timezone            string         `json:"timezone"`              // 解析 Kuma 时间使用的时区
+	DroppedHeartbeats   int64          `json:"droppedHeartbeats"`     // 启动以来因时间无法解析而丢弃的心跳数量
+	RecentAttempts      []FetchAttempt `json:"recentAttempts"`
+}
+
+// HealthStatus 服务健康状态
+type HealthStatus struct {
+	Status        string     `json:"status"` // healthy, starting, stale
+	Message       string     `json:"message"`
+	Stale         bool       `json:"stale"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt"`
+}
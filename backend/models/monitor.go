@@ -2,21 +2,57 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Monitor 监控项模型
 type Monitor struct {
-	ID           int       `gorm:"primaryKey" json:"id"`
-	Name         string    `gorm:"size:255;not null" json:"name"`
-	Type         string    `gorm:"size:50" json:"type"`
-	URL          string    `gorm:"size:500" json:"url"`
-	Group        string    `gorm:"size:100" json:"group"`       // Kuma 分组
-	GroupOrder   int       `gorm:"default:0" json:"groupOrder"` // 分组排序顺序
-	Status       int       `gorm:"default:0" json:"status"`     // 0-异常, 1-正常, 2-维护中
-	Uptime       float64   `json:"uptime"`
-	ResponseTime int       `json:"responseTime"` // 毫秒
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	ID              int            `gorm:"primaryKey" json:"id"`
+	Name            string         `gorm:"size:255;not null" json:"name"`
+	Type            string         `gorm:"size:50" json:"type"`
+	URL             string         `gorm:"size:500" json:"url"`
+	Source          string         `gorm:"size:20;default:kuma;index" json:"source"` // 数据来源
+	Group           string         `gorm:"size:100" json:"group"`                    // Kuma 分组
+	GroupOrder      int            `gorm:"default:0" json:"groupOrder"`              // 分组排序顺序
+	Position        int            `gorm:"default:0" json:"position"`                // 在 Kuma 分组内的顺序
+	SortOrder       int            `gorm:"->;-:migration" json:"sortOrder"`          // 应用覆盖后的分组内顺序,只读
+	Icon            string         `gorm:"->;-:migration" json:"icon,omitempty"`     // 展示图标,来自覆盖配置,只读
+	Link            string         `gorm:"->;-:migration" json:"link,omitempty"`     // 展示链接,来自覆盖配置,只读
+	Status          int            `gorm:"default:0" json:"status"`                  // 0-异常, 1-正常, 2-维护中
+	EffectiveStatus int            `gorm:"-" json:"effectiveStatus"`                 // 考虑依赖和响应时间后的状态,受依赖影响为 4,响应缓慢为 5
+	DegradedReason  string         `gorm:"-" json:"degradedReason,omitempty"`        // 响应缓慢的原因
+	ImpactedBy      []int          `gorm:"-" json:"impactedBy,omitempty"`            // 导致离线的根因监控项 ID
+	Flapping        bool           `gorm:"-" json:"flapping"`                        // 状态正在频繁切换,期间不创建或解决故障事件
+	Uptime          float64        `json:"uptime"`
+	ResponseTime    int            `json:"responseTime"` // 毫秒
+	Description     string         `gorm:"size:1000" json:"description"`
+	CertExpiryDays  *int           `json:"certExpiryDays"` // 证书剩余天数,Kuma 未展示证书信息时为 null
+	CertValid       *bool          `json:"certValid"`      // 证书是否有效,未知时为 null
+	Tags            []MonitorTag   `gorm:"foreignKey:MonitorID" json:"tags"`
+	RemovedAt       gorm.DeletedAt `gorm:"column:removed_at;index" json:"removedAt"` // 从数据源移除的时间,非空表示已归档
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// MonitorTag 监控项标签
+type MonitorTag struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"-"`
+	MonitorID int    `gorm:"index;not null" json:"-"`
+	Name      string `gorm:"size:100;index" json:"name"`
+	Value     string `gorm:"size:255" json:"value"`
+	Color     string `gorm:"size:20" json:"color"`
+}
+
+// CertificateStatus 监控项的证书到期情况
+type CertificateStatus struct {
+	MonitorID     int    `json:"monitorId"`
+	Name          string `json:"name"`
+	Group         string `json:"group"`
+	URL           string `json:"url"`
+	DaysRemaining int    `json:"daysRemaining"`
+	Valid         bool   `json:"valid"`
+	Expiring      bool   `json:"expiring"` // 剩余天数不超过查询的阈值或证书无效
 }
 
 // HeartBeat 心跳记录模型
@@ -31,17 +67,101 @@ type HeartBeat struct {
 
 // Stats 统计信息
 type Stats struct {
-	TotalMonitors   int64   `json:"totalMonitors"`
-	UpMonitors      int64   `json:"upMonitors"`
-	DownMonitors    int64   `json:"downMonitors"`
-	AvgUptime       float64 `json:"avgUptime"`
-	AvgResponseTime float64 `json:"avgResponseTime"`
+	TotalMonitors    int64   `json:"totalMonitors"`
+	UpMonitors       int64   `json:"upMonitors"`
+	DownMonitors     int64   `json:"downMonitors"`
+	ImpactedMonitors int64   `json:"impactedMonitors"` // 因依赖离线而离线的监控数,包含在 downMonitors 中
+	DegradedMonitors int64   `json:"degradedMonitors"` // 响应缓慢的监控数,包含在 upMonitors 中
+	FlappingMonitors int64   `json:"flappingMonitors"` // 状态频繁切换的监控数
+	AvgUptime        float64 `json:"avgUptime"`
+	AvgResponseTime  float64 `json:"avgResponseTime"`
+}
+
+// 监控项数据来源
+const (
+	SourceKuma      = "kuma"
+	SourceComposite = "composite" // kuma-lite 中定义的组合监控项
+)
+
+// Pagination 分页信息
+type Pagination struct {
+	Total      int64  `json:"total"`                // 过滤后的总数
+	Limit      int    `json:"limit"`                // 本页条数上限,0 表示不分页
+	Offset     int    `json:"offset"`               // 本页起始位置
+	NextCursor string `json:"nextCursor,omitempty"` // 下一页游标,没有下一页时为空
 }
 
 // APIResponse API 响应结构
 type APIResponse struct {
 	Success   bool        `json:"success"`
 	Data      interface{} `json:"data,omitempty"`
+	Meta      *Pagination `json:"meta,omitempty"`
 	Error     string      `json:"error,omitempty"`
+	Stale     bool        `json:"stale,omitempty"` // 数据超过 STALE_THRESHOLD 未更新
 	Timestamp time.Time   `json:"timestamp,omitempty"`
 }
+
+// UptimeSummary 某时间窗口内的可用率汇总
+type UptimeSummary struct {
+	MonitorID       int     `json:"monitorId"`
+	Total           int64   `json:"total"`           // 心跳总数(不含维护中)
+	Up              int64   `json:"up"`              // 非离线心跳数
+	Down            int64   `json:"down"`            // 离线心跳数
+	Uptime          float64 `json:"uptime"`          // 0-1,无数据时为 -1
+	AvgResponseTime float64 `json:"avgResponseTime"` // 正常心跳的平均响应时间(毫秒)
+}
+
+// MonitorOverride 监控项的展示覆盖,只影响 kuma-lite 的展示,读取时应用
+// 字符串字段为空、SortOrder 为 nil 时使用 Kuma 的原始值
+type MonitorOverride struct {
+	MonitorID   int       `gorm:"primaryKey;autoIncrement:false" json:"monitorId"`
+	DisplayName string    `gorm:"size:255" json:"displayName"`
+	GroupName   string    `gorm:"size:100" json:"groupName"`
+	SortOrder   *int      `json:"sortOrder"` // 分组内的顺序
+	Icon        string    `gorm:"size:500" json:"icon"`
+	Link        string    `gorm:"size:500" json:"link"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// GroupOverride 分组顺序覆盖
+type GroupOverride struct {
+	GroupName string    `gorm:"primaryKey;size:100" json:"groupName"`
+	SortOrder int       `json:"sortOrder"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// MonitorDependency 监控项依赖关系,MonitorID 依赖 DependsOnID
+type MonitorDependency struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID   int       `gorm:"uniqueIndex:idx_monitor_dependency;not null" json:"monitorId"`
+	DependsOnID int       `gorm:"uniqueIndex:idx_monitor_dependency;index;not null" json:"dependsOnId"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// DependencyNode 依赖图中的监控项
+type DependencyNode struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Group           string `json:"group"`
+	Status          int    `json:"status"`
+	EffectiveStatus int    `json:"effectiveStatus"`
+	ImpactedBy      []int  `json:"impactedBy,omitempty"`
+}
+
+// DependencyGraph 依赖图,只包含有依赖关系的监控项
+type DependencyGraph struct {
+	Nodes []DependencyNode    `json:"nodes"`
+	Edges []MonitorDependency `json:"edges"`
+}
+
+// LatencyThreshold 响应时间阈值,正常的监控项超过阈值时视为响应缓慢
+// MonitorID 不为 0 时作用于单个监控项,否则作用于 GroupName 分组;单个监控项的阈值优先
+type LatencyThreshold struct {
+	ID             int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID      int       `gorm:"index" json:"monitorId,omitempty"`
+	GroupName      string    `gorm:"size:100;index" json:"groupName,omitempty"`
+	ThresholdMs    int       `json:"thresholdMs"`    // 绝对阈值(毫秒),0 表示不使用
+	BaselineFactor float64   `json:"baselineFactor"` // 相对阈值: 超过基线响应时间的倍数,0 表示不使用
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
@@ -0,0 +1,130 @@
+package models
+
+import "time"
+
+// 通知渠道类型
+const (
+	ChannelSlack    = "slack"    // Slack incoming webhook
+	ChannelDiscord  = "discord"  // Discord webhook
+	ChannelTeams    = "teams"    // Microsoft Teams incoming webhook
+	ChannelTelegram = "telegram" // Telegram Bot API
+	ChannelEmail    = "email"    // SMTP 邮件
+)
+
+// 故障事件变化类型
+const (
+	IncidentChangeOpened    = "opened"    // 创建故障事件
+	IncidentChangeEscalated = "escalated" // 响应缓慢升级为离线
+	IncidentChangeResolved  = "resolved"  // 故障事件已解决
+)
+
+// 通知类型,除故障事件变化外的取值
+const (
+	NotificationReminder   = "reminder"   // 故障未解决的重复提醒
+	NotificationEscalation = "escalation" // 故障持续超过规则设置的时长后升级
+	NotificationTest       = "test"       // 测试通知
+
+	NotificationAnomaly         = "anomaly"          // 响应时间异常开始
+	NotificationAnomalyResolved = "anomaly_resolved" // 响应时间异常结束
+	NotificationSLOAlert        = "slo_alert"        // SLO 燃烧率告警开始触发
+	NotificationSLOResolved     = "slo_resolved"     // SLO 燃烧率告警结束
+)
+
+// 通知发送状态
+const (
+	DeliveryPending    = "pending"    // 等待发送,发送失败等待重试时 NextAttemptAt 为重试时间
+	DeliveryDeferred   = "deferred"   // 处于安静时段,到 SendAfter 后发送
+	DeliverySent       = "sent"       // 已发送
+	DeliveryFailed     = "failed"     // 重试次数用完仍发送失败
+	DeliverySuppressed = "suppressed" // 未发送: 处于安静时段且设置为不发送,或故障在延后期间已解决
+)
+
+// 安静时段的处理方式
+const (
+	QuietDefer    = "defer"    // 延后到安静时段结束时发送
+	QuietSuppress = "suppress" // 不发送
+)
+
+// SeverityRank 返回故障事件影响程度的级别,用于按最低级别过滤,未知取值为 0
+func SeverityRank(impact string) int {
+	switch impact {
+	case "minor":
+		return 1
+	case "major":
+		return 2
+	case "critical":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// NotificationChannel 通知渠道,故障事件创建、升级和解决时发送通知
+// 没有被路由规则引用时按自身的过滤条件接收: Groups 和 MonitorIDs 都为空时接收所有监控项,
+// 否则只接收匹配其中之一的监控项;被规则引用时只接收匹配规则的通知,自身的过滤条件不生效
+type NotificationChannel struct {
+	ID          int      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string   `gorm:"size:255;not null" json:"name"`
+	Type        string   `gorm:"size:20;not null" json:"type"`
+	Disabled    bool     `json:"disabled"`
+	WebhookURL  string   `gorm:"size:500" json:"webhookUrl,omitempty"` // slack、discord、teams
+	BotToken    string   `gorm:"size:255" json:"botToken,omitempty"`   // telegram
+	ChatID      string   `gorm:"size:100" json:"chatId,omitempty"`     // telegram
+	EmailTo     []string `gorm:"serializer:json" json:"emailTo,omitempty"`
+	Groups      []string `gorm:"serializer:json" json:"groups"`     // 按展示分组过滤
+	MonitorIDs  []int    `gorm:"serializer:json" json:"monitorIds"` // 按监控项过滤
+	MinSeverity string   `gorm:"size:20" json:"minSeverity"`        // 最低影响程度 minor、major、critical,为空时不过滤
+	Template    string   `gorm:"size:2000" json:"template"`         // 消息正文的 Go 模板,为空时使用默认模板
+
+	// 安静时段,如 22:00 到 07:00,结束时间不晚于开始时间时跨越午夜
+	QuietStart    string    `gorm:"size:5" json:"quietStart"`     // HH:MM,为空时没有安静时段
+	QuietEnd      string    `gorm:"size:5" json:"quietEnd"`       // HH:MM
+	QuietTimezone string    `gorm:"size:64" json:"quietTimezone"` // IANA 时区,为空时使用服务器时区
+	QuietMode     string    `gorm:"size:20" json:"quietMode"`     // defer 或 suppress
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// NotificationRule 通知路由规则,故障事件的监控项和影响程度匹配时发送到规则的渠道
+// 匹配条件为空时不限制,多个条件需要同时满足
+type NotificationRule struct {
+	ID                   int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                 string    `gorm:"size:255;not null" json:"name"`
+	Disabled             bool      `json:"disabled"`
+	MonitorPattern       string    `gorm:"size:255" json:"monitorPattern"` // 监控项名称的通配符,如 "db-*",不区分大小写
+	Group                string    `gorm:"size:100" json:"group"`          // 展示分组
+	Tag                  string    `gorm:"size:100" json:"tag"`            // 标签名,或 "名称:值"
+	MinSeverity          string    `gorm:"size:20" json:"minSeverity"`     // 最低影响程度
+	ChannelIDs           []int     `gorm:"serializer:json" json:"channelIds"`
+	RepeatMinutes        int       `json:"repeatMinutes"`   // 故障未解决时每隔多少分钟重复提醒,0 表示不提醒
+	EscalateMinutes      int       `json:"escalateMinutes"` // 故障持续多少分钟未解决时升级,0 表示不升级
+	EscalationChannelIDs []int     `gorm:"serializer:json" json:"escalationChannelIds"`
+	CreatedAt            time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt            time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// NotificationDelivery 一条通知的发送记录,先记录为待发送再由后台发送,发送历史也来自该表
+type NotificationDelivery struct {
+	ID            int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ChannelID     int        `gorm:"index;not null" json:"channelId"`
+	ChannelName   string     `gorm:"size:255" json:"channelName"`
+	RuleID        int        `gorm:"index" json:"ruleId"`     // 按渠道自身的过滤条件发送时为 0
+	IncidentID    int        `gorm:"index" json:"incidentId"` // 告警和测试通知为 0
+	AlertID       int        `gorm:"index" json:"alertId"`    // 响应时间异常或 SLO 燃烧率告警的 ID,其他通知为 0
+	MonitorID     int        `gorm:"index" json:"monitorId"`
+	Kind          string     `gorm:"size:20" json:"kind"` // opened、escalated、resolved、reminder、escalation、anomaly、anomaly_resolved、slo_alert、slo_resolved、test
+	Title         string     `gorm:"size:255" json:"title"`
+	Status        string     `gorm:"size:20;index" json:"status"`
+	Error         string     `gorm:"size:500" json:"error,omitempty"`
+	SendAfter     *time.Time `json:"sendAfter"`     // 延后发送的时间
+	Attempts      int        `json:"attempts"`      // 已尝试发送的次数
+	NextAttemptAt *time.Time `json:"nextAttemptAt"` // 发送失败后下次重试的时间
+	SentAt        *time.Time `json:"sentAt"`
+	CreatedAt     time.Time  `gorm:"index" json:"createdAt"`
+}
+
+// IncidentChange 一次故障事件变化,由同步故障事件时产生,不单独建表
+type IncidentChange struct {
+	Kind     string
+	Incident Incident
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ReliabilityStats 某时间范围内的可靠性指标,由心跳的状态变化按时间计算
+// 重试中按正常计算,维护中的时间不计入;时长单位均为秒
+type ReliabilityStats struct {
+	Availability  float64    `json:"availability"`           // 非离线时间占比(0-1),无数据时为 -1
+	Uptime        int64      `json:"uptime"`                 // 非离线时长
+	Downtime      int64      `json:"downtime"`               // 离线时长
+	Failures      int        `json:"failures"`               // 范围内开始的故障次数(进入离线的次数)
+	MTTR          float64    `json:"mttr"`                   // 已恢复故障的平均恢复时间,没有时为 0
+	MTBF          float64    `json:"mtbf"`                   // 平均故障间隔: 非离线时长 / 故障次数,没有故障时为 0
+	LongestOutage int64      `json:"longestOutage"`          // 最长一次离线(截取到范围内)
+	LongestAt     *time.Time `json:"longestOutageStartedAt"` // 最长一次离线的开始时间
+	Flaps         int        `json:"flaps"`                  // 正常与离线之间的切换次数
+	FlapsPerDay   float64    `json:"flapsPerDay"`
+}
+
+// MonitorReliability 单个监控项的可靠性指标
+type MonitorReliability struct {
+	MonitorID int    `json:"monitorId"`
+	Name      string `json:"name"`
+	Group     string `json:"group"`
+	ReliabilityStats
+}
+
+// GroupReliability 分组的可靠性指标,由分组内各监控项的时长和次数汇总
+type GroupReliability struct {
+	Group    string `json:"group"`
+	Monitors int    `json:"monitors"`
+	ReliabilityStats
+}
+
+// ReliabilityReport 可靠性报告
+type ReliabilityReport struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Monitors []MonitorReliability `json:"monitors"`
+	Groups   []GroupReliability   `json:"groups"`
+}
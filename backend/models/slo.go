@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SLO 服务水平目标,针对单个监控项或一个分组
+// 好事件为非离线的心跳;设置 LatencyMs 时,响应时间超过该值的正常心跳也计为坏事件
+type SLO struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Description string    `gorm:"size:1000" json:"description"`
+	MonitorID   int       `gorm:"index" json:"monitorId"`     // 与 GroupName 二选一
+	GroupName   string    `gorm:"size:100" json:"groupName"`  // 按应用展示覆盖后的分组匹配
+	Target      float64   `gorm:"not null" json:"target"`     // 目标(百分比),如 99.9
+	WindowDays  int       `gorm:"not null" json:"windowDays"` // 统计窗口(天)
+	LatencyMs   int       `json:"latencyMs"`                  // 0 表示只看可用性
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// SLOBurnRateRule 多窗口燃烧率告警规则,长短两个窗口的燃烧率都超过阈值时触发
+type SLOBurnRateRule struct {
+	Name      string
+	Long      time.Duration
+	Short     time.Duration
+	Threshold float64
+	Severity  string // 告警通知的影响程度
+}
+
+// SLOBurnRateRules 燃烧率告警规则,取值参考 Google SRE Workbook
+var SLOBurnRateRules = []SLOBurnRateRule{
+	{Name: "fast", Long: time.Hour, Short: 5 * time.Minute, Threshold: 14.4, Severity: "critical"},
+	{Name: "medium", Long: 6 * time.Hour, Short: 30 * time.Minute, Threshold: 6, Severity: "major"},
+	{Name: "slow", Long: 72 * time.Hour, Short: 6 * time.Hour, Threshold: 1, Severity: "minor"},
+}
+
+// FindSLOBurnRateRule 根据名称查找燃烧率告警规则,不存在时返回 nil
+func FindSLOBurnRateRule(name string) *SLOBurnRateRule {
+	for i := range SLOBurnRateRules {
+		if SLOBurnRateRules[i].Name == name {
+			return &SLOBurnRateRules[i]
+		}
+	}
+	return nil
+}
+
+// FormatSLOWindow 窗口的简短表示,如 5m、6h、3d
+func FormatSLOWindow(window time.Duration) string {
+	switch {
+	case window%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", window/(24*time.Hour))
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	}
+}
+
+// SLOBurnRate 某个窗口内的错误预算燃烧率,1 表示恰好在统计窗口结束时用完错误预算
+type SLOBurnRate struct {
+	Window string  `json:"window"` // 如 5m、1h、3d
+	Total  int64   `json:"total"`  // 窗口内的事件数
+	Rate   float64 `json:"rate"`
+}
+
+// SLOAlert 燃烧率告警,规则开始满足时创建,不再满足时结束
+type SLOAlert struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SLOID     int        `gorm:"column:slo_id;index;not null" json:"sloId"`
+	Rule      string     `gorm:"size:20" json:"rule"` // fast, medium, slow
+	BurnRate  float64    `json:"burnRate"`            // 触发时长窗口的燃烧率
+	StartedAt time.Time  `gorm:"index" json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt"` // 未结束时为 null
+}
+
+// SLOReport SLO 的当前计算结果
+type SLOReport struct {
+	SLO
+	TotalEvents          int64         `json:"totalEvents"` // 统计窗口内的事件数(不含维护中)
+	GoodEvents           int64         `json:"goodEvents"`
+	SLI                  float64       `json:"sli"`                  // 百分比,无数据时为 -1
+	ErrorBudgetRemaining float64       `json:"errorBudgetRemaining"` // 剩余错误预算占比,超支时为负数
+	BurnRates            []SLOBurnRate `json:"burnRates"`
+	Alerts               []SLOAlert    `json:"alerts"` // 正在触发的告警
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// 状态更新类型,发送给邮件订阅者
+const (
+	UpdateIncidentOpened     = "incident_opened"     // 故障开始
+	UpdateIncidentResolved   = "incident_resolved"   // 故障结束
+	UpdateMaintenanceStarted = "maintenance_started" // 维护开始
+	UpdateMaintenanceEnded   = "maintenance_ended"   // 维护结束
+)
+
+// Subscriber 邮件订阅者,确认后才发送状态更新
+// 再次订阅时新的分组先记入 PendingGroups,确认后生效
+type Subscriber struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email            string     `gorm:"size:255;uniqueIndex;not null" json:"email"` // 小写
+	Groups           []string   `gorm:"serializer:json" json:"groups"`              // 订阅的展示分组,为空时订阅所有分组
+	PendingGroups    []string   `gorm:"serializer:json" json:"pendingGroups"`       // 等待确认的分组
+	Confirmed        bool       `gorm:"index" json:"confirmed"`
+	ConfirmToken     string     `gorm:"size:64;index" json:"-"`
+	UnsubscribeToken string     `gorm:"size:64;uniqueIndex" json:"-"`
+	ConfirmSentAt    time.Time  `json:"confirmSentAt"`
+	ConfirmedAt      *time.Time `json:"confirmedAt"`
+	LastUpdateID     int        `json:"lastUpdateId"` // 已发送的最后一条状态更新 ID
+	LastSentAt       *time.Time `json:"lastSentAt"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// StatusUpdate 发送给订阅者的状态更新,故障事件和维护开始、结束时记录
+type StatusUpdate struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind      string    `gorm:"size:30" json:"kind"`
+	MonitorID int       `gorm:"index" json:"monitorId"`
+	Group     string    `gorm:"size:100" json:"group"` // 记录时的展示分组
+	Title     string    `gorm:"size:255" json:"title"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// MaintenancePeriod 监控项处于维护中的期间,由 Kuma 的维护状态推导
+type MaintenancePeriod struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	MonitorID int        `gorm:"index;not null" json:"monitorId"`
+	StartedAt time.Time  `gorm:"index" json:"startedAt"`
+	EndedAt   *time.Time `gorm:"index" json:"endedAt"` // 未结束时为 null
+}
+
+// SubscriptionRequest 订阅请求,Groups 为空时订阅所有分组
+type SubscriptionRequest struct {
+	Email  string   `json:"email"`
+	Groups []string `json:"groups"`
+}
+
+// SubscriptionToken 确认订阅的请求体
+type SubscriptionToken struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"log"
+	"time"
+)
+
+// anomalySeverity 响应时间异常通知的影响程度,异常时监控项仍然可用
+const anomalySeverity = "minor"
+
+// QueueAnomaly 为响应时间异常的开始(anomaly)或结束(anomaly_resolved)记录待发送的通知,
+// 按影响程度 minor 与故障事件一样路由,由 ProcessNotifications 在后台发送
+func QueueAnomaly(kind string, anomaly *models.LatencyAnomaly, now time.Time) {
+	monitor, err := database.GetMonitorByID(anomaly.MonitorID)
+	if err != nil {
+		log.Printf("获取监控项失败,跳过通知 (异常 ID: %d): %v", anomaly.ID, err)
+		return
+	}
+	title := monitor.Name + " 响应时间异常"
+	if kind == models.NotificationAnomalyResolved {
+		title = monitor.Name + " 响应时间恢复正常"
+	}
+	queueAlert(kind, anomaly.ID, monitor, anomalySeverity, title, now)
+}
+
+// QueueSLOAlert 为燃烧率告警的开始(slo_alert)或结束(slo_resolved)记录待发送的通知,影响程度由告警规则决定
+// 按分组统计的 SLO 以 SLO 名称和分组匹配路由规则和渠道的过滤条件
+func QueueSLOAlert(kind string, alert *models.SLOAlert, slo *models.SLO, now time.Time) {
+	rule := models.FindSLOBurnRateRule(alert.Rule)
+	if rule == nil {
+		return
+	}
+	monitor, err := sloMonitor(slo)
+	if err != nil {
+		log.Printf("获取监控项失败,跳过通知 (SLO 告警 ID: %d): %v", alert.ID, err)
+		return
+	}
+	title := fmt.Sprintf("SLO %s 错误预算消耗过快", slo.Name)
+	if kind == models.NotificationSLOResolved {
+		title = fmt.Sprintf("SLO %s 燃烧率已恢复", slo.Name)
+	}
+	queueAlert(kind, alert.ID, monitor, rule.Severity, title, now)
+}
+
+// sloMonitor 获取 SLO 对应的监控项,按分组统计时返回只有 SLO 名称和分组的监控项
+func sloMonitor(slo *models.SLO) (*models.Monitor, error) {
+	if slo.MonitorID == 0 {
+		return &models.Monitor{Name: slo.Name, Group: slo.GroupName}, nil
+	}
+	return database.GetMonitorByID(slo.MonitorID)
+}
+
+// queueAlert 按路由规则和渠道的过滤条件记录故障事件以外的告警通知
+// 告警没有重复提醒和升级,安静时段的处理与故障事件相同
+func queueAlert(kind string, alertID int, monitor *models.Monitor, severity, title string, now time.Time) {
+	r, err := loadRouting()
+	if err != nil {
+		log.Printf("获取通知渠道失败: %v", err)
+		return
+	}
+
+	var deliveries []models.NotificationDelivery
+	for _, t := range r.targets(monitor, severity) {
+		delivery := models.NotificationDelivery{
+			ChannelID:   t.channel.ID,
+			ChannelName: t.channel.Name,
+			RuleID:      t.ruleID,
+			AlertID:     alertID,
+			MonitorID:   monitor.ID,
+			Kind:        kind,
+			Title:       title,
+			Status:      models.DeliveryPending,
+			CreatedAt:   now,
+		}
+		applyQuietHours(&delivery, t.channel, now)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := database.CreateNotificationDeliveries(deliveries); err != nil {
+		log.Printf("记录通知失败: %v", err)
+	}
+}
+
+// anomalyMessage 根据发送记录和响应时间异常重新生成通知,.Incident 为由异常生成的事件,不对应实际的故障事件
+func anomalyMessage(delivery *models.NotificationDelivery) (*Message, string, error) {
+	anomaly, err := database.GetAnomaly(delivery.AlertID)
+	if err != nil {
+		return nil, "", fmt.Errorf("响应时间异常不存在")
+	}
+	monitor, err := database.GetMonitorByID(delivery.MonitorID)
+	if err != nil {
+		return nil, "", fmt.Errorf("监控项不存在")
+	}
+
+	msg := &Message{
+		Kind:       delivery.Kind,
+		Title:      delivery.Title,
+		Severity:   anomalySeverity,
+		StatusPage: config.AppConfig.StatusPageName,
+		Monitor:    *monitor,
+		Incident: models.Incident{
+			MonitorID: anomaly.MonitorID,
+			Title:     delivery.Title,
+			Impact:    anomalySeverity,
+			Message: fmt.Sprintf("响应时间 %dms,同时段通常为 %.0fms(偏离 %.1f)",
+				anomaly.ResponseTime, anomaly.Baseline, anomaly.Score),
+			StartedAt:  anomaly.StartedAt,
+			ResolvedAt: anomaly.EndedAt,
+		},
+		Status: models.StatusText(monitor.EffectiveStatus),
+		Time:   anomaly.StartedAt,
+	}
+	if delivery.Kind == models.NotificationAnomalyResolved && anomaly.EndedAt != nil {
+		msg.Incident.Message = fmt.Sprintf("异常期间最高 %dms,同时段通常为 %.0fms(偏离 %.1f)",
+			anomaly.ResponseTime, anomaly.Baseline, anomaly.Score)
+		msg.Time = *anomaly.EndedAt
+		msg.Duration = msg.Time.Sub(anomaly.StartedAt)
+	}
+	return msg, "", nil
+}
+
+// sloAlertMessage 根据发送记录和燃烧率告警重新生成通知,.Incident 为由告警生成的事件,不对应实际的故障事件
+func sloAlertMessage(delivery *models.NotificationDelivery) (*Message, string, error) {
+	alert, err := database.GetSLOAlert(delivery.AlertID)
+	if err != nil {
+		return nil, "", fmt.Errorf("SLO 告警不存在")
+	}
+	slo, err := database.GetSLO(alert.SLOID)
+	if err != nil {
+		return nil, "", fmt.Errorf("SLO 不存在")
+	}
+	monitor, err := sloMonitor(slo)
+	if err != nil {
+		return nil, "", fmt.Errorf("监控项不存在")
+	}
+	rule := models.FindSLOBurnRateRule(alert.Rule)
+	if rule == nil {
+		return nil, "", fmt.Errorf("燃烧率告警规则不存在")
+	}
+
+	msg := &Message{
+		Kind:       delivery.Kind,
+		Title:      delivery.Title,
+		Severity:   rule.Severity,
+		StatusPage: config.AppConfig.StatusPageName,
+		Monitor:    *monitor,
+		Incident: models.Incident{
+			MonitorID: slo.MonitorID,
+			Title:     delivery.Title,
+			Impact:    rule.Severity,
+			Message: fmt.Sprintf("%s 规则: %s 燃烧率 %.1f,阈值 %.1f",
+				rule.Name, models.FormatSLOWindow(rule.Long), alert.BurnRate, rule.Threshold),
+			StartedAt:  alert.StartedAt,
+			ResolvedAt: alert.EndedAt,
+		},
+		Time: alert.StartedAt,
+	}
+	if slo.MonitorID != 0 {
+		msg.Status = models.StatusText(monitor.EffectiveStatus)
+	}
+	if delivery.Kind == models.NotificationSLOResolved && alert.EndedAt != nil {
+		msg.Time = *alert.EndedAt
+		msg.Duration = msg.Time.Sub(alert.StartedAt)
+	}
+	return msg, "", nil
+}
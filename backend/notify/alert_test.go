@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestQueueAnomalyRouting 响应时间异常按 minor 路由:规则的最低影响程度和渠道自身的过滤条件都生效
+func TestQueueAnomalyRouting(t *testing.T) {
+	setupTestDB(t)
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	monitor := &models.Monitor{ID: 1, Name: "db-main", Group: "数据库", Status: models.StatusUp}
+	if err := database.SaveMonitor(monitor); err != nil {
+		t.Fatal(err)
+	}
+	channels := []*models.NotificationChannel{
+		{Name: "规则渠道", Type: models.ChannelSlack, WebhookURL: server.URL},
+		{Name: "全部", Type: models.ChannelSlack, WebhookURL: server.URL},
+		{Name: "仅离线", Type: models.ChannelSlack, WebhookURL: server.URL, MinSeverity: "major"},
+	}
+	for _, channel := range channels {
+		if err := database.SaveNotificationChannel(channel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rule := &models.NotificationRule{Name: "数据库", MonitorPattern: "db-*", MinSeverity: "major", ChannelIDs: []int{channels[0].ID}}
+	if err := database.SaveNotificationRule(rule); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	anomaly := &models.LatencyAnomaly{MonitorID: 1, ResponseTime: 900, Baseline: 120, Score: 8.5, StartedAt: now}
+	if err := database.OpenAnomaly(anomaly, monitor.Name); err != nil {
+		t.Fatal(err)
+	}
+	QueueAnomaly(models.NotificationAnomaly, anomaly, now)
+
+	deliveries, err := database.GetNotificationDeliveries(database.NotificationDeliveryQuery{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ChannelID != channels[1].ID {
+		t.Fatalf("只有不限影响程度的渠道应收到通知: %+v", deliveries)
+	}
+	delivery := deliveries[0]
+	if delivery.Kind != models.NotificationAnomaly || delivery.AlertID != anomaly.ID || delivery.IncidentID != 0 ||
+		delivery.Title != "db-main 响应时间异常" {
+		t.Errorf("发送记录不正确: %+v", delivery)
+	}
+
+	deliverDue()
+	if text, _ := receive(t, requests).payload["text"].(string); !strings.Contains(text, "响应时间 900ms") {
+		t.Fatalf("通知正文应包含异常的响应时间: %q", text)
+	}
+
+	// 降低规则的最低影响程度后,结束通知发送到规则的渠道
+	rule.MinSeverity = "minor"
+	if err := database.SaveNotificationRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	endedAt := now.Add(10 * time.Minute)
+	if err := database.CloseAnomaly(anomaly.ID, endedAt); err != nil {
+		t.Fatal(err)
+	}
+	QueueAnomaly(models.NotificationAnomalyResolved, anomaly, endedAt)
+	deliverDue()
+
+	deliveries, err = database.GetNotificationDeliveries(database.NotificationDeliveryQuery{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := make(map[int]string)
+	for _, delivery := range deliveries {
+		if delivery.Kind == models.NotificationAnomalyResolved {
+			sent[delivery.ChannelID] = delivery.Status
+		}
+	}
+	if len(sent) != 2 || sent[channels[0].ID] != models.DeliverySent || sent[channels[1].ID] != models.DeliverySent {
+		t.Errorf("结束通知应发送到规则渠道和不限影响程度的渠道: %+v", sent)
+	}
+	for i := 0; i < 2; i++ {
+		if text, _ := receive(t, requests).payload["text"].(string); !strings.Contains(text, "持续时长: 10 分钟") {
+			t.Errorf("结束通知应包含持续时长: %q", text)
+		}
+	}
+}
+
+// TestQueueSLOAlertSeverity 燃烧率告警按规则的影响程度路由,按分组统计的 SLO 以分组匹配路由规则
+func TestQueueSLOAlertSeverity(t *testing.T) {
+	setupTestDB(t)
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	channel := &models.NotificationChannel{Name: "值班", Type: models.ChannelSlack, WebhookURL: server.URL}
+	if err := database.SaveNotificationChannel(channel); err != nil {
+		t.Fatal(err)
+	}
+	rule := &models.NotificationRule{Name: "数据库", Group: "数据库", MinSeverity: "critical", ChannelIDs: []int{channel.ID}}
+	if err := database.SaveNotificationRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	slo := &models.SLO{Name: "数据库可用性", GroupName: "数据库", Target: 99.9, WindowDays: 30}
+	if err := database.SaveSLO(slo); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	slow := &models.SLOAlert{SLOID: slo.ID, Rule: "slow", BurnRate: 1.5, StartedAt: now}
+	fast := &models.SLOAlert{SLOID: slo.ID, Rule: "fast", BurnRate: 20, StartedAt: now}
+	for _, alert := range []*models.SLOAlert{slow, fast} {
+		if err := database.DB.Create(alert).Error; err != nil {
+			t.Fatal(err)
+		}
+		QueueSLOAlert(models.NotificationSLOAlert, alert, slo, now)
+	}
+
+	deliveries, err := database.GetNotificationDeliveries(database.NotificationDeliveryQuery{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].AlertID != fast.ID || deliveries[0].RuleID != rule.ID ||
+		deliveries[0].MonitorID != 0 || deliveries[0].Title != "SLO 数据库可用性 错误预算消耗过快" {
+		t.Fatalf("只有 fast 规则(critical)的告警应发送到规则的渠道: %+v", deliveries)
+	}
+
+	deliverDue()
+	text, _ := receive(t, requests).payload["text"].(string)
+	if !strings.Contains(text, "监控项: 数据库可用性 (数据库)") || !strings.Contains(text, "fast 规则: 1h 燃烧率 20.0") ||
+		strings.Contains(text, "状态:") {
+		t.Errorf("通知正文不正确: %q", text)
+	}
+}
@@ -0,0 +1,405 @@
+package notify
+
+import (
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"log"
+	"sync"
+	"time"
+)
+
+// deliveryErrorMax 发送记录中错误信息的最大字符数
+const deliveryErrorMax = 500
+
+// 发送失败的重试: 第 n 次失败后等待 deliveryRetryBase*2^(n-1),最多尝试 deliveryMaxAttempts 次
+const (
+	deliveryMaxAttempts = 5
+	deliveryRetryBase   = time.Minute
+)
+
+// deliverMu 保证同一时间只有一个后台发送,通知按记录顺序发送
+var deliverMu sync.Mutex
+
+// QueueIncidentChanges 为故障事件变化记录待发送的通知,由 ProcessNotifications 在后台发送
+// 归入根因事件的故障事件(影响程度为 none)不单独通知,由根因事件的通知覆盖;
+// 解决通知还会发送到已收到升级通知的渠道
+func QueueIncidentChanges(changes []models.IncidentChange, now time.Time) {
+	if len(changes) == 0 {
+		return
+	}
+	r, err := loadRouting()
+	if err != nil {
+		log.Printf("获取通知渠道失败: %v", err)
+		return
+	}
+	if len(r.channels) == 0 {
+		return
+	}
+
+	var deliveries []models.NotificationDelivery
+	for _, change := range changes {
+		incident := change.Incident
+		if models.SeverityRank(incident.Impact) == 0 {
+			continue
+		}
+		monitor, err := database.GetMonitorByID(incident.MonitorID)
+		if err != nil {
+			log.Printf("获取监控项失败,跳过通知 (事件 ID: %d): %v", incident.ID, err)
+			continue
+		}
+
+		targets := r.targets(monitor, incident.Impact)
+		if change.Kind == models.IncidentChangeResolved {
+			escalated, err := escalationTargets(r, incident.ID)
+			if err != nil {
+				log.Printf("获取升级记录失败 (事件 ID: %d): %v", incident.ID, err)
+			}
+			targets = appendTargets(targets, escalated...)
+		}
+
+		title := messageTitle(change.Kind, &incident, monitor.Name, now)
+		for _, t := range targets {
+			deliveries = append(deliveries, newDelivery(t, change.Kind, title, &incident, now))
+		}
+	}
+
+	if err := database.CreateNotificationDeliveries(deliveries); err != nil {
+		log.Printf("记录通知失败: %v", err)
+	}
+}
+
+// ProcessNotifications 记录到期的重复提醒和升级通知,然后在后台发送所有到期的通知
+// 每个获取周期在 QueueIncidentChanges、QueueAnomaly 和 QueueSLOAlert 之后调用
+func ProcessNotifications(now time.Time) {
+	if err := queueFollowUps(now); err != nil {
+		log.Printf("检查通知提醒和升级失败: %v", err)
+	}
+	go deliverDue()
+}
+
+// queueFollowUps 为未解决的故障事件记录重复提醒和升级通知
+// 重复提醒发送到规则的渠道以及已升级的渠道,间隔从该渠道上一条通知算起,该渠道还有延后未发的通知时不再提醒;
+// 升级每个规则只发送一次
+func queueFollowUps(now time.Time) error {
+	r, err := loadRouting()
+	if err != nil {
+		return err
+	}
+	var rules []models.NotificationRule
+	for _, rule := range r.rules {
+		if rule.RepeatMinutes > 0 || rule.EscalateMinutes > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	incidents, err := database.GetUnresolvedIncidents()
+	if err != nil || len(incidents) == 0 {
+		return err
+	}
+	ids := make([]int, len(incidents))
+	for i := range incidents {
+		ids[i] = incidents[i].ID
+	}
+	history, err := database.GetIncidentNotificationDeliveries(ids)
+	if err != nil {
+		return err
+	}
+
+	// 每个故障事件在各渠道最近一条通知的时间、仍在等待发送的渠道,以及已经升级的规则
+	type pair struct{ incident, id int }
+	last := make(map[pair]time.Time)
+	waiting := make(map[pair]bool)
+	escalated := make(map[pair]bool)
+	for _, delivery := range history {
+		if delivery.Kind != models.IncidentChangeResolved {
+			last[pair{delivery.IncidentID, delivery.ChannelID}] = delivery.CreatedAt
+		}
+		if delivery.Status == models.DeliveryPending || delivery.Status == models.DeliveryDeferred {
+			waiting[pair{delivery.IncidentID, delivery.ChannelID}] = true
+		}
+		if delivery.Kind == models.NotificationEscalation {
+			escalated[pair{delivery.IncidentID, delivery.RuleID}] = true
+		}
+	}
+
+	var deliveries []models.NotificationDelivery
+	for i := range incidents {
+		incident := &incidents[i]
+		if models.SeverityRank(incident.Impact) == 0 {
+			continue
+		}
+		monitor, err := database.GetMonitorByID(incident.MonitorID)
+		if err != nil {
+			continue
+		}
+
+		for _, rule := range rules {
+			if !ruleMatches(&rule, monitor, incident.Impact) {
+				continue
+			}
+
+			isEscalated := escalated[pair{incident.ID, rule.ID}]
+			if rule.EscalateMinutes > 0 && !isEscalated &&
+				now.Sub(incident.StartedAt) >= time.Duration(rule.EscalateMinutes)*time.Minute {
+				title := messageTitle(models.NotificationEscalation, incident, monitor.Name, now)
+				for _, id := range rule.EscalationChannelIDs {
+					if channel := r.channel(id); channel != nil {
+						t := target{channel: channel, ruleID: rule.ID}
+						deliveries = append(deliveries, newDelivery(t, models.NotificationEscalation, title, incident, now))
+						last[pair{incident.ID, id}] = now
+					}
+				}
+				escalated[pair{incident.ID, rule.ID}] = true
+				continue
+			}
+
+			if rule.RepeatMinutes <= 0 {
+				continue
+			}
+			channelIDs := rule.ChannelIDs
+			if isEscalated {
+				channelIDs = append(append([]int(nil), channelIDs...), rule.EscalationChannelIDs...)
+			}
+			title := messageTitle(models.NotificationReminder, incident, monitor.Name, now)
+			for _, id := range channelIDs {
+				channel := r.channel(id)
+				previous, notified := last[pair{incident.ID, id}]
+				if channel == nil || !notified || waiting[pair{incident.ID, id}] ||
+					now.Sub(previous) < time.Duration(rule.RepeatMinutes)*time.Minute {
+					continue
+				}
+				t := target{channel: channel, ruleID: rule.ID}
+				deliveries = append(deliveries, newDelivery(t, models.NotificationReminder, title, incident, now))
+				last[pair{incident.ID, id}] = now
+			}
+		}
+	}
+	return database.CreateNotificationDeliveries(deliveries)
+}
+
+// escalationTargets 已收到该故障事件升级通知的渠道
+func escalationTargets(r *routing, incidentID int) ([]target, error) {
+	history, err := database.GetIncidentNotificationDeliveries([]int{incidentID})
+	if err != nil {
+		return nil, err
+	}
+	var targets []target
+	for _, delivery := range history {
+		if delivery.Kind != models.NotificationEscalation || delivery.Status == models.DeliverySuppressed {
+			continue
+		}
+		if channel := r.channel(delivery.ChannelID); channel != nil {
+			targets = appendTargets(targets, target{channel: channel, ruleID: delivery.RuleID})
+		}
+	}
+	return targets, nil
+}
+
+// appendTargets 追加渠道,已有的渠道不重复加入
+func appendTargets(targets []target, more ...target) []target {
+	for _, t := range more {
+		duplicate := false
+		for _, existing := range targets {
+			if existing.channel.ID == t.channel.ID {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// newDelivery 生成一条发送记录,处于渠道安静时段时按渠道设置延后或不发送
+func newDelivery(t target, kind, title string, incident *models.Incident, now time.Time) models.NotificationDelivery {
+	delivery := models.NotificationDelivery{
+		ChannelID:   t.channel.ID,
+		ChannelName: t.channel.Name,
+		RuleID:      t.ruleID,
+		IncidentID:  incident.ID,
+		MonitorID:   incident.MonitorID,
+		Kind:        kind,
+		Title:       title,
+		Status:      models.DeliveryPending,
+		CreatedAt:   now,
+	}
+	applyQuietHours(&delivery, t.channel, now)
+	return delivery
+}
+
+// applyQuietHours 处于渠道安静时段时按渠道设置将发送记录改为延后或不发送
+func applyQuietHours(delivery *models.NotificationDelivery, channel *models.NotificationChannel, now time.Time) {
+	if until, quiet := quietUntil(channel, now); quiet {
+		if channel.QuietMode == models.QuietSuppress {
+			delivery.Status = models.DeliverySuppressed
+			delivery.Error = "处于安静时段"
+		} else {
+			delivery.Status = models.DeliveryDeferred
+			delivery.SendAfter = &until
+		}
+	}
+}
+
+// messageTitle 生成通知标题
+func messageTitle(kind string, incident *models.Incident, monitorName string, now time.Time) string {
+	switch kind {
+	case models.IncidentChangeEscalated:
+		return monitorName + " 由响应缓慢转为离线"
+	case models.IncidentChangeResolved:
+		return monitorName + " 已恢复"
+	case models.NotificationReminder:
+		return incident.Title + ",仍未恢复"
+	case models.NotificationEscalation:
+		return fmt.Sprintf("%s,已持续 %s 未恢复", incident.Title, formatDuration(now.Sub(incident.StartedAt)))
+	default:
+		return incident.Title
+	}
+}
+
+// deliverDue 发送所有到期的通知并记录结果
+func deliverDue() {
+	deliverMu.Lock()
+	defer deliverMu.Unlock()
+
+	deliveries, err := database.GetDueNotificationDeliveries(time.Now())
+	if err != nil {
+		log.Printf("获取待发送通知失败: %v", err)
+		return
+	}
+
+	channels := make(map[int]*models.NotificationChannel)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		channel, ok := channels[delivery.ChannelID]
+		if !ok {
+			channel, _ = database.GetNotificationChannel(delivery.ChannelID)
+			channels[delivery.ChannelID] = channel
+		}
+
+		msg, skip, err := deliveryMessage(delivery)
+		switch {
+		case channel == nil:
+			delivery.Status, delivery.Error = models.DeliveryFailed, "通知渠道不存在"
+		case channel.Disabled:
+			delivery.Status, delivery.Error = models.DeliverySuppressed, "通知渠道已停用"
+		case err != nil:
+			delivery.Status, delivery.Error = models.DeliveryFailed, err.Error()
+		case skip != "":
+			delivery.Status, delivery.Error = models.DeliverySuppressed, skip
+		default:
+			delivery.Attempts++
+			if err := Send(channel, msg); err != nil {
+				delivery.Error = truncate(err.Error(), deliveryErrorMax)
+				if delivery.Attempts < deliveryMaxAttempts {
+					delay := deliveryRetryDelay(delivery.Attempts)
+					next := time.Now().Add(delay)
+					delivery.Status, delivery.NextAttemptAt = models.DeliveryPending, &next
+					log.Printf("发送通知失败 [%s] %s,%v 后重试 (%d/%d): %v",
+						channel.Name, delivery.Title, delay, delivery.Attempts, deliveryMaxAttempts, err)
+				} else {
+					delivery.Status, delivery.NextAttemptAt = models.DeliveryFailed, nil
+					log.Printf("发送通知失败 [%s] %s,已尝试 %d 次: %v", channel.Name, delivery.Title, delivery.Attempts, err)
+				}
+			} else {
+				sentAt := time.Now()
+				delivery.Status, delivery.Error, delivery.NextAttemptAt, delivery.SentAt = models.DeliverySent, "", nil, &sentAt
+			}
+		}
+
+		if err := database.FinishNotificationDelivery(delivery); err != nil {
+			log.Printf("记录通知发送结果失败 (ID: %d): %v", delivery.ID, err)
+		}
+	}
+}
+
+// deliveryRetryDelay 第 attempts 次发送失败后到下次重试的等待时间
+func deliveryRetryDelay(attempts int) time.Duration {
+	return deliveryRetryBase << (attempts - 1)
+}
+
+// deliveryMessage 根据发送记录重新生成通知,监控项取发送时的展示信息
+// 延后的重复提醒和升级在故障已解决时不再发送,返回不发送的原因
+func deliveryMessage(delivery *models.NotificationDelivery) (*Message, string, error) {
+	switch delivery.Kind {
+	case models.NotificationAnomaly, models.NotificationAnomalyResolved:
+		return anomalyMessage(delivery)
+	case models.NotificationSLOAlert, models.NotificationSLOResolved:
+		return sloAlertMessage(delivery)
+	}
+
+	incident, err := database.GetIncident(delivery.IncidentID)
+	if err != nil {
+		return nil, "", fmt.Errorf("故障事件不存在")
+	}
+	if incident.Status == models.IncidentResolved &&
+		(delivery.Kind == models.NotificationReminder || delivery.Kind == models.NotificationEscalation) {
+		return nil, "故障已解决", nil
+	}
+	monitor, err := database.GetMonitorByID(delivery.MonitorID)
+	if err != nil {
+		return nil, "", fmt.Errorf("监控项不存在")
+	}
+
+	msg := &Message{
+		Kind:       delivery.Kind,
+		Title:      delivery.Title,
+		Severity:   incident.Impact,
+		StatusPage: config.AppConfig.StatusPageName,
+		Monitor:    *monitor,
+		Incident:   *incident,
+		Status:     models.StatusText(monitor.EffectiveStatus),
+		Time:       delivery.CreatedAt,
+	}
+	switch delivery.Kind {
+	case models.IncidentChangeOpened:
+		msg.Time = incident.StartedAt
+	case models.IncidentChangeResolved:
+		if incident.ResolvedAt != nil {
+			msg.Time = *incident.ResolvedAt
+		}
+		msg.Duration = msg.Time.Sub(incident.StartedAt)
+	case models.NotificationReminder, models.NotificationEscalation:
+		msg.Duration = msg.Time.Sub(incident.StartedAt)
+	}
+	return msg, "", nil
+}
+
+// SendTest 向渠道发送测试通知并记录到发送历史
+func SendTest(channel *models.NotificationChannel) error {
+	msg := TestMessage()
+	err := Send(channel, msg)
+
+	delivery := models.NotificationDelivery{
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		Kind:        models.NotificationTest,
+		Title:       msg.Title,
+		Status:      models.DeliverySent,
+		CreatedAt:   msg.Time,
+	}
+	if err != nil {
+		delivery.Status, delivery.Error = models.DeliveryFailed, truncate(err.Error(), deliveryErrorMax)
+	} else {
+		delivery.SentAt = &msg.Time
+	}
+	if err := database.CreateNotificationDeliveries([]models.NotificationDelivery{delivery}); err != nil {
+		log.Printf("记录测试通知失败: %v", err)
+	}
+	return err
+}
+
+// truncate 按字符截断过长的文本
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeliveryRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := deliveryRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("deliveryRetryDelay(%d) = %v,应为 %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// TestDeliverDueRetries 发送失败后按退避时间重试,重试期间保持待发送,用完次数后才标记为失败
+func TestDeliverDueRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		wantStatus   string
+		wantAttempts int
+	}{
+		{"重试后成功", 2, models.DeliverySent, 3},
+		{"重试次数用完", deliveryMaxAttempts, models.DeliveryFailed, deliveryMaxAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			monitor := &models.Monitor{ID: 1, Name: "db-main", Status: models.StatusDown}
+			if err := database.SaveMonitor(monitor); err != nil {
+				t.Fatal(err)
+			}
+			incident := &models.Incident{MonitorID: 1, Title: "db-main 离线", Status: models.IncidentInvestigating, Impact: "major", StartedAt: time.Now()}
+			if err := database.DB.Create(incident).Error; err != nil {
+				t.Fatal(err)
+			}
+			channel := &models.NotificationChannel{Name: "值班", Type: models.ChannelSlack, WebhookURL: server.URL}
+			if err := database.SaveNotificationChannel(channel); err != nil {
+				t.Fatal(err)
+			}
+			delivery := models.NotificationDelivery{ChannelID: channel.ID, IncidentID: incident.ID, MonitorID: 1,
+				Kind: models.IncidentChangeOpened, Title: incident.Title, Status: models.DeliveryPending}
+			if err := database.CreateNotificationDeliveries([]models.NotificationDelivery{delivery}); err != nil {
+				t.Fatal(err)
+			}
+
+			load := func() models.NotificationDelivery {
+				t.Helper()
+				var got models.NotificationDelivery
+				if err := database.DB.First(&got).Error; err != nil {
+					t.Fatal(err)
+				}
+				return got
+			}
+			for attempt := 1; ; attempt++ {
+				before := time.Now()
+				deliverDue()
+				got := load()
+				if got.Attempts != attempt || int(requests.Load()) != attempt {
+					t.Fatalf("第 %d 次发送后 attempts = %d,请求 %d 次", attempt, got.Attempts, requests.Load())
+				}
+				if got.Status != models.DeliveryPending {
+					break
+				}
+				if got.NextAttemptAt == nil || got.NextAttemptAt.Before(before.Add(deliveryRetryDelay(attempt))) || got.Error == "" {
+					t.Fatalf("第 %d 次失败后应等待 %v 重试并记录错误: %+v", attempt, deliveryRetryDelay(attempt), got)
+				}
+
+				// 未到重试时间不发送
+				deliverDue()
+				if int(requests.Load()) != attempt {
+					t.Fatalf("未到重试时间不应发送")
+				}
+				if err := database.DB.Model(&got).Update("next_attempt_at", time.Now().Add(-time.Second)).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := load()
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts || got.NextAttemptAt != nil {
+				t.Errorf("最终状态 %s,尝试 %d 次,应为 %s,尝试 %d 次: %+v", got.Status, got.Attempts, tt.wantStatus, tt.wantAttempts, got)
+			}
+			if (got.Status == models.DeliverySent) != (got.SentAt != nil) {
+				t.Errorf("只有发送成功时记录 sentAt: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mail 一封纯文本邮件
+type Mail struct {
+	To      []string
+	Subject string
+	Body    string
+	Headers map[string]string // 额外的邮件头,如 List-Unsubscribe
+}
+
+// sendEmail 通过 SMTP 发送邮件通知,所有收件人在同一封邮件中
+func sendEmail(ctx context.Context, channel *models.NotificationChannel, msg *Message) error {
+	return SendMail(ctx, &Mail{
+		To:      channel.EmailTo,
+		Subject: "[" + msg.StatusPage + "] " + msg.Title,
+		Body:    msg.Body,
+	})
+}
+
+// SMTPConfigured 判断是否配置了 SMTP 服务器
+func SMTPConfigured() bool {
+	return config.AppConfig.SMTPHost != ""
+}
+
+// SendMail 通过配置的 SMTP 服务器发送邮件
+// SMTP_TLS 为 true 时使用隐式 TLS,否则在服务器支持时使用 STARTTLS;设置了用户名时使用 PLAIN 认证
+func SendMail(ctx context.Context, m *Mail) error {
+	cfg := config.AppConfig
+	if !SMTPConfigured() {
+		return errors.New("未配置 SMTP 服务器")
+	}
+	if len(m.To) == 0 {
+		return errors.New("没有收件人")
+	}
+	from, err := mail.ParseAddress(cfg.SMTPFrom)
+	if err != nil {
+		return fmt.Errorf("SMTP_FROM 无效: %w", err)
+	}
+
+	addr := net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: cfg.SMTPHost}
+	if cfg.SMTPTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !cfg.SMTPTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range m.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMail(cfg.SMTPFrom, from.Address, m)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMail 生成邮件内容,标题按 RFC 2047 编码,正文使用 base64
+func buildMail(from, fromAddress string, m *Mail) []byte {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	header("From", from)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(fromAddress))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "base64")
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		header(key, m.Headers[key])
+	}
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(m.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+// messageID 生成邮件的 Message-ID,域名取发件人地址的域名
+func messageID(fromAddress string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
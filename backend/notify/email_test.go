@@ -0,0 +1,231 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"mime"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpMessage fakeSMTP 收到的一封邮件
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// fakeSMTP 接收邮件的本地 SMTP 服务器,不支持 STARTTLS 和认证
+type fakeSMTP struct {
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+// newFakeSMTP 启动 fakeSMTP,并将 SMTP 配置指向它
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	fake := &fakeSMTP{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	config.AppConfig.SMTPHost = host
+	config.AppConfig.SMTPPort, _ = strconv.Atoi(port)
+	config.AppConfig.SMTPFrom = "状态页 <status@example.com>"
+	return fake
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+	var current smtpMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 fake")
+		case "MAIL":
+			current = smtpMessage{From: smtpAddress(command)}
+			reply("250 ok")
+		case "RCPT":
+			current.To = append(current.To, smtpAddress(command))
+			reply("250 ok")
+		case "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			current.Data = data.String()
+			f.mu.Lock()
+			f.messages = append(f.messages, current)
+			f.mu.Unlock()
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// smtpAddress 取出 MAIL FROM:<a> 或 RCPT TO:<a> 中的地址
+func smtpAddress(command string) string {
+	start, end := strings.Index(command, "<"), strings.Index(command, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return command[start+1 : end]
+}
+
+// received 返回已收到的邮件
+func (f *fakeSMTP) received() []smtpMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]smtpMessage(nil), f.messages...)
+}
+
+// parseMail 解析邮件内容,返回邮件头和解码后的正文
+func parseMail(t *testing.T, data string) (mail.Header, string) {
+	t.Helper()
+	m, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("邮件格式无效: %v\n%s", err, data)
+	}
+	body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, m.Body))
+	if err != nil {
+		t.Fatalf("正文不是 base64: %v", err)
+	}
+	return m.Header, string(body)
+}
+
+func TestSendMail(t *testing.T) {
+	setupTestConfig(t)
+	fake := newFakeSMTP(t)
+
+	body := strings.Repeat("正文内容,", 20) + "\n第二行"
+	err := SendMail(context.Background(), &Mail{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "[测试状态页] db-main 离线",
+		Body:    body,
+		Headers: map[string]string{"List-Unsubscribe": "<https://status.example.com/unsubscribe>"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := fake.received()
+	if len(messages) != 1 {
+		t.Fatalf("应收到一封邮件,实际 %d 封", len(messages))
+	}
+	m := messages[0]
+	if m.From != "status@example.com" || strings.Join(m.To, ",") != "a@example.com,b@example.com" {
+		t.Errorf("发件人或收件人不正确: %+v", m)
+	}
+	_, encoded, _ := strings.Cut(m.Data, "\r\n\r\n")
+	for _, line := range strings.Split(strings.TrimRight(encoded, "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("base64 正文每行不应超过 76 个字符: %q", line)
+		}
+	}
+
+	header, decoded := parseMail(t, m.Data)
+	if raw := header.Get("Subject"); !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Errorf("Subject 应按 RFC 2047 Q 编码,实际 %q", raw)
+	}
+	if subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject")); err != nil || subject != "[测试状态页] db-main 离线" {
+		t.Errorf("Subject 解码后不正确: %q, %v", subject, err)
+	}
+	want := map[string]string{
+		"From":                      "状态页 <status@example.com>",
+		"To":                        "a@example.com, b@example.com",
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "base64",
+		"List-Unsubscribe":          "<https://status.example.com/unsubscribe>",
+	}
+	for key, value := range want {
+		if header.Get(key) != value {
+			t.Errorf("%s 应为 %q,实际 %q", key, value, header.Get(key))
+		}
+	}
+	if id := header.Get("Message-Id"); !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Message-ID 应使用发件人域名: %q", id)
+	}
+	if _, err := header.Date(); err != nil {
+		t.Errorf("Date 无效: %v", err)
+	}
+	if decoded != body {
+		t.Errorf("正文解码后不一致: %q", decoded)
+	}
+}
+
+func TestSendMailErrors(t *testing.T) {
+	setupTestConfig(t)
+	if err := SendMail(context.Background(), &Mail{To: []string{"a@example.com"}}); err == nil {
+		t.Error("未配置 SMTP 服务器时应返回错误")
+	}
+	newFakeSMTP(t)
+	if err := SendMail(context.Background(), &Mail{}); err == nil {
+		t.Error("没有收件人时应返回错误")
+	}
+}
+
+// TestSendEmailChannel 邮件渠道的标题带状态页名称,所有收件人在同一封邮件中
+func TestSendEmailChannel(t *testing.T) {
+	setupTestConfig(t)
+	fake := newFakeSMTP(t)
+
+	channel := &models.NotificationChannel{Name: "邮件", Type: models.ChannelEmail, EmailTo: []string{"ops@example.com", "dba@example.com"}}
+	if err := Send(channel, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	messages := fake.received()
+	if len(messages) != 1 || len(messages[0].To) != 2 {
+		t.Fatalf("应发送一封包含所有收件人的邮件: %+v", messages)
+	}
+	header, body := parseMail(t, messages[0].Data)
+	subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if subject != "[测试状态页] <db> & cache 离线" {
+		t.Errorf("标题不正确: %q", subject)
+	}
+	if !strings.Contains(body, "监控项: <db> & cache (数据库)") || !strings.Contains(body, "状态: 离线") {
+		t.Errorf("正文应使用默认模板: %q", body)
+	}
+}
@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"log"
+	"slices"
+	"text/template"
+	"time"
+)
+
+// Message 一条通知,由故障事件变化或告警和监控项生成,各渠道按自己的格式发送
+type Message struct {
+	Kind       string          // opened、escalated、resolved、reminder、escalation,告警为 anomaly、slo_alert 等,测试通知为 test
+	Title      string          // 标题,如 "Website 离线"
+	Severity   string          // 影响程度 minor、major、critical
+	StatusPage string          // 状态页名称
+	Monitor    models.Monitor  // 应用展示覆盖后的监控项,按分组统计的 SLO 只有 SLO 名称和分组
+	Incident   models.Incident // 对应的故障事件,告警通知为由告警生成的事件
+	Status     string          // 监控项当前状态的中文描述,按分组统计的 SLO 为空
+	Time       time.Time       // 变化发生的时间
+	Duration   time.Duration   // 故障持续时长,opened 和 escalated 没有值
+	Body       string          // 按渠道模板渲染后的正文
+}
+
+// driver 向某种渠道发送一条通知
+type driver func(ctx context.Context, channel *models.NotificationChannel, msg *Message) error
+
+// drivers 各渠道类型的发送实现
+var drivers = map[string]driver{
+	models.ChannelSlack:    sendSlack,
+	models.ChannelDiscord:  sendDiscord,
+	models.ChannelTeams:    sendTeams,
+	models.ChannelTelegram: sendTelegram,
+	models.ChannelEmail:    sendEmail,
+}
+
+// defaultTemplate 默认的消息正文模板
+const defaultTemplate = `监控项: {{.Monitor.Name}}{{with .Monitor.Group}} ({{.}}){{end}}
+{{- with .Status}}
+状态: {{.}}{{end}}
+{{- with .Incident.Message}}
+信息: {{.}}{{end}}
+开始时间: {{formatTime .Incident.StartedAt}}
+{{- if eq .Kind "resolved"}}
+恢复时间: {{formatTime .Time}}{{end}}
+{{- if .Duration}}
+持续时长: {{formatDuration .Duration}}{{end}}
+{{- with .Monitor.URL}}
+地址: {{.}}{{end}}`
+
+// templateFuncs 消息模板可用的函数,时间按服务器时区(TZ)显示
+var templateFuncs = template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		return t.Local().Format("2006-01-02 15:04:05 MST")
+	},
+	"formatDuration": formatDuration,
+}
+
+// ParseTemplate 解析消息正文模板,用于保存渠道前校验
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("message").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// SupportedChannel 判断是否支持该渠道类型
+func SupportedChannel(channelType string) bool {
+	_, ok := drivers[channelType]
+	return ok
+}
+
+// TestMessage 生成测试通知,用于确认渠道配置
+func TestMessage() *Message {
+	now := time.Now()
+	return &Message{
+		Kind:       models.NotificationTest,
+		Title:      "测试通知",
+		Severity:   "major",
+		StatusPage: config.AppConfig.StatusPageName,
+		Monitor:    models.Monitor{Name: "示例监控项", Group: "示例分组"},
+		Incident:   models.Incident{Title: "示例监控项 离线", Impact: "major", Message: "这是一条测试通知", StartedAt: now},
+		Status:     models.StatusText(models.StatusDown),
+		Time:       now,
+	}
+}
+
+// channelMatches 按渠道自身的过滤条件判断是否接收该监控项的通知
+// 先按最低影响程度过滤,再按分组和监控项过滤,两者都未设置时接收所有监控项
+func channelMatches(channel *models.NotificationChannel, monitor *models.Monitor, severity string) bool {
+	if models.SeverityRank(severity) < models.SeverityRank(channel.MinSeverity) {
+		return false
+	}
+	if len(channel.Groups) == 0 && len(channel.MonitorIDs) == 0 {
+		return true
+	}
+	return slices.Contains(channel.Groups, monitor.Group) || slices.Contains(channel.MonitorIDs, monitor.ID)
+}
+
+// Send 按渠道模板渲染正文并发送,msg 会被复制,不影响其他渠道
+func Send(channel *models.NotificationChannel, msg *Message) error {
+	send, ok := drivers[channel.Type]
+	if !ok {
+		return fmt.Errorf("不支持的渠道类型: %s", channel.Type)
+	}
+
+	rendered := *msg
+	rendered.Body = render(channel, msg)
+	ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.NotifyTimeout)
+	defer cancel()
+	return send(ctx, channel, &rendered)
+}
+
+// render 渲染消息正文,渠道模板无效或执行失败时使用默认模板
+func render(channel *models.NotificationChannel, msg *Message) string {
+	if channel.Template != "" {
+		tmpl, err := ParseTemplate(channel.Template)
+		if err == nil {
+			var buf bytes.Buffer
+			if err = tmpl.Execute(&buf, msg); err == nil {
+				return buf.String()
+			}
+		}
+		log.Printf("通知模板渲染失败,使用默认模板 [%s]: %v", channel.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := defaultMessageTemplate.Execute(&buf, msg); err != nil {
+		log.Printf("默认通知模板渲染失败: %v", err)
+	}
+	return buf.String()
+}
+
+var defaultMessageTemplate = template.Must(ParseTemplate(defaultTemplate))
+
+// formatDuration 将时长格式化为中文描述,精确到分钟,不足一分钟时显示秒
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d 秒", int(d.Seconds()))
+	}
+	minutes := int(d.Minutes())
+	days, hours := minutes/(24*60), minutes/60%24
+	minutes %= 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d 天 %d 小时 %d 分钟", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d 小时 %d 分钟", hours, minutes)
+	default:
+		return fmt.Sprintf("%d 分钟", minutes)
+	}
+}
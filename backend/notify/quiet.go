@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"kuma-lite/backend/models"
+	"time"
+)
+
+// ParseClock 解析 HH:MM 格式的时刻,返回当天的分钟数
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil || len(value) != len("15:04") {
+		return 0, fmt.Errorf("时间格式需要是 HH:MM: %s", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// quietUntil 判断 now 是否处于渠道的安静时段,是时返回本次安静时段的结束时间
+// 结束时间不晚于开始时间时安静时段跨越午夜;配置无效时视为没有安静时段
+func quietUntil(channel *models.NotificationChannel, now time.Time) (time.Time, bool) {
+	if channel.QuietStart == "" || channel.QuietEnd == "" {
+		return time.Time{}, false
+	}
+	start, err := ParseClock(channel.QuietStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := ParseClock(channel.QuietEnd)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc := time.Local
+	if channel.QuietTimezone != "" {
+		if loc, err = time.LoadLocation(channel.QuietTimezone); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	day := local.Day()
+	if start >= end && minute >= start {
+		day++
+	}
+	return time.Date(local.Year(), local.Month(), day, end/60, end%60, 0, 0, loc), true
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"path"
+	"strings"
+)
+
+// target 通知的接收渠道及选中该渠道的路由规则
+type target struct {
+	channel *models.NotificationChannel
+	ruleID  int // 按渠道自身的过滤条件选中时为 0
+}
+
+// routing 一次路由使用的渠道和启用的规则
+type routing struct {
+	channels []models.NotificationChannel
+	byID     map[int]*models.NotificationChannel
+	rules    []models.NotificationRule
+	routed   map[int]bool // 被启用的规则引用的渠道,不再按自身的过滤条件接收
+}
+
+// loadRouting 读取所有渠道和启用的路由规则
+func loadRouting() (*routing, error) {
+	channels, err := database.GetNotificationChannels()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := database.GetNotificationRules()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &routing{
+		channels: channels,
+		byID:     make(map[int]*models.NotificationChannel, len(channels)),
+		routed:   make(map[int]bool),
+	}
+	for i := range r.channels {
+		r.byID[r.channels[i].ID] = &r.channels[i]
+	}
+	for _, rule := range rules {
+		if rule.Disabled {
+			continue
+		}
+		r.rules = append(r.rules, rule)
+		for _, id := range rule.ChannelIDs {
+			r.routed[id] = true
+		}
+		for _, id := range rule.EscalationChannelIDs {
+			r.routed[id] = true
+		}
+	}
+	return r, nil
+}
+
+// channel 获取启用的渠道,不存在或已停用时返回 nil
+func (r *routing) channel(id int) *models.NotificationChannel {
+	channel := r.byID[id]
+	if channel == nil || channel.Disabled {
+		return nil
+	}
+	return channel
+}
+
+// targets 计算监控项的通知应发送到哪些渠道,每个渠道最多一次
+// 先按规则顺序匹配规则的渠道,再加入未被规则引用且自身过滤条件匹配的渠道
+func (r *routing) targets(monitor *models.Monitor, severity string) []target {
+	var targets []target
+	seen := make(map[int]bool)
+	for _, rule := range r.rules {
+		if !ruleMatches(&rule, monitor, severity) {
+			continue
+		}
+		for _, id := range rule.ChannelIDs {
+			if channel := r.channel(id); channel != nil && !seen[id] {
+				seen[id] = true
+				targets = append(targets, target{channel: channel, ruleID: rule.ID})
+			}
+		}
+	}
+	for i := range r.channels {
+		channel := &r.channels[i]
+		if channel.Disabled || r.routed[channel.ID] || seen[channel.ID] {
+			continue
+		}
+		if channelMatches(channel, monitor, severity) {
+			seen[channel.ID] = true
+			targets = append(targets, target{channel: channel})
+		}
+	}
+	return targets
+}
+
+// ruleMatches 判断规则是否匹配监控项和影响程度,未设置的条件不限制
+func ruleMatches(rule *models.NotificationRule, monitor *models.Monitor, severity string) bool {
+	if models.SeverityRank(severity) < models.SeverityRank(rule.MinSeverity) {
+		return false
+	}
+	if rule.Group != "" && rule.Group != monitor.Group {
+		return false
+	}
+	if rule.MonitorPattern != "" {
+		matched, err := path.Match(strings.ToLower(rule.MonitorPattern), strings.ToLower(monitor.Name))
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if rule.Tag != "" && !hasTag(monitor, rule.Tag) {
+		return false
+	}
+	return true
+}
+
+// hasTag 判断监控项是否有指定标签,tag 为 "名称" 或 "名称:值",不区分大小写
+func hasTag(monitor *models.Monitor, tag string) bool {
+	name, value, withValue := strings.Cut(tag, ":")
+	for _, t := range monitor.Tags {
+		if !strings.EqualFold(t.Name, name) {
+			continue
+		}
+		if !withValue || strings.EqualFold(t.Value, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SubscriptionConfirmTTL 确认链接的有效期,过期仍未确认的订阅者会被清理
+const SubscriptionConfirmTTL = 48 * time.Hour
+
+// subscriptionMailMax 一封邮件最多列出的状态更新条数
+const subscriptionMailMax = 50
+
+// subscriptionMu 保证同一时间只有一个后台发送订阅邮件
+var subscriptionMu sync.Mutex
+
+// SubscriptionsEnabled 判断是否启用邮件订阅: 需要配置 PUBLIC_URL 和 SMTP 服务器
+func SubscriptionsEnabled() bool {
+	return config.AppConfig.PublicURL != "" && SMTPConfigured()
+}
+
+// NewSubscriptionToken 生成确认或退订令牌
+func NewSubscriptionToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
+
+// RecordStatusUpdates 将故障事件的开始、结束和维护的开始、结束记录为订阅者的状态更新
+// 与通知渠道一样,归入根因事件的故障事件不单独记录;响应缓慢升级为离线不算新的故障
+func RecordStatusUpdates(changes []models.IncidentChange, started, ended []models.MaintenancePeriod, now time.Time) {
+	if !SubscriptionsEnabled() {
+		return
+	}
+
+	var updates []models.StatusUpdate
+	add := func(kind string, monitorID int, title func(name string) string) {
+		monitor, err := database.GetMonitorByID(monitorID)
+		if err != nil {
+			log.Printf("获取监控项失败,跳过状态更新 (ID: %d): %v", monitorID, err)
+			return
+		}
+		updates = append(updates, models.StatusUpdate{
+			Kind:      kind,
+			MonitorID: monitor.ID,
+			Group:     monitor.Group,
+			Title:     title(monitor.Name),
+			CreatedAt: now,
+		})
+	}
+
+	for _, change := range changes {
+		incident := change.Incident
+		if models.SeverityRank(incident.Impact) == 0 {
+			continue
+		}
+		switch change.Kind {
+		case models.IncidentChangeOpened:
+			add(models.UpdateIncidentOpened, incident.MonitorID, func(string) string { return incident.Title })
+		case models.IncidentChangeResolved:
+			add(models.UpdateIncidentResolved, incident.MonitorID, func(name string) string { return name + " 已恢复" })
+		}
+	}
+	for _, period := range started {
+		add(models.UpdateMaintenanceStarted, period.MonitorID, func(name string) string { return name + " 开始维护" })
+	}
+	for _, period := range ended {
+		add(models.UpdateMaintenanceEnded, period.MonitorID, func(name string) string { return name + " 维护结束" })
+	}
+
+	if err := database.CreateStatusUpdates(updates); err != nil {
+		log.Printf("记录状态更新失败: %v", err)
+	}
+}
+
+// ProcessSubscriptions 在后台向订阅者发送状态更新邮件,上一次发送未完成时跳过
+func ProcessSubscriptions() {
+	if !SubscriptionsEnabled() {
+		return
+	}
+	go func() {
+		if !subscriptionMu.TryLock() {
+			return
+		}
+		defer subscriptionMu.Unlock()
+		sendStatusUpdates(time.Now())
+	}()
+}
+
+// sendStatusUpdates 向每个订阅者发送其订阅分组中尚未发送的状态更新
+// 距离上一封邮件不足 SUBSCRIPTION_INTERVAL 的订阅者暂不发送,期间的更新在下一封邮件中合并发送
+func sendStatusUpdates(now time.Time) {
+	subscribers, err := database.GetConfirmedSubscribers()
+	if err != nil {
+		log.Printf("获取订阅者失败: %v", err)
+		return
+	}
+	if len(subscribers) == 0 {
+		return
+	}
+	after := subscribers[0].LastUpdateID
+	for _, subscriber := range subscribers {
+		after = min(after, subscriber.LastUpdateID)
+	}
+	updates, err := database.GetStatusUpdatesAfter(after)
+	if err != nil {
+		log.Printf("获取状态更新失败: %v", err)
+		return
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	for i := range subscribers {
+		subscriber := &subscribers[i]
+		if subscriber.LastSentAt != nil && now.Sub(*subscriber.LastSentAt) < config.AppConfig.SubscriptionInterval {
+			continue
+		}
+		var pending []models.StatusUpdate
+		for _, update := range updates {
+			if update.ID > subscriber.LastUpdateID &&
+				(len(subscriber.Groups) == 0 || slices.Contains(subscriber.Groups, update.Group)) {
+				pending = append(pending, update)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.NotifyTimeout)
+		err := SendMail(ctx, statusUpdateMail(subscriber, pending))
+		cancel()
+		if err != nil {
+			log.Printf("发送状态更新邮件失败 (订阅者 ID: %d): %v", subscriber.ID, err)
+			continue
+		}
+		if err := database.MarkSubscriberSent(subscriber.ID, pending[len(pending)-1].ID, now); err != nil {
+			log.Printf("记录订阅邮件发送失败 (订阅者 ID: %d): %v", subscriber.ID, err)
+		}
+	}
+}
+
+// statusUpdateMail 生成状态更新邮件,带一键退订的 List-Unsubscribe 头
+func statusUpdateMail(subscriber *models.Subscriber, updates []models.StatusUpdate) *Mail {
+	cfg := config.AppConfig
+	subject := "[" + cfg.StatusPageName + "] " + updates[0].Title
+	if len(updates) > 1 {
+		subject = fmt.Sprintf("[%s] %d 条状态更新", cfg.StatusPageName, len(updates))
+	}
+
+	var body strings.Builder
+	for i, update := range updates {
+		if i == subscriptionMailMax {
+			fmt.Fprintf(&body, "以及另外 %d 条更新\n", len(updates)-i)
+			break
+		}
+		fmt.Fprintf(&body, "%s  %s\n", update.CreatedAt.Local().Format("2006-01-02 15:04 MST"), update.Title)
+	}
+	token := url.QueryEscape(subscriber.UnsubscribeToken)
+	fmt.Fprintf(&body, "\n查看状态页: %s/\n退订: %s/?unsubscribe=%s\n", cfg.PublicURL, cfg.PublicURL, token)
+
+	return &Mail{
+		To:      []string{subscriber.Email},
+		Subject: subject,
+		Body:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + cfg.PublicURL + "/api/subscriptions/unsubscribe?token=" + token + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+}
+
+// SendConfirmation 发送订阅确认邮件,订阅者点击链接后才开始接收状态更新
+func SendConfirmation(subscriber *models.Subscriber) error {
+	cfg := config.AppConfig
+	groups := "所有分组"
+	if len(subscriber.PendingGroups) > 0 {
+		groups = "分组 " + strings.Join(subscriber.PendingGroups, "、")
+	}
+	body := fmt.Sprintf("有人使用此邮箱订阅了 %s 的状态更新(%s)。\n\n确认订阅: %s/?confirm=%s\n\n链接 %d 小时内有效。如果不是你本人操作,忽略此邮件即可。\n",
+		cfg.StatusPageName, groups, cfg.PublicURL, url.QueryEscape(subscriber.ConfirmToken), int(SubscriptionConfirmTTL.Hours()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.NotifyTimeout)
+	defer cancel()
+	return SendMail(ctx, &Mail{
+		To:      []string{subscriber.Email},
+		Subject: "[" + cfg.StatusPageName + "] 请确认订阅状态更新",
+		Body:    body,
+	})
+}
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"mime"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupTestDB 使用临时数据库和默认配置
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		StatusPageName: "测试状态页",
+		NotifyTimeout:  5 * time.Second,
+	}
+	if err := database.InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	t.Cleanup(func() { database.CloseDB() })
+}
+
+// TestSendStatusUpdatesBatching 距离上一封邮件不足 SUBSCRIPTION_INTERVAL 时暂不发送,之后的更新合并到一封邮件中
+func TestSendStatusUpdatesBatching(t *testing.T) {
+	setupTestDB(t)
+	fake := newFakeSMTP(t)
+	config.AppConfig.PublicURL = "https://status.example.com"
+	config.AppConfig.SubscriptionInterval = 15 * time.Minute
+
+	now := time.Now()
+	subscribers := []*models.Subscriber{
+		{Email: "all@example.com", Groups: []string{}, UnsubscribeToken: "token-all"},
+		{Email: "core@example.com", Groups: []string{"Core"}, UnsubscribeToken: "token-core"},
+	}
+	for _, subscriber := range subscribers {
+		subscriber.PendingGroups = subscriber.Groups
+		if err := database.ConfirmSubscriber(subscriber, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := func(group, title string) {
+		t.Helper()
+		if err := database.CreateStatusUpdates([]models.StatusUpdate{
+			{Kind: models.UpdateIncidentOpened, Group: group, Title: title, CreatedAt: now},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// received 返回发送给各收件人的邮件标题
+	received := func() map[string][]string {
+		t.Helper()
+		subjects := make(map[string][]string)
+		for _, m := range fake.received() {
+			header, _ := parseMail(t, m.Data)
+			subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+			subjects[m.To[0]] = append(subjects[m.To[0]], subject)
+		}
+		return subjects
+	}
+
+	record("Core", "Website 离线")
+	sendStatusUpdates(now)
+	subjects := received()
+	if len(subjects["all@example.com"]) != 1 || subjects["core@example.com"][0] != "[测试状态页] Website 离线" {
+		t.Fatalf("两个订阅者都应收到第一条更新: %+v", subjects)
+	}
+
+	record("Core", "Website 已恢复")
+	record("Other", "API 离线")
+	sendStatusUpdates(now.Add(5 * time.Minute))
+	if subjects := received(); len(subjects["all@example.com"]) != 1 || len(subjects["core@example.com"]) != 1 {
+		t.Fatalf("间隔内不应发送: %+v", subjects)
+	}
+
+	sendStatusUpdates(now.Add(16 * time.Minute))
+	subjects = received()
+	if got := subjects["all@example.com"]; len(got) != 2 || got[1] != "[测试状态页] 2 条状态更新" {
+		t.Errorf("订阅所有分组的订阅者应收到合并的一封邮件: %+v", got)
+	}
+	if got := subjects["core@example.com"]; len(got) != 2 || got[1] != "[测试状态页] Website 已恢复" {
+		t.Errorf("订阅 Core 的订阅者只应收到 Core 的更新: %+v", got)
+	}
+
+	messages := fake.received()
+	header, body := parseMail(t, messages[len(messages)-1].Data)
+	if !strings.Contains(header.Get("List-Unsubscribe"), "token=token-") || header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+		t.Errorf("应带一键退订的邮件头: %v", header)
+	}
+	if !strings.Contains(body, "退订: https://status.example.com/?unsubscribe=token-") {
+		t.Errorf("正文应包含退订链接: %q", body)
+	}
+
+	// 全部发送后不再重复发送
+	sendStatusUpdates(now.Add(time.Hour))
+	if n := len(fake.received()); n != 4 {
+		t.Errorf("没有新的更新时不应发送,共 %d 封", n)
+	}
+}
@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"strings"
+)
+
+// sendTelegram 通过 Telegram Bot API 发送纯文本消息,不使用 parse_mode 以免转义问题
+func sendTelegram(ctx context.Context, channel *models.NotificationChannel, msg *Message) error {
+	url := strings.TrimRight(config.AppConfig.TelegramAPIURL, "/") + "/bot" + channel.BotToken + "/sendMessage"
+	err := postJSON(ctx, url, map[string]interface{}{
+		"chat_id":                  channel.ChatID,
+		"text":                     msg.Title + "\n\n" + msg.Body,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		// 请求失败时错误信息包含 URL,隐去其中的 bot token
+		return errors.New(strings.ReplaceAll(err.Error(), channel.BotToken, "***"))
+	}
+	return nil
+}
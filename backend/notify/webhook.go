@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kuma-lite/backend/models"
+	"net/http"
+	"strings"
+)
+
+// discordMaxContent Discord 消息内容的最大字符数
+const discordMaxContent = 2000
+
+// slackEscaper 转义 Slack mrkdwn 中的控制字符
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// httpClient 发送 webhook 和 Telegram 请求,超时由 context 控制
+var httpClient = &http.Client{}
+
+// sendSlack 通过 Slack incoming webhook 发送
+func sendSlack(ctx context.Context, channel *models.NotificationChannel, msg *Message) error {
+	return postJSON(ctx, channel.WebhookURL, map[string]interface{}{
+		"text": "*" + slackEscaper.Replace(msg.Title) + "*\n" + slackEscaper.Replace(msg.Body),
+	})
+}
+
+// sendDiscord 通过 Discord webhook 发送,禁止消息中的 @ 提及
+func sendDiscord(ctx context.Context, channel *models.NotificationChannel, msg *Message) error {
+	content := []rune("**" + msg.Title + "**\n" + msg.Body)
+	if len(content) > discordMaxContent {
+		content = content[:discordMaxContent]
+	}
+	return postJSON(ctx, channel.WebhookURL, map[string]interface{}{
+		"content":          string(content),
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	})
+}
+
+// sendTeams 通过 Microsoft Teams incoming webhook 以 MessageCard 发送
+// Teams 的 Markdown 需要空行才换行
+func sendTeams(ctx context.Context, channel *models.NotificationChannel, msg *Message) error {
+	return postJSON(ctx, channel.WebhookURL, map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"themeColor": messageColor(msg),
+		"title":      msg.Title,
+		"text":       strings.ReplaceAll(msg.Body, "\n", "\n\n"),
+	})
+}
+
+// messageColor 通知的主题色: 恢复为绿色,响应缓慢为黄色,其他为红色
+func messageColor(msg *Message) string {
+	switch {
+	case msg.Kind == models.IncidentChangeResolved:
+		return "16A34A"
+	case msg.Severity == "minor":
+		return "EAB308"
+	default:
+		return "DC2626"
+	}
+}
+
+// postJSON 以 JSON 发送 POST 请求,非 2xx 响应视为失败
+func postJSON(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// capturedRequest 测试服务器收到的一次请求
+type capturedRequest struct {
+	path        string
+	contentType string
+	payload     map[string]interface{}
+}
+
+// newCaptureServer 启动记录请求的测试服务器,返回 status 状态码
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := capturedRequest{path: r.URL.Path, contentType: r.Header.Get("Content-Type")}
+		if err := json.Unmarshal(body, &request.payload); err != nil {
+			t.Errorf("请求体不是 JSON: %q", body)
+		}
+		requests <- request
+		w.WriteHeader(status)
+		io.WriteString(w, "服务器错误")
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// testMessage 用于测试驱动的通知,标题包含需要转义的字符
+func testMessage() *Message {
+	started := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	return &Message{
+		Kind:       models.IncidentChangeOpened,
+		Title:      "<db> & cache 离线",
+		Severity:   "major",
+		StatusPage: "测试状态页",
+		Monitor:    models.Monitor{ID: 1, Name: "<db> & cache", Group: "数据库"},
+		Incident:   models.Incident{Title: "<db> & cache 离线", Impact: "major", StartedAt: started},
+		Status:     "离线",
+		Time:       started,
+	}
+}
+
+func setupTestConfig(t *testing.T) {
+	t.Helper()
+	config.AppConfig = &config.Config{StatusPageName: "测试状态页", NotifyTimeout: 5 * time.Second}
+}
+
+func receive(t *testing.T, requests chan capturedRequest) capturedRequest {
+	t.Helper()
+	select {
+	case request := <-requests:
+		if request.contentType != "application/json" {
+			t.Errorf("Content-Type 应为 application/json,实际 %q", request.contentType)
+		}
+		return request
+	default:
+		t.Fatal("没有收到请求")
+		return capturedRequest{}
+	}
+}
+
+func TestSendSlack(t *testing.T) {
+	setupTestConfig(t)
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	channel := &models.NotificationChannel{Name: "slack", Type: models.ChannelSlack, WebhookURL: server.URL, Template: "{{.Monitor.Name}}"}
+	if err := Send(channel, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	request := receive(t, requests)
+	want := "*&lt;db&gt; &amp; cache 离线*\n&lt;db&gt; &amp; cache"
+	if text := request.payload["text"]; text != want {
+		t.Errorf("text 应为 %q,实际 %q", want, text)
+	}
+}
+
+func TestSendDiscord(t *testing.T) {
+	setupTestConfig(t)
+	server, requests := newCaptureServer(t, http.StatusNoContent)
+
+	channel := &models.NotificationChannel{Name: "discord", Type: models.ChannelDiscord, WebhookURL: server.URL, Template: "@everyone {{.Status}}"}
+	if err := Send(channel, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	request := receive(t, requests)
+	if content := request.payload["content"]; content != "**<db> & cache 离线**\n@everyone 离线" {
+		t.Errorf("content 不正确: %q", content)
+	}
+	mentions, ok := request.payload["allowed_mentions"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("应设置 allowed_mentions: %+v", request.payload)
+	}
+	if parse, ok := mentions["parse"].([]interface{}); !ok || len(parse) != 0 {
+		t.Errorf("allowed_mentions.parse 应为空数组,实际 %#v", mentions["parse"])
+	}
+
+	// 超长的内容按字符截断到 2000 个
+	channel.Template = strings.Repeat("长", 3000)
+	if err := Send(channel, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	request = receive(t, requests)
+	if content, _ := request.payload["content"].(string); len([]rune(content)) != discordMaxContent {
+		t.Errorf("content 应截断到 %d 个字符,实际 %d", discordMaxContent, len([]rune(content)))
+	}
+}
+
+func TestSendTeams(t *testing.T) {
+	setupTestConfig(t)
+	server, requests := newCaptureServer(t, http.StatusOK)
+
+	channel := &models.NotificationChannel{Name: "teams", Type: models.ChannelTeams, WebhookURL: server.URL, Template: "第一行\n第二行"}
+	msg := testMessage()
+	if err := Send(channel, msg); err != nil {
+		t.Fatal(err)
+	}
+	request := receive(t, requests)
+	want := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"themeColor": "DC2626",
+		"title":      msg.Title,
+		"text":       "第一行\n\n第二行",
+	}
+	for key, value := range want {
+		if request.payload[key] != value {
+			t.Errorf("%s 应为 %q,实际 %q", key, value, request.payload[key])
+		}
+	}
+
+	msg.Kind = models.IncidentChangeResolved
+	if err := Send(channel, msg); err != nil {
+		t.Fatal(err)
+	}
+	if color := receive(t, requests).payload["themeColor"]; color != "16A34A" {
+		t.Errorf("恢复通知应为绿色,实际 %q", color)
+	}
+}
+
+func TestSendWebhookError(t *testing.T) {
+	setupTestConfig(t)
+	server, requests := newCaptureServer(t, http.StatusInternalServerError)
+
+	channel := &models.NotificationChannel{Name: "slack", Type: models.ChannelSlack, WebhookURL: server.URL}
+	err := Send(channel, testMessage())
+	receive(t, requests)
+	if err == nil || err.Error() != "HTTP 500: 服务器错误" {
+		t.Errorf("非 2xx 响应应返回状态码和响应内容,实际 %v", err)
+	}
+}
+
+func TestSendTelegram(t *testing.T) {
+	setupTestConfig(t)
+	server, requests := newCaptureServer(t, http.StatusOK)
+	config.AppConfig.TelegramAPIURL = server.URL + "/"
+
+	const token = "123456:secret-token"
+	channel := &models.NotificationChannel{Name: "telegram", Type: models.ChannelTelegram, BotToken: token, ChatID: "-1001", Template: "正文"}
+	if err := Send(channel, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	request := receive(t, requests)
+	if request.path != "/bot"+token+"/sendMessage" {
+		t.Errorf("请求路径不正确: %q", request.path)
+	}
+	if request.payload["chat_id"] != "-1001" || request.payload["text"] != "<db> & cache 离线\n\n正文" ||
+		request.payload["disable_web_page_preview"] != true {
+		t.Errorf("请求内容不正确: %+v", request.payload)
+	}
+
+	// 连接失败时错误信息中的 URL 包含 token,应被隐去
+	server.Close()
+	err := Send(channel, testMessage())
+	if err == nil {
+		t.Fatal("服务器关闭后应发送失败")
+	}
+	if strings.Contains(err.Error(), token) || !strings.Contains(err.Error(), "/bot***/sendMessage") {
+		t.Errorf("错误信息应隐去 bot token: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/notify"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// anomalyRecentSamples 判断是否异常时取最近多少个正常心跳的中位数
+const anomalyRecentSamples = 5
+
+// cachedBaseline 某个监控项在某个小时的响应时间基线,nil 表示样本不足
+type cachedBaseline struct {
+	hour     time.Time
+	baseline *database.LatencyBaseline
+}
+
+var (
+	// anomalyBaselines 按监控项缓存当前小时的基线,进入下一个小时后重新计算
+	anomalyBaselines   = make(map[int]cachedBaseline)
+	anomalyBaselinesMu sync.Mutex
+)
+
+// detectAnomalies 对比最近的响应时间和同时段的历史基线,记录或结束响应时间异常
+// 偏离程度超过阈值时开始异常,降到阈值一半以下时结束;非正常状态的监控项不参与判断
+// 异常的开始和结束记录为待发送的通知,由 ProcessNotifications 发送
+func detectAnomalies(monitors []models.Monitor, now time.Time) {
+	cfg := config.AppConfig
+	if cfg.AnomalyThreshold <= 0 {
+		return
+	}
+
+	open, err := database.GetOpenAnomalies()
+	if err != nil {
+		log.Printf("获取响应时间异常失败: %v", err)
+		return
+	}
+
+	anomalyBaselinesMu.Lock()
+	defer anomalyBaselinesMu.Unlock()
+
+	hour := now.Truncate(time.Hour)
+	for _, monitor := range monitors {
+		if monitor.Status != models.StatusUp {
+			continue
+		}
+
+		cached, ok := anomalyBaselines[monitor.ID]
+		if !ok || !cached.hour.Equal(hour) {
+			baseline, err := database.GetLatencyBaseline(monitor.ID, hour, cfg.AnomalyBaselineDays)
+			if err != nil {
+				log.Printf("计算响应时间基线失败 [%s]: %v", monitor.Name, err)
+				continue
+			}
+			cached = cachedBaseline{hour: hour, baseline: baseline}
+			anomalyBaselines[monitor.ID] = cached
+		}
+
+		recent, err := database.GetRecentResponseTime(monitor.ID, anomalyRecentSamples)
+		if err != nil {
+			log.Printf("获取最近响应时间失败 [%s]: %v", monitor.Name, err)
+			continue
+		}
+
+		score := 0.0
+		if cached.baseline != nil && recent > 0 {
+			score = cached.baseline.Score(recent)
+		}
+		responseTime := int(math.Round(recent))
+
+		anomaly, isOpen := open[monitor.ID]
+		switch {
+		case !isOpen && score >= cfg.AnomalyThreshold:
+			anomaly = models.LatencyAnomaly{
+				MonitorID:    monitor.ID,
+				ResponseTime: responseTime,
+				Baseline:     cached.baseline.Median,
+				Score:        score,
+				StartedAt:    now,
+			}
+			if err := database.OpenAnomaly(&anomaly, monitor.Name); err != nil {
+				log.Printf("记录响应时间异常失败 [%s]: %v", monitor.Name, err)
+				continue
+			}
+			log.Printf("响应时间异常: [%s] %dms,同时段通常为 %.0fms", monitor.Name, responseTime, anomaly.Baseline)
+			notify.QueueAnomaly(models.NotificationAnomaly, &anomaly, now)
+		case isOpen && score < cfg.AnomalyThreshold/2:
+			if err := database.CloseAnomaly(anomaly.ID, now); err != nil {
+				log.Printf("结束响应时间异常失败 [%s]: %v", monitor.Name, err)
+				continue
+			}
+			log.Printf("响应时间恢复正常: [%s]", monitor.Name)
+			notify.QueueAnomaly(models.NotificationAnomalyResolved, &anomaly, now)
+		case isOpen && score > anomaly.Score:
+			if err := database.UpdateAnomalyPeak(anomaly.ID, responseTime, score); err != nil {
+				log.Printf("更新响应时间异常失败 [%s]: %v", monitor.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"fmt"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"log"
+	"math"
+	"time"
+)
+
+// evaluateComposites 计算组合监控项的状态,写入心跳并更新监控项
+// 返回计算后的组合监控项,供故障事件判断使用
+func evaluateComposites(now time.Time) []models.Monitor {
+	composites, err := database.GetComposites()
+	if err != nil {
+		log.Printf("获取组合监控项失败: %v", err)
+		return nil
+	}
+	if len(composites) == 0 {
+		return nil
+	}
+
+	var ids []int
+	for _, composite := range composites {
+		ids = append(ids, composite.ID)
+		for _, member := range composite.Members {
+			ids = append(ids, member.MonitorID)
+		}
+	}
+	monitors, err := database.GetMonitorsByIDs(ids)
+	if err != nil {
+		log.Printf("获取组合监控项成员失败: %v", err)
+		return nil
+	}
+	byID := make(map[int]models.Monitor, len(monitors))
+	for _, monitor := range monitors {
+		byID[monitor.ID] = monitor
+	}
+
+	evaluated := make([]models.Monitor, 0, len(composites))
+	for _, composite := range composites {
+		monitor, ok := byID[composite.ID]
+		if !ok {
+			// 对应的监控项已归档或被删除
+			continue
+		}
+
+		status, responseTime, message := compositeStatus(&composite, byID)
+		heartbeat := models.HeartBeat{
+			MonitorID:    composite.ID,
+			Status:       status,
+			ResponseTime: responseTime,
+			Message:      message,
+			CreatedAt:    now,
+		}
+		if err := database.SaveHeartBeat(&heartbeat); err != nil {
+			log.Printf("保存组合监控项心跳失败 [%s]: %v", composite.Name, err)
+			continue
+		}
+
+		uptime := 0.0
+		if summary, err := database.GetUptimeSummary(composite.ID, now.Add(-24*time.Hour)); err == nil && summary.Uptime >= 0 {
+			uptime = summary.Uptime
+		}
+		if err := database.UpdateMonitorStatus(composite.ID, status, uptime, responseTime); err != nil {
+			log.Printf("更新组合监控项状态失败 [%s]: %v", composite.Name, err)
+			continue
+		}
+
+		monitor.Status = status
+		monitor.Uptime = uptime
+		monitor.ResponseTime = responseTime
+		evaluated = append(evaluated, monitor)
+	}
+	return evaluated
+}
+
+// compositeStatus 按规则计算组合监控项的状态
+// 维护中的成员不参与计算;规则仅在重试中的成员恢复后才能满足时为重试中
+// 响应时间取正常成员中的最大值
+func compositeStatus(composite *models.CompositeMonitor, monitors map[int]models.Monitor) (int, int, string) {
+	var total, up, pending int
+	var totalWeight, upWeight, pendingWeight float64
+	responseTime := 0
+	for _, member := range composite.Members {
+		monitor, ok := monitors[member.MonitorID]
+		if !ok || monitor.Status == models.StatusMaintenance {
+			continue
+		}
+		weight := member.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total++
+		totalWeight += weight
+		switch monitor.Status {
+		case models.StatusUp:
+			up++
+			upWeight += weight
+			responseTime = max(responseTime, monitor.ResponseTime)
+		case models.StatusPending:
+			pending++
+			pendingWeight += weight
+		}
+	}
+
+	if total == 0 {
+		if hasMaintenanceMember(composite, monitors) {
+			return models.StatusMaintenance, 0, "所有成员维护中"
+		}
+		return models.StatusPending, 0, "没有可用的成员"
+	}
+
+	satisfied := func(count int, weight float64) bool {
+		switch composite.Rule {
+		case models.CompositeAny:
+			return count >= 1
+		case models.CompositeAtLeast:
+			return count >= int(math.Ceil(composite.Threshold))
+		case models.CompositeWeighted:
+			return weight/totalWeight >= composite.Threshold
+		default:
+			return count == total
+		}
+	}
+
+	message := fmt.Sprintf("%d/%d 个成员正常", up, total)
+	if composite.Rule == models.CompositeWeighted {
+		message = fmt.Sprintf("正常权重占比 %.2f,阈值 %.2f", upWeight/totalWeight, composite.Threshold)
+	}
+
+	switch {
+	case satisfied(up, upWeight):
+		return models.StatusUp, responseTime, message
+	case pending > 0 && satisfied(up+pending, upWeight+pendingWeight):
+		return models.StatusPending, responseTime, message
+	default:
+		return models.StatusDown, responseTime, message
+	}
+}
+
+// hasMaintenanceMember 是否有成员处于维护中
+func hasMaintenanceMember(composite *models.CompositeMonitor, monitors map[int]models.Monitor) bool {
+	for _, member := range composite.Members {
+		if monitor, ok := monitors[member.MonitorID]; ok && monitor.Status == models.StatusMaintenance {
+			return true
+		}
+	}
+	return false
+}
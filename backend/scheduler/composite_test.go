@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"kuma-lite/backend/models"
+	"testing"
+)
+
+func TestCompositeStatus(t *testing.T) {
+	const (
+		down        = models.StatusDown
+		up          = models.StatusUp
+		pending     = models.StatusPending
+		maintenance = models.StatusMaintenance
+	)
+	// 成员监控项 ID 即下标加 1,响应时间为 ID*100
+	member := func(weights ...float64) []models.CompositeMember {
+		members := make([]models.CompositeMember, len(weights))
+		for i, weight := range weights {
+			members[i] = models.CompositeMember{MonitorID: i + 1, Weight: weight}
+		}
+		return members
+	}
+	tests := []struct {
+		name         string
+		rule         string
+		threshold    float64
+		members      []models.CompositeMember
+		statuses     []int
+		want         int
+		responseTime int
+	}{
+		{"all 全部正常", models.CompositeAll, 0, member(0, 0, 0), []int{up, up, up}, up, 300},
+		{"all 一个离线", models.CompositeAll, 0, member(0, 0, 0), []int{up, down, up}, down, 300},
+		{"all 一个重试中", models.CompositeAll, 0, member(0, 0), []int{up, pending}, pending, 100},
+		{"all 重试中也无法满足", models.CompositeAll, 0, member(0, 0, 0), []int{down, pending, up}, down, 300},
+		{"any 一个正常", models.CompositeAny, 0, member(0, 0, 0), []int{down, up, down}, up, 200},
+		{"any 全部离线", models.CompositeAny, 0, member(0, 0), []int{down, down}, down, 0},
+		{"at_least 满足", models.CompositeAtLeast, 2, member(0, 0, 0), []int{up, down, up}, up, 300},
+		{"at_least 等待重试", models.CompositeAtLeast, 2, member(0, 0, 0), []int{up, pending, down}, pending, 100},
+		{"at_least 不满足", models.CompositeAtLeast, 2, member(0, 0, 0), []int{up, down, down}, down, 100},
+		{"at_least 阈值向上取整", models.CompositeAtLeast, 1.5, member(0, 0, 0), []int{up, down, down}, down, 100},
+		{"weighted 满足", models.CompositeWeighted, 0.6, member(3, 1, 1), []int{up, down, down}, up, 100},
+		{"weighted 不满足", models.CompositeWeighted, 0.6, member(1, 1, 3), []int{up, up, down}, down, 200},
+		{"weighted 权重缺省为 1", models.CompositeWeighted, 0.5, member(0, 0), []int{up, down}, up, 100},
+		{"维护中的成员不参与计算", models.CompositeAll, 0, member(0, 0), []int{up, maintenance}, up, 100},
+		{"全部成员维护中", models.CompositeAll, 0, member(0, 0), []int{maintenance, maintenance}, maintenance, 0},
+		{"成员已归档", models.CompositeAll, 0, member(0, 0), nil, pending, 0},
+	}
+	for _, tt := range tests {
+		monitors := make(map[int]models.Monitor)
+		for i, status := range tt.statuses {
+			monitors[i+1] = models.Monitor{ID: i + 1, Status: status, ResponseTime: (i + 1) * 100}
+		}
+		composite := &models.CompositeMonitor{Rule: tt.rule, Threshold: tt.threshold, Members: tt.members}
+		status, responseTime, message := compositeStatus(composite, monitors)
+		if status != tt.want || responseTime != tt.responseTime {
+			t.Errorf("%s: 状态 %d,响应时间 %d,应为 %d, %d (%s)", tt.name, status, responseTime, tt.want, tt.responseTime, message)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"log"
+	"time"
+)
+
+// detectFlapping 统计滑动窗口内的状态切换次数,记录或结束监控项的抖动期间
+// 切换次数达到阈值时开始抖动,保持 FlapStable 没有切换后结束;应在同步故障事件之前调用
+func detectFlapping(monitors []models.Monitor, now time.Time) {
+	cfg := config.AppConfig
+	if cfg.FlapThreshold <= 0 {
+		return
+	}
+
+	ids := make([]int, 0, len(monitors))
+	for _, monitor := range monitors {
+		ids = append(ids, monitor.ID)
+	}
+	activity, err := database.GetFlapActivity(ids, now.Add(-cfg.FlapWindow))
+	if err != nil {
+		log.Printf("统计状态切换失败: %v", err)
+		return
+	}
+	open, err := database.GetOpenFlaps()
+	if err != nil {
+		log.Printf("获取抖动状态失败: %v", err)
+		return
+	}
+
+	for _, monitor := range monitors {
+		current := activity[monitor.ID]
+		stable := current.Changes == 0 || now.Sub(current.LastChange) >= cfg.FlapStable
+
+		flap, isOpen := open[monitor.ID]
+		switch {
+		case !isOpen && current.Changes >= cfg.FlapThreshold && !stable:
+			flap = models.FlapPeriod{
+				MonitorID: monitor.ID,
+				Changes:   current.Changes,
+				StartedAt: now,
+			}
+			if err := database.OpenFlap(&flap, monitor.Name, cfg.FlapWindow); err != nil {
+				log.Printf("记录抖动失败 [%s]: %v", monitor.Name, err)
+				continue
+			}
+			log.Printf("监控项状态频繁切换,暂停故障事件: [%s] %d 次", monitor.Name, current.Changes)
+		case isOpen && stable:
+			if err := database.CloseFlap(&flap, monitor.Name, now); err != nil {
+				log.Printf("结束抖动失败 [%s]: %v", monitor.Name, err)
+				continue
+			}
+			log.Printf("监控项状态恢复稳定: [%s] 当前%s", monitor.Name, models.StatusText(monitor.Status))
+		case isOpen && current.Changes > flap.Changes:
+			if err := database.UpdateFlapChanges(flap.ID, current.Changes); err != nil {
+				log.Printf("更新抖动记录失败 [%s]: %v", monitor.Name, err)
+			}
+		}
+	}
+}
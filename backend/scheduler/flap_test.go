@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"kuma-lite/backend/config"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupTestDB 使用临时数据库,cfg 为本次测试的配置
+func setupTestDB(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	prev := config.AppConfig
+	config.AppConfig = cfg
+	if err := database.InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		database.CloseDB()
+		config.AppConfig = prev
+	})
+}
+
+// TestDetectFlapping 窗口内切换达到阈值时开始抖动,切换更多时更新次数,保持 FlapStable 没有切换后结束
+func TestDetectFlapping(t *testing.T) {
+	setupTestDB(t, &config.Config{FlapThreshold: 3, FlapWindow: 30 * time.Minute, FlapStable: 10 * time.Minute})
+	monitor := models.Monitor{ID: 1, Name: "Web", Status: models.StatusUp}
+	if err := database.SaveMonitor(&monitor); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	minute := 0
+	// beats 每分钟写入一条心跳
+	beats := func(statuses ...int) {
+		t.Helper()
+		for _, status := range statuses {
+			minute++
+			heartbeat := &models.HeartBeat{MonitorID: 1, Status: status, CreatedAt: start.Add(time.Duration(minute) * time.Minute)}
+			if err := database.SaveHeartBeat(heartbeat); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	at := func(offset time.Duration) time.Time {
+		return start.Add(time.Duration(minute)*time.Minute + offset)
+	}
+	openFlap := func() *models.FlapPeriod {
+		t.Helper()
+		open, err := database.GetOpenFlaps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flap, ok := open[1]; ok {
+			return &flap
+		}
+		return nil
+	}
+	monitors := []models.Monitor{monitor}
+
+	beats(models.StatusUp, models.StatusDown, models.StatusUp)
+	detectFlapping(monitors, at(0))
+	if flap := openFlap(); flap != nil {
+		t.Fatalf("切换次数未达到阈值时不应开始抖动: %+v", flap)
+	}
+
+	beats(models.StatusDown)
+	detectFlapping(monitors, at(0))
+	flap := openFlap()
+	if flap == nil || flap.Changes != 3 {
+		t.Fatalf("切换 3 次后应开始抖动: %+v", flap)
+	}
+
+	beats(models.StatusUp, models.StatusDown)
+	detectFlapping(monitors, at(0))
+	if flap := openFlap(); flap == nil || flap.Changes != 5 {
+		t.Fatalf("切换更多时应更新次数: %+v", flap)
+	}
+
+	detectFlapping(monitors, at(9*time.Minute))
+	if openFlap() == nil {
+		t.Fatal("稳定时间不足 FlapStable 时不应结束抖动")
+	}
+	detectFlapping(monitors, at(10*time.Minute))
+	if flap := openFlap(); flap != nil {
+		t.Fatalf("稳定 FlapStable 后应结束抖动: %+v", flap)
+	}
+	flaps, err := database.GetFlaps(1, start, 10)
+	if err != nil || len(flaps) != 1 || flaps[0].EndedAt == nil || flaps[0].Changes != 5 {
+		t.Errorf("应有一个已结束的抖动期间: %+v, %v", flaps, err)
+	}
+}
@@ -1,18 +1,57 @@
 package scheduler
 
 import (
+	"errors"
 	"kuma-lite/backend/cache"
 	"kuma-lite/backend/config"
 	"kuma-lite/backend/database"
 	"kuma-lite/backend/fetcher"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/notify"
 	"log"
-	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+var (
+	// lastSuccessfulFetch 最近一次成功获取数据的时间(UnixNano)
+	lastSuccessfulFetch atomic.Int64
+	// staleMarked 是否已经因数据过期使缓存失效过,避免每次失败都清空缓存
+	staleMarked atomic.Bool
+	// startedAt 调度器启动时间,启动后尚未成功获取过数据时用于判断是否过期
+	startedAt = time.Now()
+)
+
+// LastSuccessfulFetch 最近一次成功获取数据的时间,尚未成功时返回零值
+func LastSuccessfulFetch() time.Time {
+	nanos := lastSuccessfulFetch.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// IsStale 数据是否已过期: 距离最近一次成功获取超过 STALE_THRESHOLD
+// 启动后从未成功获取时,从启动时间开始计算
+func IsStale() bool {
+	since := LastSuccessfulFetch()
+	if since.IsZero() {
+		since = startedAt
+	}
+	return time.Since(since) > config.AppConfig.StaleThreshold
+}
+
 // StartScheduler 启动定时任务
 func StartScheduler() {
 	cfg := config.AppConfig
+	startedAt = time.Now()
+
+	// 从数据库恢复最近一次成功获取的时间,避免重启后误判为过期
+	if last, err := database.GetLastSuccessfulFetch(); err != nil {
+		log.Printf("读取最近一次成功获取记录失败: %v", err)
+	} else if last != nil {
+		lastSuccessfulFetch.Store(last.StartedAt.UnixNano())
+	}
 
 	// 立即执行一次数据获取
 	go func() {
@@ -40,67 +79,146 @@ func StartScheduler() {
 	log.Printf("调度器已启动: 数据获取间隔 %v, 数据保留 %d 天", cfg.FetchInterval, cfg.DataRetentionDays)
 }
 
-// fetchAndStore 获取并存储数据
+// fetchAndStore 获取并存储数据,并记录本次获取的结果
 func fetchAndStore() {
 	log.Println("开始获取 Uptime Kuma 数据...")
 
-	// 获取状态页面和心跳数据
-	statusPage, heartbeatData, err := fetcher.FetchKumaData()
+	attempt := models.FetchAttempt{StartedAt: time.Now()}
+	err := syncKumaData(&attempt)
+	if errors.Is(err, fetcher.ErrCircuitOpen) {
+		// 熔断期间没有发起请求,不记录获取结果
+		log.Printf("跳过本次数据获取: %v", err)
+		markStale()
+		return
+	}
+
+	attempt.DurationMs = time.Since(attempt.StartedAt).Milliseconds()
+	attempt.Success = err == nil
+	if err != nil {
+		attempt.Error = truncateError(err.Error(), 500)
+	}
+	if saveErr := database.SaveFetchAttempt(&attempt); saveErr != nil {
+		log.Printf("保存数据获取记录失败: %v", saveErr)
+	}
+
 	if err != nil {
 		log.Printf("获取数据失败: %v", err)
+		markStale()
 		return
 	}
 
-	// 解析监控项（结合心跳数据）
-	monitors := fetcher.ParseMonitors(statusPage, heartbeatData)
+	log.Printf("数据获取成功: %d 个监控项", attempt.MonitorCount)
+	// 记录开始时间而不是结束时间,与数据库中的记录保持一致
+	lastSuccessfulFetch.Store(attempt.StartedAt.UnixNano())
+	staleMarked.Store(false)
 
-	// 收集当前的监控项ID列表
-	currentMonitorIDs := make([]int, 0, len(monitors))
-	for _, monitor := range monitors {
-		currentMonitorIDs = append(currentMonitorIDs, monitor.ID)
+	// 数据获取成功后，使本周期之前的缓存全部失效
+	cache.NextGeneration()
+}
+
+// markStale 数据刚变为过期时让缓存失效一次,使响应中的 stale 标记及时生效
+func markStale() {
+	if IsStale() && staleMarked.CompareAndSwap(false, true) {
+		log.Printf("数据已超过 %v 未更新,标记为过期", config.AppConfig.StaleThreshold)
+		cache.NextGeneration()
+	}
+}
+
+// syncKumaData 从 Kuma 获取数据并写入数据库,获取结果记入 attempt
+func syncKumaData(attempt *models.FetchAttempt) error {
+	// 获取状态页面和心跳数据
+	statusPage, heartbeatData, err := fetcher.FetchKumaData()
+	if err != nil {
+		return err
 	}
+	droppedBefore := fetcher.DroppedHeartBeats()
 
-	// 同步删除不存在的监控项
-	if err := database.SyncMonitors(currentMonitorIDs); err != nil {
-		log.Printf("同步删除监控项失败: %v", err)
+	// 解析监控项（结合心跳数据）
+	monitors := fetcher.ParseMonitors(statusPage, heartbeatData)
+
+	// 归档不存在的监控项,记录新增和移除
+	if err := database.SyncMonitors(monitors); err != nil {
+		log.Printf("同步监控项列表失败: %v", err)
 	}
 
 	// 保存监控项和心跳记录
+	// 没有心跳的监控项状态未知,只更新基本信息,也不参与故障事件判断
+	known := make([]models.Monitor, 0, len(monitors))
 	for _, monitor := range monitors {
+		if !fetcher.HasHeartBeats(monitor.ID, heartbeatData) {
+			if err := database.SaveMonitorMetadata(&monitor); err != nil {
+				log.Printf("保存监控项失败 [%s]: %v", monitor.Name, err)
+			}
+			continue
+		}
+
 		if err := database.SaveMonitor(&monitor); err != nil {
 			log.Printf("保存监控项失败 [%s]: %v", monitor.Name, err)
 			continue
 		}
+		known = append(known, monitor)
 
 		// 解析并保存心跳历史记录
-		if heartbeatData != nil {
-			heartbeats := fetcher.ParseHeartBeats(monitor.ID, heartbeatData)
-			for _, hb := range heartbeats {
-				if err := database.SaveHeartBeat(&hb); err != nil {
-					// 心跳记录可能重复，不打印错误
-					continue
-				}
+		heartbeats := fetcher.ParseHeartBeats(monitor.ID, heartbeatData)
+		for _, hb := range heartbeats {
+			if err := database.SaveHeartBeat(&hb); err != nil {
+				// 心跳记录可能重复，不打印错误
+				continue
 			}
 		}
 	}
 
-	log.Printf("数据获取成功: %d 个监控项", len(monitors))
+	// 根据本周期的成员状态计算组合监控项,与 Kuma 监控项一起参与故障事件判断
+	if heartbeatData != nil {
+		known = append(known, evaluateComposites(time.Now().UTC())...)
+	}
 
-	// 数据获取成功后，清空相关缓存以便下次请求时获取最新数据
-	cache.Delete("monitors")
-	cache.Delete("stats")
+	// 检测状态抖动,抖动中的监控项暂不创建或解决故障事件
+	detectFlapping(known, time.Now().UTC())
 
-	// 为每个监控项清空历史记录缓存
-	for _, monitor := range monitors {
-		// 清空 limit 模式的缓存(主页使用)
-		cache.Delete("history_" + strconv.Itoa(monitor.ID) + "_limit_100")
+	// 根据最新状态创建或解决故障事件,记录变化的通知
+	now := time.Now().UTC()
+	changes, err := database.SyncIncidents(known)
+	if err != nil {
+		log.Printf("同步故障事件失败: %v", err)
+	}
+	notify.QueueIncidentChanges(changes, now)
 
-		// 清空不同时间范围的历史记录缓存(详情页使用)
-		for _, hours := range []string{"1", "3", "6", "12", "24", "48", "168"} {
-			cacheKey := "history_" + strconv.Itoa(monitor.ID) + "_" + hours + "h"
-			cache.Delete(cacheKey)
-		}
+	// 记录维护的开始和结束,连同故障事件的变化发送给邮件订阅者
+	started, ended, err := database.SyncMaintenance(known, now)
+	if err != nil {
+		log.Printf("同步维护状态失败: %v", err)
+	}
+	notify.RecordStatusUpdates(changes, started, ended, now)
+	notify.ProcessSubscriptions()
+
+	// 对比同时段的历史响应时间,记录响应时间异常
+	detectAnomalies(known, time.Now().UTC())
+
+	// 计算 SLO 燃烧率,触发或结束告警
+	evaluateSLOs(time.Now().UTC())
+
+	// 记录到期的重复提醒和升级,在后台发送故障事件和告警的通知
+	notify.ProcessNotifications(now)
+
+	// 同步状态页公告
+	if err := database.SyncAnnouncement(fetcher.ParseAnnouncement(statusPage)); err != nil {
+		log.Printf("同步状态页公告失败: %v", err)
 	}
+
+	attempt.MonitorCount = len(monitors)
+	attempt.Heartbeats = heartbeatData != nil
+	attempt.DroppedHeartbeats = int(fetcher.DroppedHeartBeats() - droppedBefore)
+	return nil
+}
+
+// truncateError 截断过长的错误信息,按字符截断避免切断多字节字符
+func truncateError(msg string, max int) string {
+	runes := []rune(msg)
+	if len(runes) <= max {
+		return msg
+	}
+	return string(runes[:max])
 }
 
 // cleanOldData 清理旧数据
@@ -113,4 +231,44 @@ func cleanOldData() {
 	} else {
 		log.Println("旧数据清理完成")
 	}
+
+	if err := database.CleanOldFetchAttempts(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的数据获取记录失败: %v", err)
+	}
+
+	if err := database.CleanOldEvents(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧事件失败: %v", err)
+	}
+
+	if err := database.CleanOldAnomalies(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的响应时间异常失败: %v", err)
+	}
+
+	if err := database.CleanOldSLOAlerts(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的 SLO 告警失败: %v", err)
+	}
+
+	if err := database.CleanOldFlaps(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的抖动记录失败: %v", err)
+	}
+
+	if err := database.CleanOldNotificationDeliveries(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的通知发送记录失败: %v", err)
+	}
+
+	if err := database.CleanOldMaintenance(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的维护记录失败: %v", err)
+	}
+
+	if err := database.CleanOldStatusUpdates(cfg.DataRetentionDays); err != nil {
+		log.Printf("清理旧的状态更新失败: %v", err)
+	}
+
+	if err := database.CleanUnconfirmedSubscribers(time.Now().Add(-notify.SubscriptionConfirmTTL)); err != nil {
+		log.Printf("清理未确认的订阅者失败: %v", err)
+	}
+
+	if _, err := database.PurgeArchivedMonitors(cfg.ArchiveGraceDays); err != nil {
+		log.Printf("清理归档监控项失败: %v", err)
+	}
 }
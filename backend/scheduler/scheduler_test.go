@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"kuma-lite/backend/cache"
+	"kuma-lite/backend/config"
+	"testing"
+	"time"
+)
+
+// setStaleState 设置过期判断依赖的全局状态,测试结束后恢复
+func setStaleState(t *testing.T, threshold time.Duration, started, last time.Time) {
+	t.Helper()
+	prevConfig, prevStarted, prevLast := config.AppConfig, startedAt, lastSuccessfulFetch.Load()
+	t.Cleanup(func() {
+		config.AppConfig, startedAt = prevConfig, prevStarted
+		lastSuccessfulFetch.Store(prevLast)
+		staleMarked.Store(false)
+	})
+
+	config.AppConfig = &config.Config{StaleThreshold: threshold}
+	startedAt = started
+	lastSuccessfulFetch.Store(0)
+	if !last.IsZero() {
+		lastSuccessfulFetch.Store(last.UnixNano())
+	}
+	staleMarked.Store(false)
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		started time.Time
+		last    time.Time
+		want    bool
+	}{
+		{"最近成功", now.Add(-time.Hour), now.Add(-time.Minute), false},
+		{"成功超过阈值", now.Add(-time.Hour), now.Add(-10 * time.Minute), true},
+		{"刚启动尚未成功", now.Add(-time.Minute), time.Time{}, false},
+		{"启动后长时间未成功", now.Add(-10 * time.Minute), time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setStaleState(t, 5*time.Minute, tt.started, tt.last)
+			if got := IsStale(); got != tt.want {
+				t.Errorf("IsStale() = %v,应为 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMarkStaleInvalidatesOnce 数据刚过期时使缓存失效一次,之后的失败不再清空缓存,成功获取后重新计数
+func TestMarkStaleInvalidatesOnce(t *testing.T) {
+	cache.InitCache(cache.NewMemoryStore(time.Minute, 2*time.Minute))
+	values := cache.NewTyped[string]("test")
+	cached := func() bool {
+		_, ok := values.Get("key", cache.TagFetch)
+		return ok
+	}
+
+	setStaleState(t, 5*time.Minute, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+	values.Set("key", "value", time.Minute, cache.TagFetch)
+	markStale()
+	if !cached() {
+		t.Fatal("数据未过期时不应使缓存失效")
+	}
+
+	lastSuccessfulFetch.Store(time.Now().Add(-10 * time.Minute).UnixNano())
+	markStale()
+	if cached() {
+		t.Fatal("数据刚过期时应使缓存失效")
+	}
+
+	values.Set("key", "value", time.Minute, cache.TagFetch)
+	markStale()
+	if !cached() {
+		t.Fatal("已标记过期后不应再次使缓存失效")
+	}
+
+	staleMarked.Store(false)
+	markStale()
+	if cached() {
+		t.Fatal("重新计数后再次过期时应使缓存失效")
+	}
+}
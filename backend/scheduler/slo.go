@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"fmt"
+	"kuma-lite/backend/database"
+	"kuma-lite/backend/models"
+	"kuma-lite/backend/notify"
+	"log"
+	"time"
+)
+
+// evaluateSLOs 计算各 SLO 的燃烧率,按多窗口规则触发或结束告警
+// 同一 SLO 的同一规则在结束前只触发一次,告警的开始和结束记录为待发送的通知
+func evaluateSLOs(now time.Time) {
+	reports, err := database.GetSLOReports(now)
+	if err != nil {
+		log.Printf("计算 SLO 失败: %v", err)
+		return
+	}
+
+	for _, report := range reports {
+		open := make(map[string]models.SLOAlert, len(report.Alerts))
+		for _, alert := range report.Alerts {
+			open[alert.Rule] = alert
+		}
+
+		for _, rule := range models.SLOBurnRateRules {
+			long := database.SLOBurnRate(&report, rule.Long)
+			short := database.SLOBurnRate(&report, rule.Short)
+			firing := long >= rule.Threshold && short >= rule.Threshold
+
+			alert, isOpen := open[rule.Name]
+			switch {
+			case firing && !isOpen:
+				alert = models.SLOAlert{
+					SLOID:     report.ID,
+					Rule:      rule.Name,
+					BurnRate:  long,
+					StartedAt: now,
+				}
+				message := fmt.Sprintf("%s 规则: %s 燃烧率 %.1f,%s 燃烧率 %.1f,阈值 %.1f;剩余错误预算 %.1f%%",
+					rule.Name, models.FormatSLOWindow(rule.Long), long, models.FormatSLOWindow(rule.Short), short,
+					rule.Threshold, report.ErrorBudgetRemaining*100)
+				if err := database.OpenSLOAlert(&alert, &report, message); err != nil {
+					log.Printf("记录 SLO 告警失败 [%s]: %v", report.Name, err)
+					continue
+				}
+				log.Printf("SLO 错误预算消耗过快: [%s] %s", report.Name, message)
+				notify.QueueSLOAlert(models.NotificationSLOAlert, &alert, &report.SLO, now)
+			case !firing && isOpen:
+				if err := database.CloseSLOAlert(alert.ID, now); err != nil {
+					log.Printf("结束 SLO 告警失败 [%s]: %v", report.Name, err)
+					continue
+				}
+				log.Printf("SLO 燃烧率恢复: [%s] %s 规则", report.Name, rule.Name)
+				notify.QueueSLOAlert(models.NotificationSLOResolved, &alert, &report.SLO, now)
+			}
+		}
+	}
+}
@@ -61,7 +61,20 @@ const i18n = {
         
         // 其他
         group: '分组',
-        other: '其他'
+        other: '其他',
+        impactedBy: '受影响于',
+        flapping: '状态频繁切换',
+        
+        // 邮件订阅
+        subscribe: '订阅状态更新',
+        subscribeEmail: '邮箱地址',
+        subscribeSubmit: '订阅',
+        subscribeCancel: '取消',
+        subscribeAllGroups: '不选择分组时订阅所有分组,故障和维护开始、结束时发送邮件',
+        subscribeSent: '确认邮件已发送,点击邮件中的链接后完成订阅',
+        subscribeConfirmed: '订阅已确认',
+        unsubscribed: '已退订',
+        subscribeFailed: '操作失败'
     },
     en: {
         // Status
@@ -122,7 +135,20 @@ const i18n = {
         
         // Others
         group: 'Group',
-        other: 'Other'
+        other: 'Other',
+        impactedBy: 'Impacted by',
+        flapping: 'Flapping',
+        
+        // Subscriptions
+        subscribe: 'Subscribe to updates',
+        subscribeEmail: 'Email address',
+        subscribeSubmit: 'Subscribe',
+        subscribeCancel: 'Cancel',
+        subscribeAllGroups: 'Leave all groups unchecked to subscribe to every group. Emails are sent when incidents and maintenance start and end.',
+        subscribeSent: 'Confirmation email sent. Click the link in the email to finish subscribing.',
+        subscribeConfirmed: 'Subscription confirmed',
+        unsubscribed: 'Unsubscribed',
+        subscribeFailed: 'Request failed'
     }
 };
 
@@ -152,11 +178,18 @@ const app = createApp({
                 text: '',
                 x: 0,
                 y: 0
+            },
+            subscription: {
+                open: false,
+                email: '',
+                groups: [],
+                submitting: false,
+                notice: ''
             }
         };
     },
     computed: {
-        // 按组分类监控项，并按照 Kuma 原始配置的分组顺序排列
+        // 按组分类监控项，分组和组内顺序由后端按展示覆盖和 Kuma 配置排好
         groupedMonitors() {
             // 先过滤搜索结果
             let filteredMonitors = this.monitors;
@@ -170,7 +203,7 @@ const app = createApp({
             
             const groups = {};
             filteredMonitors.forEach(monitor => {
-                // 使用 Kuma API 返回的 group 字段
+                // 使用 API 返回的 group 字段(已应用展示覆盖)
                 const groupName = monitor.group || 'other';
                 if (!groups[groupName]) {
                     groups[groupName] = {
@@ -197,6 +230,17 @@ const app = createApp({
             return result;
         },
         
+        // 可订阅的分组(已应用展示覆盖),按展示顺序去重
+        groupNames() {
+            const names = [];
+            this.monitors.forEach(monitor => {
+                if (monitor.group && !names.includes(monitor.group)) {
+                    names.push(monitor.group);
+                }
+            });
+            return names;
+        },
+        
         // 翻译文本
         t() {
             return i18n[this.language] || i18n.zh;
@@ -232,6 +276,7 @@ const app = createApp({
             this.language = savedLanguage;
         }
         
+        this.handleSubscriptionLink();
         this.fetchData();
         this.startAutoRefresh();
     },
@@ -251,7 +296,7 @@ const app = createApp({
                 this.error = null;
 
                 // 获取监控列表
-                const monitorsRes = await axios.get('/api/monitors');
+                const monitorsRes = await axios.get('/api/monitors?sort=group');
                 if (monitorsRes.data.success) {
                     this.monitors = monitorsRes.data.data;
                     
@@ -758,9 +803,21 @@ const app = createApp({
         getStatusIconClass(status) {
             if (status === 1) return 'up';
             if (status === 2) return 'maintenance';
+            if (status === 4) return 'impacted';
+            if (status === 5) return 'degraded';
             return 'down';
         },
 
+        // 根因监控项名称(依赖离线导致本监控项离线)
+        impactedNames(monitor) {
+            return monitor.impactedBy
+                .map(id => {
+                    const root = this.monitors.find(m => m.id === id);
+                    return root ? root.name : '#' + id;
+                })
+                .join(', ');
+        },
+
         // 获取状态条类
         getStatusBarClass(item) {
             const status = typeof item === 'object' ? item.status : item;
@@ -973,6 +1030,44 @@ const app = createApp({
             this.showLanguageMenu = false;
         },
 
+        // 提交邮件订阅
+        async submitSubscription() {
+            this.subscription.submitting = true;
+            try {
+                await axios.post('/api/subscriptions', {
+                    email: this.subscription.email,
+                    groups: this.subscription.groups
+                });
+                this.subscription.notice = this.t.subscribeSent;
+                this.subscription.open = false;
+            } catch (err) {
+                this.subscription.notice = this.t.subscribeFailed + ': ' + (err.response?.data?.error || err.message);
+            } finally {
+                this.subscription.submitting = false;
+            }
+        },
+
+        // 处理邮件中的确认和退订链接(?confirm= 或 ?unsubscribe=),处理后从地址栏移除令牌
+        async handleSubscriptionLink() {
+            const params = new URLSearchParams(window.location.search);
+            const confirmToken = params.get('confirm');
+            const unsubscribeToken = params.get('unsubscribe');
+            if (!confirmToken && !unsubscribeToken) return;
+            window.history.replaceState(null, '', window.location.pathname);
+
+            try {
+                if (confirmToken) {
+                    await axios.post('/api/subscriptions/confirm', { token: confirmToken });
+                    this.subscription.notice = this.t.subscribeConfirmed;
+                } else {
+                    await axios.post('/api/subscriptions/unsubscribe?token=' + encodeURIComponent(unsubscribeToken));
+                    this.subscription.notice = this.t.unsubscribed;
+                }
+            } catch (err) {
+                this.subscription.notice = this.t.subscribeFailed + ': ' + (err.response?.data?.error || err.message);
+            }
+        },
+
         // 切换所有分组展开/收起（预留功能）
         toggleAllGroups() {
             // TODO: 实现分组展开/收起功能